@@ -0,0 +1,44 @@
+package z3
+
+/*
+#cgo CFLAGS: -I../../modules/z3
+#cgo LDFLAGS: -L../../modules/z3 -lz3
+#include "../../modules/z3/src/api/z3.h"
+*/
+import "C"
+
+// Returns the sort of arrays which map all values of the domain to values of the range.
+func (context *Context) ArraySort(domain, rangeSort *Sort) *Sort {
+	return compute(context, func() *Sort {
+		return context.wrapSort(
+			C.Z3_mk_array_sort(context.z3Context, domain.z3Sort, rangeSort.z3Sort),
+		)
+	}, domain, rangeSort)
+}
+
+// Returns the array of the domain where all elements are the value.
+func (context *Context) NewConstantArray(domain *Sort, value *AST) *AST {
+	return compute(context, func() *AST {
+		return context.wrapAST(
+			C.Z3_mk_const_array(context.z3Context, domain.z3Sort, value.z3AST),
+		)
+	}, domain, value)
+}
+
+// Returns the element of the array at the index.
+func Select(array, index *AST) *AST {
+	return binary(
+		func(context C.Z3_context, array, index C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_select(context, array, index)
+		}, array, index,
+	)
+}
+
+// Returns the array where the element at the index is replaced by the value.
+func Store(array, index, value *AST) *AST {
+	return ternary(
+		func(context C.Z3_context, array, index, value C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_store(context, array, index, value)
+		}, array, index, value,
+	)
+}
@@ -0,0 +1,81 @@
+package z3
+
+/*
+#cgo CFLAGS: -I../../modules/z3
+#cgo LDFLAGS: -L../../modules/z3 -lz3
+#include "../../modules/z3/src/api/z3.h"
+*/
+import "C"
+
+func (context *Context) BitVectorSort(width uint) *Sort {
+	return context.wrapSort(
+		C.Z3_mk_bv_sort(context.z3Context, C.uint(width)),
+	)
+}
+
+// Returns the bit-vector of the width whose value is the integer modulo 2^width.
+func IntegerToBitVector(width uint, operand *AST) *AST {
+	return unary(
+		func(context C.Z3_context, operand C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_int2bv(context, C.uint(width), operand)
+		}, operand,
+	)
+}
+
+// Returns the integer of the bit-vector which is read in two's complement if it is signed.
+func BitVectorToInteger(operand *AST, signed bool) *AST {
+	return unary(
+		func(context C.Z3_context, operand C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_bv2int(context, operand, C.bool(signed))
+		}, operand,
+	)
+}
+
+func BitwiseNot(operand *AST) *AST {
+	return unary(
+		func(context C.Z3_context, operand C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_bvnot(context, operand)
+		}, operand,
+	)
+}
+
+func BitwiseAnd(lhs, rhs *AST) *AST {
+	return binary(
+		func(context C.Z3_context, lhs, rhs C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_bvand(context, lhs, rhs)
+		}, lhs, rhs,
+	)
+}
+
+func BitwiseOr(lhs, rhs *AST) *AST {
+	return binary(
+		func(context C.Z3_context, lhs, rhs C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_bvor(context, lhs, rhs)
+		}, lhs, rhs,
+	)
+}
+
+func BitwiseXor(lhs, rhs *AST) *AST {
+	return binary(
+		func(context C.Z3_context, lhs, rhs C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_bvxor(context, lhs, rhs)
+		}, lhs, rhs,
+	)
+}
+
+func ShiftLeft(lhs, rhs *AST) *AST {
+	return binary(
+		func(context C.Z3_context, lhs, rhs C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_bvshl(context, lhs, rhs)
+		}, lhs, rhs,
+	)
+}
+
+// Shifts the bits to the right where the sign bit is copied into the vacated bits.
+func ArithmeticShiftRight(lhs, rhs *AST) *AST {
+	return binary(
+		func(context C.Z3_context, lhs, rhs C.Z3_ast) C.Z3_ast {
+			return C.Z3_mk_bvashr(context, lhs, rhs)
+		}, lhs, rhs,
+	)
+}
@@ -25,6 +25,9 @@ type Context struct {
 	// This is necessary as we used Z3_mk_context_rc with our own reference counting.
 	//   This reference counting is important when performing AST operations.
 	mutex sync.Mutex
+
+	// The tuple sorts by their names.
+	tuples map[string]*Sort
 }
 
 // Create a context using the given configuration.
@@ -40,6 +43,7 @@ func NewContext(config *Config) *Context {
 		// anymore. This idiom is similar to the one used in
 		// BDD (binary decision diagrams) packages such as CUDD.
 		z3Context: C.Z3_mk_context_rc(config.z3Config),
+		tuples:    map[string]*Sort{},
 	}
 
 	// Before GC of the context we want to delete the C unmanaged context object.
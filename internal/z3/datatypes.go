@@ -0,0 +1,61 @@
+package z3
+
+/*
+#cgo CFLAGS: -I../../modules/z3
+#cgo LDFLAGS: -L../../modules/z3 -lz3
+#include "../../modules/z3/src/api/z3.h"
+*/
+import "C"
+
+// Returns the tuple sort with the name and fields. Tuple sorts are distinct even if they have the same
+// fields and are therefore cached by their name such that all sorts with the same name are the same.
+func (context *Context) TupleSort(name string, fields []string, sorts []*Sort) *Sort {
+	symbol := context.NewStringSymbol(name)
+	names := make([]C.Z3_symbol, len(fields))
+	for idx := range fields {
+		names[idx] = context.NewStringSymbol(fields[idx]).z3Symbol
+	}
+	return compute(context, func() *Sort {
+		if sort, exists := context.tuples[name]; exists {
+			return sort
+		}
+		domain := make([]C.Z3_sort, len(sorts))
+		for idx := range sorts {
+			domain[idx] = sorts[idx].z3Sort
+		}
+		projections := make([]C.Z3_func_decl, len(sorts))
+		var constructor C.Z3_func_decl
+		var namesPointer *C.Z3_symbol
+		var domainPointer *C.Z3_sort
+		var projectionsPointer *C.Z3_func_decl
+		if len(sorts) > 0 {
+			namesPointer, domainPointer, projectionsPointer = &names[0], &domain[0], &projections[0]
+		}
+		sort := context.wrapSort(C.Z3_mk_tuple_sort(
+			context.z3Context, symbol.z3Symbol, C.uint(len(sorts)),
+			namesPointer, domainPointer, &constructor, projectionsPointer,
+		))
+		// The sort is kept alive for the lifetime of the context as it is cached.
+		C.Z3_inc_ref(context.z3Context, C.Z3_sort_to_ast(context.z3Context, sort.z3Sort))
+		context.tuples[name] = sort
+		return sort
+	}, sorts)
+}
+
+// Returns the constructor of the tuple sort which takes the values of the fields.
+func (sort *Sort) TupleConstructor() *FunctionDeclaration {
+	return compute(sort.context, func() *FunctionDeclaration {
+		return sort.context.wrapFunctionDeclaration(
+			C.Z3_get_tuple_sort_mk_decl(sort.context.z3Context, sort.z3Sort),
+		)
+	}, sort)
+}
+
+// Returns the projection of the tuple sort onto its field at the index.
+func (sort *Sort) TupleField(index int) *FunctionDeclaration {
+	return compute(sort.context, func() *FunctionDeclaration {
+		return sort.context.wrapFunctionDeclaration(
+			C.Z3_get_tuple_sort_field_decl(sort.context.z3Context, sort.z3Sort, C.uint(index)),
+		)
+	}, sort)
+}
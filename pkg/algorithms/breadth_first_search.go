@@ -2,6 +2,7 @@ package algorithms
 
 import "github.com/Brandhoej/gobion/pkg/structures"
 
+// Yields the nodes as they are discovered, starting with the roots, until yield returns false.
 func BFS[T any](
 	successors func(node T) []T,
 	visited func(state T) bool,
@@ -13,6 +14,10 @@ func BFS[T any](
 	for _, root := range roots {
 		node := structures.NewLinkedNode(nil, root)
 		queue.Enqueue(node)
+
+		if !yield(node) {
+			return
+		}
 	}
 
 	for !queue.IsEmpty() {
@@ -2,6 +2,7 @@ package algorithms
 
 import "github.com/Brandhoej/gobion/pkg/structures"
 
+// Yields the nodes as they are discovered, starting with the roots, until yield returns false.
 func DFS[T any](
 	successors func(node T) []T,
 	visited func(state T) bool,
@@ -13,6 +14,10 @@ func DFS[T any](
 	for _, root := range roots {
 		node := structures.NewLinkedNode(nil, root)
 		stack.Push(node)
+
+		if !yield(node) {
+			return
+		}
 	}
 
 	for !stack.IsEmpty() {
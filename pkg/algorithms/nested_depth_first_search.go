@@ -0,0 +1,82 @@
+package algorithms
+
+import "github.com/Brandhoej/gobion/pkg/structures"
+
+// Searches for a cycle through an accepting node reachable from the roots by the nested depth-first search
+// of Courcoubetis et al. The outer search explores the nodes and, when all successors of an accepting node
+// are explored, the inner search from it looks for a node on the path of the outer search. Nodes are the same
+// if they are equal such that the sets of visited nodes are represented by lists.
+//
+// If a cycle is found then the path to the first node of the cycle is returned as the prefix. The last node of
+// the cycle has the first node of the cycle as a successor.
+func NestedDFS[T any](
+	successors func(node T) []T,
+	accepting func(node T) bool,
+	equal func(lhs, rhs T) bool,
+	roots ...T,
+) (prefix, cycle []T, found bool) {
+	var path, outer, inner []T
+	contains := func(nodes []T, node T) int {
+		for idx := range nodes {
+			if equal(nodes[idx], node) {
+				return idx
+			}
+		}
+		return -1
+	}
+
+	var search func(node T) bool
+	search = func(node T) bool {
+		path = append(path, node)
+		outer = append(outer, node)
+		for _, successor := range successors(node) {
+			if contains(outer, successor) < 0 && search(successor) {
+				return true
+			}
+		}
+
+		if accepting(node) {
+			DFS(
+				successors,
+				func(node T) bool {
+					return contains(inner, node) >= 0
+				},
+				func(linked structures.LinkedNode[T]) bool {
+					// The accepting node itself is on the path but must be reached by a successor.
+					if linked.Parent == nil {
+						return true
+					}
+					start := contains(path, linked.Data)
+					if start < 0 {
+						inner = append(inner, linked.Data)
+						return true
+					}
+
+					// The inner path goes from the accepting node, which is the last on the path, back to the start.
+					back := linked.Array()
+					prefix = append(prefix, path[:start]...)
+					cycle = append(cycle, path[start:]...)
+					for idx := len(back) - 2; idx > 0; idx-- {
+						cycle = append(cycle, back[idx])
+					}
+					found = true
+					return false
+				},
+				node,
+			)
+			if found {
+				return true
+			}
+		}
+
+		path = path[:len(path)-1]
+		return false
+	}
+
+	for _, root := range roots {
+		if contains(outer, root) < 0 && search(root) {
+			return prefix, cycle, true
+		}
+	}
+	return nil, nil, false
+}
@@ -33,6 +33,10 @@ func (automaton *Automaton[L, E]) Locations(yield func(symbols.Symbol, L) bool)
 	automaton.graph.Vertices(yield)
 }
 
+func (automaton *Automaton[L, E]) Edges(yield func(E) bool) {
+	automaton.graph.Edges(yield)
+}
+
 func (automaton *Automaton[L, E]) Outgoing(location symbols.Symbol) (edges []E) {
 	return automaton.graph.From(location)
 }
@@ -7,6 +7,7 @@ import (
 
 type LocationConfig struct {
 	invariant Invariant
+	urgency   Urgency
 }
 
 type LocationConfiguration func(config *LocationConfig)
@@ -27,6 +28,12 @@ func WithInvariant(invariant Invariant) LocationConfiguration {
 	}
 }
 
+func WithUrgency(urgency Urgency) LocationConfiguration {
+	return func(config *LocationConfig) {
+		config.urgency = urgency
+	}
+}
+
 type EdgeConfig struct {
 	guard  Guard
 	update Update
@@ -75,6 +82,7 @@ func NewAutomatonBuilder() *SymbolicAutomatonBuilder {
 func (builder *SymbolicAutomatonBuilder) AddLocation(name string, configs ...LocationConfiguration) symbols.Symbol {
 	config := NewLocationConfig(configs...)
 	location := NewLocation(name, config.invariant)
+	location.urgency = config.urgency
 	symbol := symbols.Symbol(builder.factory.Next())
 	key := builder.locations.Add(location, symbol)
 	return key
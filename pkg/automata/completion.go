@@ -11,3 +11,22 @@ var DirectedCompletion = func(destination symbols.Symbol) func(symbols.Symbol, G
 		return destination
 	}
 }
+
+// Returns the guard which is satisfied when none of the guards are satisfied.
+func missingGuard(guards ...Guard) Guard {
+	// The disjunction is the disjunction of all guard conditions.
+	// If there are not outgoing edges then we assume a false edge condition.
+	// This means that if there are no edge a true condition is the missing.
+	var disjunction Guard
+	if len(guards) > 0 {
+		disjunction = guards[0]
+		if len(guards) > 1 {
+			// We do "guards[0]." and not "disjunction." to not have an additional "depth".
+			disjunction = guards[0].Disjunction(guards[1:]...)
+		}
+	} else {
+		disjunction = NewFalseGuard()
+	}
+
+	return disjunction.Negation()
+}
@@ -0,0 +1,98 @@
+package automata
+
+import (
+	"fmt"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// Returns true if the automata can be composed in parallel. That is, the outputs are disjoint.
+func (lhs IOAutomaton) IsComposable(rhs IOAutomaton) bool {
+	for _, output := range lhs.outputs {
+		if rhs.IsOutput(output) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if the action is either an input or output of the automaton.
+func (automaton IOAutomaton) InAlphabet(action Action) bool {
+	return automaton.IsInput(action) || automaton.IsOutput(action)
+}
+
+// Returns the parallel composition "lhs ∥ rhs" of the two automata. Actions in the alphabet
+// of both automata synchronise whilst the remaining actions interleave. When an output of one
+// synchronises with an input of the other then it is an output of the composition. The inputs of
+// the composition are the inputs which are not an output of either automaton. An error is returned
+// if the automata are not composable.
+func (lhs IOAutomaton) Composition(rhs IOAutomaton) (*IOAutomaton, error) {
+	if !lhs.IsComposable(rhs) {
+		return nil, fmt.Errorf("the outputs of the composed automata are not disjoint")
+	}
+
+	builder := NewIOAutomatonBuilder()
+	for _, input := range lhs.inputs {
+		if !rhs.IsOutput(input) {
+			builder.AddInputs(input)
+		}
+	}
+	for _, input := range rhs.inputs {
+		if !lhs.InAlphabet(input) {
+			builder.AddInputs(input)
+		}
+	}
+	builder.AddOutputs(lhs.outputs...)
+	builder.AddOutputs(rhs.outputs...)
+
+	product := newProduct(builder, lhs, rhs)
+	product.Explore(func(source symbols.Symbol, pair locationPair) {
+		lhsEdges := lhs.Automaton.Outgoing(pair.lhs)
+		rhsEdges := rhs.Automaton.Outgoing(pair.rhs)
+
+		// The lhs moves on its own whilst the rhs stays in its location.
+		for _, edge := range lhsEdges {
+			if rhs.InAlphabet(edge.action) {
+				continue
+			}
+			destination := product.Location(locationPair{edge.destination, pair.rhs})
+			builder.AddEdge(
+				source, edge.action, destination,
+				WithGuard(edge.guard), WithUpdate(edge.update),
+			)
+		}
+
+		// The rhs moves on its own whilst the lhs stays in its location.
+		for _, edge := range rhsEdges {
+			if lhs.InAlphabet(edge.action) {
+				continue
+			}
+			destination := product.Location(locationPair{pair.lhs, edge.destination})
+			builder.AddEdge(
+				source, edge.action, destination,
+				WithGuard(edge.guard), WithUpdate(edge.update),
+			)
+		}
+
+		// Both move on a shared action.
+		for _, lhsEdge := range lhsEdges {
+			if !rhs.InAlphabet(lhsEdge.action) {
+				continue
+			}
+			for _, rhsEdge := range rhsEdges {
+				if lhsEdge.action != rhsEdge.action {
+					continue
+				}
+				destination := product.Location(locationPair{lhsEdge.destination, rhsEdge.destination})
+				builder.AddEdge(
+					source, lhsEdge.action, destination,
+					WithGuard(lhsEdge.guard.Conjunction(rhsEdge.guard)),
+					WithUpdate(lhsEdge.update.Conjunction(rhsEdge.update)),
+				)
+			}
+		}
+	})
+
+	composition := builder.Build()
+	return &composition, nil
+}
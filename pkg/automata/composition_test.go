@@ -0,0 +1,121 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Composition(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	coin := Action(symbolsMap.Insert("coin"))
+	coffee := Action(symbolsMap.Insert("coffee"))
+	publication := Action(symbolsMap.Insert("publication"))
+
+	machine := NewIOAutomatonBuilder()
+	machine.AddInputs(coin)
+	machine.AddOutputs(coffee)
+	idle := machine.AddInitial("idle")
+	brewing := machine.AddLocation("brewing")
+	machine.AddEdge(idle, coin, brewing)
+	machine.AddEdge(brewing, coffee, idle)
+
+	researcher := NewIOAutomatonBuilder()
+	researcher.AddInputs(coffee)
+	researcher.AddOutputs(coin, publication)
+	thinking := researcher.AddInitial("thinking")
+	waiting := researcher.AddLocation("waiting")
+	researcher.AddEdge(thinking, coin, waiting)
+	researcher.AddEdge(waiting, coffee, thinking)
+	researcher.AddLoop(thinking, publication)
+
+	// Act
+	composition, err := machine.Build().Composition(researcher.Build())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, composition.Inputs())
+	assert.ElementsMatch(t, []Action{coffee, coin, publication}, composition.Outputs())
+
+	locations := 0
+	composition.Locations(func(symbols.Symbol, Location) bool {
+		locations += 1
+		return true
+	})
+	assert.Equal(t, 2, locations)
+
+	initial := composition.Initial()
+	assert.Len(t, composition.Outgoing(initial, coin), 1)
+	assert.Len(t, composition.Outgoing(initial, publication), 1)
+	assert.Len(t, composition.Outgoing(initial, coffee), 0)
+}
+
+func Test_CompositionIsComposable(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	coin := Action(symbolsMap.Insert("coin"))
+
+	lhs := NewIOAutomatonBuilder()
+	lhs.AddOutputs(coin)
+	lhs.AddInitial("lhs")
+
+	rhs := NewIOAutomatonBuilder()
+	rhs.AddOutputs(coin)
+	rhs.AddInitial("rhs")
+
+	// Act
+	composable := lhs.Build().IsComposable(rhs.Build())
+	composition, err := lhs.Build().Composition(rhs.Build())
+
+	// Assert
+	assert.False(t, composable)
+	assert.Nil(t, composition)
+	assert.EqualError(t, err, "the outputs of the composed automata are not disjoint")
+}
+
+func Test_CompositionOutgoing(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x := symbolsMap.Insert("x")
+	variables := language.NewVariablesMap()
+	variables.Declare(x, language.IntegerSort)
+	request := Action(symbolsMap.Insert("request"))
+	grant := Action(symbolsMap.Insert("grant"))
+
+	client := NewIOAutomatonBuilder()
+	client.AddOutputs(request)
+	client.AddInputs(grant)
+	ready := client.AddInitial("ready")
+	client.AddLoop(ready, request)
+	client.AddLoop(ready, grant)
+
+	server := NewIOAutomatonBuilder()
+	server.AddInputs(request)
+	server.AddOutputs(grant)
+	open := server.AddInitial("open")
+	server.AddLoop(open, request)
+	server.AddLoop(open, grant, WithGuard(
+		NewGuard(
+			language.NewBinary(
+				language.NewVariable(x), language.GreaterThan, language.NewInteger(0),
+			),
+		),
+	))
+	composition, err := client.Build().Composition(server.Build())
+	assert.NoError(t, err)
+	interpreter := NewInterpreter(context, variables)
+	system := NewTransitionSystem(composition.Symbolic(), interpreter)
+	valuations := language.NewValuationsMap()
+	valuations.Assign(x, language.NewInteger(0))
+
+	// Act
+	successors := system.Outgoing(system.Initial(valuations))
+
+	// Assert
+	assert.Len(t, successors, 1)
+}
@@ -0,0 +1,87 @@
+package automata
+
+import (
+	"errors"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// Returns true if both automata have the same inputs and outputs.
+func (lhs IOAutomaton) HasSameAlphabet(rhs IOAutomaton) bool {
+	for _, input := range lhs.inputs {
+		if !rhs.IsInput(input) {
+			return false
+		}
+	}
+	for _, input := range rhs.inputs {
+		if !lhs.IsInput(input) {
+			return false
+		}
+	}
+	for _, output := range lhs.outputs {
+		if !rhs.IsOutput(output) {
+			return false
+		}
+	}
+	for _, output := range rhs.outputs {
+		if !lhs.IsOutput(output) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the conjunction "lhs ∧ rhs" of two specifications with the same alphabet.
+// Both automata synchronise on every action where guards are conjoined and invariants
+// are intersected. Product locations with an unsatisfiable invariant are inconsistent
+// and are replaced by a single error location with a false invariant. Edges which
+// can never be taken because of an unsatisfiable guard are pruned. An error is returned if
+// the alphabets differ.
+func (lhs IOAutomaton) Conjunction(rhs IOAutomaton, interpreter *Interpreter) (*IOAutomaton, error) {
+	if !lhs.HasSameAlphabet(rhs) {
+		return nil, errors.New("the conjoined automata do not have the same inputs and outputs")
+	}
+
+	builder := NewIOAutomatonBuilder()
+	builder.AddInputs(lhs.inputs...)
+	builder.AddOutputs(lhs.outputs...)
+
+	product := newProduct(builder, lhs, rhs)
+	product.consistent = func(invariant Invariant) bool {
+		return interpreter.IsSatisfiable(invariant.condition)
+	}
+	product.Explore(func(source symbols.Symbol, pair locationPair) {
+		location, _ := builder.locations.Vertex(source)
+		for _, action := range lhs.Actions() {
+			for _, lhsEdge := range lhs.Outgoing(pair.lhs, action) {
+				for _, rhsEdge := range rhs.Outgoing(pair.rhs, action) {
+					guard := lhsEdge.guard.Conjunction(rhsEdge.guard)
+					enabled := guard.Conjunction(NewGuard(location.invariant.condition))
+					if !enabled.IsSatisfiable(interpreter) {
+						continue
+					}
+
+					destination := product.Location(locationPair{lhsEdge.destination, rhsEdge.destination})
+					builder.AddEdge(
+						source, action, destination,
+						WithGuard(guard),
+						WithUpdate(lhsEdge.update.Conjunction(rhsEdge.update)),
+					)
+				}
+			}
+		}
+	})
+
+	conjunction := builder.Build()
+	return &conjunction, nil
+}
+
+// Returns the conjunction "lhs ∧ rhs" of two timed specifications with the same alphabet.
+// The clocks of the conjunction are the union of the clocks of both specifications.
+func (lhs TIOAutomaton) Conjunction(rhs TIOAutomaton, interpreter *Interpreter) (*TIOAutomaton, error) {
+	conjunction, err := lhs.IOAutomaton.Conjunction(rhs.IOAutomaton, interpreter)
+	if err != nil {
+		return nil, err
+	}
+	return NewTIOAutomaton(*conjunction, unionClocks(lhs.clocks, rhs.clocks)), nil
+}
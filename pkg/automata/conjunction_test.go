@@ -0,0 +1,164 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Conjunction(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x := symbolsMap.Insert("x")
+	variables := language.NewVariablesMap()
+	variables.Declare(x, language.IntegerSort)
+	request := Action(symbolsMap.Insert("request"))
+	grant := Action(symbolsMap.Insert("grant"))
+	interpreter := NewInterpreter(context, variables)
+
+	lhs := NewIOAutomatonBuilder()
+	lhs.AddInputs(request)
+	lhs.AddOutputs(grant)
+	lhsIdle := lhs.AddInitial("idle")
+	lhsBusy := lhs.AddLocation("busy", WithInvariant(
+		NewInvariant(
+			language.NewBinary(language.NewVariable(x), language.LessThanEqual, language.NewInteger(5)),
+		),
+	))
+	lhs.AddEdge(lhsIdle, request, lhsBusy)
+	lhs.AddLoop(lhsIdle, grant, WithGuard(
+		NewGuard(
+			language.NewBinary(language.NewVariable(x), language.GreaterThan, language.NewInteger(3)),
+		),
+	))
+
+	rhs := NewIOAutomatonBuilder()
+	rhs.AddInputs(request)
+	rhs.AddOutputs(grant)
+	rhsIdle := rhs.AddInitial("idle")
+	rhsBusy := rhs.AddLocation("busy", WithInvariant(
+		NewInvariant(
+			language.NewBinary(language.NewVariable(x), language.GreaterThanEqual, language.NewInteger(10)),
+		),
+	))
+	rhs.AddEdge(rhsIdle, request, rhsBusy)
+	rhs.AddLoop(rhsIdle, grant, WithGuard(
+		NewGuard(
+			language.NewBinary(language.NewVariable(x), language.LessThan, language.NewInteger(2)),
+		),
+	))
+
+	// Act
+	conjunction, err := lhs.Build().Conjunction(rhs.Build(), interpreter)
+
+	// Assert
+	assert.NoError(t, err)
+	initial := conjunction.Initial()
+	assert.Empty(t, conjunction.Outgoing(initial, grant))
+	requests := conjunction.Outgoing(initial, request)
+	if assert.Len(t, requests, 1) {
+		location, exists := conjunction.Location(requests[0].Destination())
+		assert.True(t, exists)
+		assert.Equal(t, "Error", location.name)
+		assert.Equal(t, language.NewFalse(), location.invariant.condition)
+	}
+}
+
+func Test_TimedConjunction(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	y, z := symbolsMap.Insert("y"), symbolsMap.Insert("z")
+	request := Action(symbolsMap.Insert("request"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	lhsClocks := language.NewClocksMap(reference)
+	lhsClocks.Declare(y)
+	lhs := NewIOAutomatonBuilder()
+	lhs.AddInputs(request)
+	lhsInitial := lhs.AddInitial("initial", WithInvariant(
+		NewInvariant(language.NewClockConstraint(y, reference, zones.NewRelation(5, zones.Weak))),
+	))
+	lhs.AddLoop(lhsInitial, request)
+
+	rhsClocks := language.NewClocksMap(reference)
+	rhsClocks.Declare(z)
+	rhs := NewIOAutomatonBuilder()
+	rhs.AddInputs(request)
+	rhsInitial := rhs.AddInitial("initial", WithInvariant(
+		NewInvariant(language.NewClockConstraint(z, reference, zones.NewRelation(3, zones.Weak))),
+	))
+	rhs.AddLoop(rhsInitial, request)
+
+	// Act
+	conjunction, err := NewTIOAutomaton(lhs.Build(), lhsClocks).Conjunction(
+		*NewTIOAutomaton(rhs.Build(), rhsClocks), interpreter,
+	)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, zones.Clock(3), conjunction.Clocks().Dimensions())
+	_, exists := conjunction.Clocks().Lookup(y)
+	assert.True(t, exists)
+	_, exists = conjunction.Clocks().Lookup(z)
+	assert.True(t, exists)
+	assert.Len(t, conjunction.Outgoing(conjunction.Initial(), request), 1)
+}
+
+func Test_ConjunctionDifferentAlphabets(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	request := Action(symbolsMap.Insert("request"))
+	grant := Action(symbolsMap.Insert("grant"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	lhs := NewIOAutomatonBuilder()
+	lhs.AddInputs(request)
+	lhs.AddOutputs(grant)
+	lhs.AddInitial("idle")
+
+	rhs := NewIOAutomatonBuilder()
+	rhs.AddInputs(request, grant)
+	rhs.AddInitial("idle")
+
+	// Act
+	conjunction, err := lhs.Build().Conjunction(rhs.Build(), interpreter)
+
+	// Assert
+	assert.Nil(t, conjunction)
+	assert.EqualError(t, err, "the conjoined automata do not have the same inputs and outputs")
+}
+
+func Test_UnionClocks(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	a, b, c, d := symbolsMap.Insert("a"), symbolsMap.Insert("b"), symbolsMap.Insert("c"), symbolsMap.Insert("d")
+	lhs := language.NewClocksMap(reference)
+	lhs.Declare(c)
+	lhs.Declare(a)
+	rhs := language.NewClocksMap(reference)
+	rhs.Declare(d)
+	rhs.Declare(a)
+	rhs.Declare(b)
+
+	for run := 0; run < 16; run++ {
+		// Act
+		union := unionClocks(lhs, rhs)
+
+		// Assert
+		assert.Equal(t, zones.Clock(5), union.Dimensions())
+		for clock, symbol := range []symbols.Symbol{reference, c, a, d, b} {
+			actual, exists := union.Lookup(symbol)
+			assert.True(t, exists)
+			assert.Equal(t, zones.Clock(clock), actual)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package dsl
+
+import (
+	"fmt"
+
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+type edge struct {
+	source, destination symbols.Symbol
+	action              automata.Action
+	hasAction           bool
+	guard, update       language.Expression
+}
+
+// An automaton defined in the textual language. Clock constraints are written as "x - y ~ n" where
+// the reference clock is "0", variables are assigned as "v' := e" and clocks as "x := n", for example:
+//
+//	clock x;
+//	bool on;
+//	input press;
+//	output flash;
+//	initial location off;
+//	location lit invariant x - 0 ≤ 5;
+//	edge off -> lit press? do x := 0; on' := true;
+//	edge lit -> off flash! when x - 0 ≥ 3;
+//
+// A model starting with "template Lamp(x : clock, press : input);" is a template whose parameters are declared
+// as the clocks, variables and actions of their kinds. Functions are declared as "function int f(int x) {return x + 1};".
+type Model struct {
+	symbols      symbols.Store[any]
+	template     bool
+	name         string
+	parameters   []automata.Parameter
+	declarations map[string]declaration
+	variables    *language.VariablesMap
+	functions    *language.FunctionsMap
+	clocks       *language.ClocksMap
+	reference    symbols.Symbol
+	inputs       []automata.Action
+	outputs      []automata.Action
+	initial      string
+	keys         map[string]symbols.Symbol
+	edges        []edge
+	symbolic     *automata.SymbolicAutomatonBuilder
+	io           *automata.IOAutomatonBuilder
+}
+
+// Parses the text as an automaton where all names are registered as symbols in the store.
+func Parse(text string, store symbols.Store[any]) (*Model, error) {
+	model := &Model{
+		symbols:      store,
+		declarations: map[string]declaration{},
+		variables:    language.NewVariablesMap(),
+		functions:    language.NewFunctionsMap(),
+		reference:    store.Insert("0"),
+		keys:         map[string]symbols.Symbol{},
+		symbolic:     automata.NewAutomatonBuilder(),
+		io:           automata.NewIOAutomatonBuilder(),
+	}
+	model.clocks = language.NewClocksMap(model.reference)
+
+	if err := model.parse(text, (*parser).declarations); err != nil {
+		return nil, err
+	}
+	if model.initial == "" {
+		return nil, fmt.Errorf("the automaton has no initial location")
+	}
+
+	model.io.AddInputs(model.inputs...)
+	model.io.AddOutputs(model.outputs...)
+	for _, edge := range model.edges {
+		configs := []automata.EdgeConfiguration{
+			automata.WithGuard(automata.NewGuard(edge.guard)),
+			automata.WithUpdate(automata.NewUpdate(edge.update)),
+		}
+		model.symbolic.AddEdge(edge.source, edge.destination, configs...)
+		if edge.hasAction {
+			model.io.AddEdge(edge.source, edge.action, edge.destination, configs...)
+		}
+	}
+	return model, nil
+}
+
+func (model *Model) addLocation(name string, invariant language.Expression) {
+	add := model.symbolic.AddLocation
+	addIO := model.io.AddLocation
+	if model.initial == name {
+		add, addIO = model.symbolic.AddInitial, model.io.AddInitial
+	}
+
+	config := automata.WithInvariant(automata.NewInvariant(invariant))
+	key := add(name, config)
+	if addIO(name, config) != key {
+		panic("The builders must assign the same keys to the locations")
+	}
+	model.keys[name] = key
+}
+
+// Returns the declared functions.
+func (model *Model) Functions() *language.FunctionsMap {
+	return model.functions
+}
+
+// Returns the sorts of the declared variables.
+func (model *Model) Variables() *language.VariablesMap {
+	return model.variables
+}
+
+// Returns the declared clocks including the reference clock "0".
+func (model *Model) Clocks() *language.ClocksMap {
+	return model.clocks
+}
+
+// Returns the key of the location with the name in all the automata of the model.
+func (model *Model) Location(name string) (symbols.Symbol, bool) {
+	key, exists := model.keys[name]
+	return key, exists
+}
+
+// Returns the automaton without the actions of the edges.
+func (model *Model) Automaton() automata.SymbolicAutomaton {
+	return model.symbolic.Build()
+}
+
+// Returns the I/O automaton of the model. An error is returned if the model has edges but declares no actions.
+func (model *Model) IOAutomaton() (automata.IOAutomaton, error) {
+	if len(model.inputs)+len(model.outputs) == 0 && len(model.edges) > 0 {
+		return automata.IOAutomaton{}, fmt.Errorf("the edges of the automaton have no actions")
+	}
+	return model.io.Build(), nil
+}
+
+func (model *Model) TIOAutomaton() (*automata.TIOAutomaton, error) {
+	automaton, err := model.IOAutomaton()
+	if err != nil {
+		return nil, err
+	}
+	return automata.NewTIOAutomaton(automaton, model.clocks), nil
+}
+
+// Returns the template declared by the model where the local variables are the variables which are not parameters.
+func (model *Model) Template() (*automata.Template, error) {
+	if !model.template {
+		return nil, fmt.Errorf("the model is not a template")
+	}
+	automaton, err := model.TIOAutomaton()
+	if err != nil {
+		return nil, err
+	}
+	return automata.NewTemplate(model.name, automaton, model.variables, model.parameters...), nil
+}
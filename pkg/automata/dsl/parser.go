@@ -0,0 +1,338 @@
+package dsl
+
+import (
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+type declarationKind uint16
+
+const (
+	variableDeclaration = declarationKind(iota)
+	clockDeclaration
+	inputDeclaration
+	outputDeclaration
+	functionDeclaration
+)
+
+type declaration struct {
+	kind   declarationKind
+	symbol symbols.Symbol
+}
+
+// The words which cannot be used as names in addition to the keywords of expressions.
+var keywords = map[string]bool{
+	"clock": true, "int": true, "bool": true, "input": true, "output": true,
+	"initial": true, "location": true, "invariant": true, "edge": true,
+	"when": true, "do": true, "template": true, "struct": true, "function": true,
+}
+
+// A parser of the declarations of models where the expressions are parsed by the language parser.
+type parser struct {
+	*language.Parser
+	model *Model
+}
+
+// Parses the text by the rule and returns the ParseError if any.
+func (model *Model) parse(text string, rule func(parser *parser)) error {
+	base, err := language.NewParser(text, model.symbols, model.clocks)
+	if err != nil {
+		return err
+	}
+	parser := &parser{
+		Parser: base,
+		model:  model,
+	}
+	base.SetResolver(parser.resolve)
+	base.SetVariables(model.variables)
+	base.SetFunctions(model.functions)
+	return base.Parse(func(*language.Parser) {
+		rule(parser)
+	})
+}
+
+// Returns the next token if it is an identifier which is not a keyword.
+func (parser *parser) expectIdentifier() language.Token {
+	token := parser.ExpectIdentifier()
+	if keywords[token.Text] {
+		parser.Fail(token, "expected an identifier but found %s", token)
+	}
+	return token
+}
+
+// Returns the next token if it is an identifier or a quoted name.
+func (parser *parser) expectName() language.Token {
+	if parser.Peek().Kind == language.NameToken {
+		return parser.Next()
+	}
+	return parser.expectIdentifier()
+}
+
+func (parser *parser) lookup(token language.Token) declaration {
+	declaration, exists := parser.model.declarations[token.Text]
+	if !exists {
+		parser.Fail(token, "undeclared identifier \"%s\"", token.Text)
+	}
+	return declaration
+}
+
+// Resolves the identifiers of expressions and statements which must be variables, clocks or called functions.
+func (parser *parser) resolve(token language.Token) symbols.Symbol {
+	declaration := parser.lookup(token)
+	if declaration.kind == inputDeclaration || declaration.kind == outputDeclaration {
+		parser.Fail(token, "the action \"%s\" cannot be used in an expression", token.Text)
+	}
+	if declaration.kind == functionDeclaration && !parser.Is("(") {
+		parser.Fail(token, "the function \"%s\" must be called", token.Text)
+	}
+	return declaration.symbol
+}
+
+// Returns true if the token starts a declaration of the model.
+func (parser *parser) isDeclaration(token language.Token) bool {
+	if token.Kind == language.EndOfInputToken {
+		return true
+	}
+	if token.Kind != language.IdentifierToken {
+		return false
+	}
+	switch token.Text {
+	case "clock", "int", "bool", "struct", "input", "output", "initial", "location", "edge", "template", "function":
+		return true
+	}
+	return false
+}
+
+// Models consist of declarations of variables, clocks, actions, locations and edges
+// where the expressions and sequences are those of the language parser:
+//
+//	model       := {declaration}
+//	declaration := sort variable {"," variable} ";"
+//	             | ("clock" | "input" | "output") identifier {"," identifier} ";"
+//	             | "template" identifier "(" [parameter {"," parameter}] ")" ";"
+//	             | "function" sort identifier function ";"
+//	             | ["initial"] "location" name ["invariant" expression] ";"
+//	             | "edge" name "->" name [identifier ("?" | "!")] ["when" expression] ["do" sequence] ";"
+//	parameter   := identifier ":" ("int" [bounds] | "bool" | "clock" | "input" | "output")
+//	variable    := identifier dimensions
+//	name        := identifier | "\"" {character} "\""
+//
+// The sorts, dimensions and functions are those of the language parser.
+func (parser *parser) declarations() {
+	for parser.Peek().Kind != language.EndOfInputToken {
+		parser.declaration()
+	}
+}
+
+func (parser *parser) declaration() {
+	token := parser.Peek()
+	switch token.Text {
+	case "clock", "int", "bool", "struct", "input", "output":
+		sort := language.IntegerSort
+		if parser.IsSort() {
+			sort = parser.Sort()
+		} else {
+			parser.Next()
+		}
+		for {
+			name := parser.expectIdentifier()
+			if token.Text == "int" || token.Text == "bool" || token.Text == "struct" {
+				parser.declare(token.Text, name, parser.Dimensions(sort))
+			} else {
+				parser.declare(token.Text, name, sort)
+			}
+			if _, ok := parser.Accept(","); !ok {
+				break
+			}
+		}
+	case "function":
+		parser.function()
+	case "template":
+		parser.template()
+	case "initial", "location":
+		parser.location()
+	case "edge":
+		parser.edge()
+	default:
+		parser.Fail(token, "expected a declaration but found %s", token)
+	}
+	parser.Expect(";")
+}
+
+// Declares the function after its body such that it cannot call itself.
+func (parser *parser) function() {
+	parser.Expect("function")
+	sort := parser.Dimensions(parser.Sort())
+	name := parser.expectIdentifier()
+	if _, exists := parser.model.declarations[name.Text]; exists {
+		parser.Fail(name, "\"%s\" is already declared", name.Text)
+	}
+	function := parser.Function(parser.model.symbols.Insert(name.Text), sort)
+	parser.declare("function", name, sort)
+	parser.model.functions.Declare(function)
+}
+
+func (parser *parser) declare(kind string, name language.Token, sort language.Sort) {
+	if _, exists := parser.model.declarations[name.Text]; exists {
+		parser.Fail(name, "\"%s\" is already declared", name.Text)
+	}
+
+	model := parser.model
+	declaration := declaration{
+		symbol: model.symbols.Insert(name.Text),
+	}
+	switch kind {
+	case "clock":
+		declaration.kind = clockDeclaration
+		model.clocks.Declare(declaration.symbol)
+	case "int", "bool", "struct":
+		model.variables.Declare(declaration.symbol, sort)
+	case "input":
+		declaration.kind = inputDeclaration
+		model.inputs = append(model.inputs, automata.Action(declaration.symbol))
+	case "output":
+		declaration.kind = outputDeclaration
+		model.outputs = append(model.outputs, automata.Action(declaration.symbol))
+	case "function":
+		declaration.kind = functionDeclaration
+	}
+	model.declarations[name.Text] = declaration
+}
+
+func (parser *parser) template() {
+	token := parser.Expect("template")
+	if parser.model.template {
+		parser.Fail(token, "the template is already declared")
+	}
+	parser.model.template = true
+	parser.model.name = parser.expectIdentifier().Text
+
+	parser.Expect("(")
+	if _, ok := parser.Accept(")"); ok {
+		return
+	}
+	for {
+		parser.parameter()
+		if _, ok := parser.Accept(","); !ok {
+			break
+		}
+	}
+	parser.Expect(")")
+}
+
+// Declares the parameter as a declaration of its kind such that it can be used like one in the template.
+func (parser *parser) parameter() {
+	name := parser.expectIdentifier()
+	parser.Expect(":")
+	kind := parser.Peek()
+	if !parser.Is("int", "bool", "clock", "input", "output") {
+		parser.Fail(kind, "expected a parameter kind but found %s", kind)
+	}
+	sort := language.IntegerSort
+	if parser.Is("int", "bool") {
+		sort = parser.Sort()
+	} else {
+		parser.Next()
+	}
+	parser.declare(kind.Text, name, sort)
+	symbol := parser.model.declarations[name.Text].symbol
+
+	var parameter automata.Parameter
+	switch kind.Text {
+	case "int":
+		parameter = automata.NewParameter(symbol, automata.IntegerParameter)
+		if lower, upper, bounded := sort.Bounds(); bounded {
+			parameter = automata.NewBoundedParameter(symbol, lower, upper)
+		}
+	case "bool":
+		parameter = automata.NewParameter(symbol, automata.BooleanParameter)
+	case "clock":
+		parameter = automata.NewParameter(symbol, automata.ClockParameter)
+	case "input", "output":
+		parameter = automata.NewParameter(symbol, automata.ChannelParameter)
+	}
+	parser.model.parameters = append(parser.model.parameters, parameter)
+}
+
+func (parser *parser) location() {
+	_, initial := parser.Accept("initial")
+	parser.Expect("location")
+	name := parser.expectName()
+	if _, exists := parser.model.keys[name.Text]; exists {
+		parser.Fail(name, "the location \"%s\" is already declared", name.Text)
+	}
+
+	invariant := language.Expression(language.NewTrue())
+	if _, ok := parser.Accept("invariant"); ok {
+		invariant = parser.convex()
+	}
+
+	if initial {
+		if parser.model.initial != "" {
+			parser.Fail(name, "the initial location is already declared")
+		}
+		parser.model.initial = name.Text
+	}
+	parser.model.addLocation(name.Text, invariant)
+}
+
+func (parser *parser) edge() {
+	parser.Expect("edge")
+	sourceName := parser.expectName()
+	parser.Expect("->")
+	destinationName := parser.expectName()
+
+	edge := edge{
+		source:      parser.lookupLocation(sourceName),
+		destination: parser.lookupLocation(destinationName),
+		guard:       language.NewTrue(),
+		update:      language.NewTrue(),
+	}
+
+	if next := parser.Lookahead(1); parser.Peek().Kind == language.IdentifierToken &&
+		next.Kind == language.PunctuationToken && (next.Text == "?" || next.Text == "!") {
+		name := parser.expectIdentifier()
+		declaration := parser.lookup(name)
+		direction := parser.Next()
+		switch {
+		case declaration.kind == inputDeclaration && direction.Text == "?",
+			declaration.kind == outputDeclaration && direction.Text == "!":
+			edge.action, edge.hasAction = automata.Action(declaration.symbol), true
+		case declaration.kind == inputDeclaration:
+			parser.Fail(direction, "the input \"%s\" must be written as \"%s?\"", name.Text, name.Text)
+		case declaration.kind == outputDeclaration:
+			parser.Fail(direction, "the output \"%s\" must be written as \"%s!\"", name.Text, name.Text)
+		default:
+			parser.Fail(name, "\"%s\" is not an action", name.Text)
+		}
+	} else if len(parser.model.inputs)+len(parser.model.outputs) > 0 {
+		parser.Fail(parser.Peek(), "expected an action but found %s", parser.Peek())
+	}
+
+	if _, ok := parser.Accept("when"); ok {
+		edge.guard = parser.convex()
+	}
+	if _, ok := parser.Accept("do"); ok {
+		edge.update = parser.Sequence(parser.isDeclaration)
+	}
+	parser.model.edges = append(parser.model.edges, edge)
+}
+
+// Returns an expression whose clock constraints can be represented by a zone.
+func (parser *parser) convex() language.Expression {
+	start := parser.Peek()
+	expression := parser.Expression()
+	if !language.IsConvex(expression) {
+		parser.Fail(start, "disjunctions of clock constraints are not supported")
+	}
+	return expression
+}
+
+func (parser *parser) lookupLocation(name language.Token) symbols.Symbol {
+	key, exists := parser.model.keys[name.Text]
+	if !exists {
+		parser.Fail(name, "undeclared location \"%s\"", name.Text)
+	}
+	return key
+}
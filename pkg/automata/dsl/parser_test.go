@@ -0,0 +1,480 @@
+package dsl
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+const lamp = `
+// A lamp which turns off after at least 3 time units.
+clock x;
+bool on;
+int presses;
+input press;
+output flash;
+
+initial location off;
+location lit invariant x - 0 ≤ 5;
+
+edge off -> lit press? when presses < 10 do x := 0; on' := true; presses' := presses + 1;
+edge lit -> off flash! when x >= 3 do on' := false;
+`
+
+func Test_Parse(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+
+	// Act
+	model, err := Parse(lamp, symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	reference, _ := symbolsMap.Lookup("0")
+	x, _ := symbolsMap.Lookup("x")
+	on, _ := symbolsMap.Lookup("on")
+	presses, _ := symbolsMap.Lookup("presses")
+	press, _ := symbolsMap.Lookup("press")
+	flash, _ := symbolsMap.Lookup("flash")
+
+	builder := automata.NewIOAutomatonBuilder()
+	builder.AddInputs(automata.Action(press))
+	builder.AddOutputs(automata.Action(flash))
+	off := builder.AddInitial("off", automata.WithInvariant(automata.NewTrueInvariant()))
+	lit := builder.AddLocation("lit", automata.WithInvariant(automata.NewInvariant(
+		language.NewClockConstraint(x, reference, zones.NewRelation(5, zones.Weak)),
+	)))
+	builder.AddEdge(off, automata.Action(press), lit,
+		automata.WithGuard(automata.NewGuard(
+			language.NewBinary(language.NewVariable(presses), language.LessThan, language.NewInteger(10)),
+		)),
+		automata.WithUpdate(automata.NewUpdate(language.NewBlockExpression(
+			language.NewTrue(),
+			language.NewClockReset(x, 0),
+			language.NewAssignment(language.NewVariable(on), language.NewTrue()),
+			language.NewAssignment(
+				language.NewVariable(presses),
+				language.NewBinary(language.NewVariable(presses), language.Addition, language.NewInteger(1)),
+			),
+		))),
+	)
+	builder.AddEdge(lit, automata.Action(flash), off,
+		automata.WithGuard(automata.NewGuard(
+			language.NewClockConstraint(reference, x, zones.NewRelation(-3, zones.Weak)),
+		)),
+		automata.WithUpdate(automata.NewUpdate(language.NewBlockExpression(
+			language.NewTrue(),
+			language.NewAssignment(language.NewVariable(on), language.NewFalse()),
+		))),
+	)
+
+	automaton, ioErr := model.IOAutomaton()
+	assert.NoError(t, ioErr)
+	assert.Equal(t, builder.Build(), automaton)
+	timed, timedErr := model.TIOAutomaton()
+	assert.NoError(t, timedErr)
+	assert.Equal(t, zones.Clock(2), timed.Clocks().Dimensions())
+	sort, _ := model.Variables().Lookup(on)
+	assert.Equal(t, language.BooleanSort, sort)
+	key, _ := model.Location("lit")
+	assert.Equal(t, lit, key)
+}
+
+func Test_ParseSymbolic(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	text := `int i; initial location "(a, b)"; edge "(a, b)" -> "(a, b)" when i < 3 do i += 1;`
+
+	// Act
+	model, err := Parse(text, symbolsMap)
+	_, ioErr := model.IOAutomaton()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.EqualError(t, ioErr, "the edges of the automaton have no actions")
+	i, _ := symbolsMap.Lookup("i")
+	builder := automata.NewAutomatonBuilder()
+	loop := builder.AddInitial("(a, b)", automata.WithInvariant(automata.NewTrueInvariant()))
+	builder.AddLoop(loop,
+		automata.WithGuard(automata.NewGuard(
+			language.NewBinary(language.NewVariable(i), language.LessThan, language.NewInteger(3)),
+		)),
+		automata.WithUpdate(automata.NewUpdate(language.NewBlockExpression(
+			language.NewTrue(),
+			language.NewAssignment(
+				language.NewVariable(i),
+				language.NewBinary(language.NewVariable(i), language.Addition, language.NewInteger(1)),
+			),
+		))),
+	)
+	assert.Equal(t, builder.Build(), model.Automaton())
+}
+
+func Test_ParseAggregates(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	text := `
+	int[0,3] a[2][4];
+	struct { int x; bool b; } r;
+	initial location l;
+	edge l -> l when r.b do a[1][r.x] += 1;
+	`
+
+	// Act
+	model, err := Parse(text, symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	a, _ := symbolsMap.Lookup("a")
+	r, _ := symbolsMap.Lookup("r")
+	aSort, _ := model.Variables().Lookup(a)
+	rSort, _ := model.Variables().Lookup(r)
+	assert.Equal(t, "int[0,3] a[2][4]", aSort.Declaration("a"))
+	assert.Equal(t, "struct { int x; bool b; }", rSort.String())
+
+	element := language.NewIndex(
+		language.NewIndex(language.NewVariable(a), language.NewInteger(1)),
+		language.NewMember(language.NewVariable(r), "x"),
+	)
+	builder := automata.NewAutomatonBuilder()
+	loop := builder.AddInitial("l", automata.WithInvariant(automata.NewTrueInvariant()))
+	builder.AddLoop(loop,
+		automata.WithGuard(automata.NewGuard(language.NewMember(language.NewVariable(r), "b"))),
+		automata.WithUpdate(automata.NewUpdate(language.NewBlockExpression(
+			language.NewTrue(),
+			language.NewAssignment(element, language.NewBinary(element, language.Addition, language.NewInteger(1))),
+		))),
+	)
+	assert.Equal(t, builder.Build(), model.Automaton())
+}
+
+func Test_ParseFunctions(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	text := `
+	int y;
+	function int f(int n) {
+		int s := 0;
+		while n > 0 do { s := s + y; n := n - 1 };
+		return s
+	};
+	initial location l;
+	edge l -> l when f(2) < 6 do y := f(y) + 1;
+	`
+
+	// Act
+	model, err := Parse(text, symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	f, _ := symbolsMap.Lookup("f")
+	y, _ := symbolsMap.Lookup("y")
+	function, exists := model.Functions().Lookup(f)
+	assert.True(t, exists)
+	assert.Equal(t, language.IntegerSort, function.Sort())
+
+	valuations := language.NewValuationsMap()
+	valuations.Assign(y, language.NewInteger(2))
+	concrete := language.NewConcreteInterpreter(model.Variables(), valuations)
+	assert.Equal(t, language.NewInteger(4), concrete.Interpret(language.NewCall(function, language.NewInteger(2))))
+
+	builder := automata.NewAutomatonBuilder()
+	loop := builder.AddInitial("l", automata.WithInvariant(automata.NewTrueInvariant()))
+	builder.AddLoop(loop,
+		automata.WithGuard(automata.NewGuard(language.NewBinary(
+			language.NewCall(function, language.NewInteger(2)), language.LessThan, language.NewInteger(6),
+		))),
+		automata.WithUpdate(automata.NewUpdate(language.NewBlockExpression(
+			language.NewTrue(),
+			language.NewAssignment(
+				language.NewVariable(y),
+				language.NewBinary(
+					language.NewCall(function, language.NewVariable(y)), language.Addition, language.NewInteger(1),
+				),
+			),
+		))),
+	)
+	assert.Equal(t, builder.Build(), model.Automaton())
+}
+
+func Test_ParseControlStatements(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	text := `
+	int y, a[3];
+	clock x;
+	initial location l;
+	edge l -> l do for i := 0 to 2 do { if a[i] < y then { a[i] := y } }; x := 0;
+	`
+
+	// Act
+	model, err := Parse(text, symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	y, _ := symbolsMap.Lookup("y")
+	a, _ := symbolsMap.Lookup("a")
+	i, _ := symbolsMap.Lookup("i")
+	x, _ := symbolsMap.Lookup("x")
+	_, declared := model.Variables().Lookup(i)
+	assert.False(t, declared)
+
+	element := language.NewIndex(language.NewVariable(a), language.NewVariable(i))
+	builder := automata.NewAutomatonBuilder()
+	loop := builder.AddInitial("l", automata.WithInvariant(automata.NewTrueInvariant()))
+	builder.AddLoop(loop,
+		automata.WithGuard(automata.NewTrueGuard()),
+		automata.WithUpdate(automata.NewUpdate(language.NewBlockExpression(
+			language.NewTrue(),
+			language.NewFor(i, 0, 2, language.NewIf(
+				language.NewBinary(element, language.LessThan, language.NewVariable(y)),
+				[]language.Statement{language.NewAssignment(element, language.NewVariable(y))},
+				nil,
+			)),
+			language.NewClockReset(x, 0),
+		))),
+	)
+	assert.Equal(t, builder.Build(), model.Automaton())
+}
+
+func Test_ParseTemplate(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	text := `
+	template Train(id : int[0,5], go : input);
+	clock x;
+	output leave;
+	initial location far;
+	location cross invariant x <= 5;
+	edge far -> cross go? when id > 0 do x := 0;
+	edge cross -> far leave! when x >= 3;
+	`
+
+	// Act
+	model, err := Parse(text, symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	template, templateErr := model.Template()
+	assert.NoError(t, templateErr)
+	assert.Equal(t, "Train", template.Name())
+	assert.Len(t, template.Parameters(), 2)
+	lower, upper, bounded := template.Parameters()[0].Bounds()
+	assert.True(t, bounded)
+	assert.Equal(t, 0, lower)
+	assert.Equal(t, 5, upper)
+	assert.Equal(t, automata.ChannelParameter, template.Parameters()[1].Kind())
+
+	start := symbolsMap.Insert("start")
+	network := automata.NewNetwork()
+	for id := 0; id < 3; id++ {
+		network.AddInstance(template, symbolsMap, model.Variables(), language.NewInteger(id), language.NewVariable(start))
+	}
+	for id := 0; id < 3; id++ {
+		name, process := network.Process(id)
+		assert.Equal(t, fmt.Sprintf("Train(%d, start)", id), name)
+		assert.Equal(t, []automata.Action{automata.Action(start)}, process.Inputs())
+	}
+	assert.Equal(t, zones.Clock(4), network.Clocks().Dimensions())
+}
+
+func Test_ParsePrettyPrinted(t *testing.T) {
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	model, _ := Parse("clock x, y; int i, j; bool b; initial location l;", symbolsMap)
+	reference, _ := symbolsMap.Lookup("0")
+	x, _ := symbolsMap.Lookup("x")
+	y, _ := symbolsMap.Lookup("y")
+	i, _ := symbolsMap.Lookup("i")
+	j, _ := symbolsMap.Lookup("j")
+	b, _ := symbolsMap.Lookup("b")
+
+	tests := []struct {
+		name       string
+		expression language.Expression
+		expected   string
+	}{
+		{
+			name:       "Upper bound",
+			expression: language.NewClockConstraint(x, reference, zones.NewRelation(5, zones.Weak)),
+			expected:   "x - 0 ≤ 5",
+		},
+		{
+			name:       "Lower bound",
+			expression: language.NewClockConstraint(reference, x, zones.NewRelation(-3, zones.Strict)),
+			expected:   "0 - x < -3",
+		},
+		{
+			name:       "Unbounded",
+			expression: language.NewClockConstraint(x, y, zones.NewInfinity()),
+			expected:   "x - y < ∞",
+		},
+		{
+			name: "Precedence",
+			expression: language.NewBinary(
+				language.NewBinary(language.NewVariable(b), language.LogicalOr, language.NewVariable(b)),
+				language.LogicalAnd,
+				language.NewBinary(
+					language.NewVariable(i),
+					language.GreaterThan,
+					language.NewBinary(
+						language.NewVariable(j),
+						language.Subtraction,
+						language.NewBinary(language.NewInteger(1), language.Subtraction, language.NewInteger(-2)),
+					),
+				),
+			),
+			expected: "(b ∨ b) ∧ i > j - (1 - -2)",
+		},
+		{
+			name: "Implication",
+			expression: language.NewBinary(
+				language.NewBinary(language.NewVariable(b), language.Implication, language.NewVariable(b)),
+				language.Implication,
+				language.LogicalNegate(language.NewBinary(language.NewVariable(i), language.NotEqual, language.NewInteger(0))),
+			),
+			expected: "(b → b) → ¬(i ≠ 0)",
+		},
+		{
+			name: "Conditional",
+			expression: language.NewIfThenElse(
+				language.NewBinary(language.NewVariable(i), language.Equal, language.NewInteger(0)),
+				language.NewVariable(j),
+				language.NewBinary(language.NewVariable(j), language.Addition, language.NewInteger(1)),
+			),
+			expected: "i = 0 ? j : j + 1",
+		},
+		{
+			name: "Statements",
+			expression: language.NewBlockExpression(
+				language.NewTrue(),
+				language.NewClockReset(x, 0),
+				language.NewClockAssignment(y, x),
+				language.NewClockShift(x, -2),
+				language.NewAssignment(language.NewVariable(b), language.NewFalse()),
+			),
+			expected: "x := 0; y := x; x -= 2; b' := false",
+		},
+		{
+			name: "Nested blocks",
+			expression: language.NewBinary(
+				language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)),
+				language.LogicalAnd,
+				language.NewBlockExpression(
+					language.NewBinary(language.NewVariable(i), language.GreaterThanEqual, language.NewInteger(2)),
+					language.NewAssignment(language.NewVariable(i), language.NewVariable(j)),
+				),
+			),
+			expected: "{x := 0} ∧ {i' := j; i ≥ 2}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var buffer bytes.Buffer
+			tt.expression.Accept(language.NewPrettyPrinter(&buffer, symbolsMap))
+
+			// Act
+			var parsed language.Expression
+			err := model.parse(buffer.String(), func(parser *parser) {
+				parsed = parser.Sequence(parser.isDeclaration)
+			})
+
+			// Assert
+			assert.Equal(t, tt.expected, buffer.String())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expression, parsed)
+		})
+	}
+}
+
+func Test_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "Undeclared identifier",
+			text:     "clock x;\ninitial location a invariant x ≤ k;",
+			expected: "2:34: undeclared identifier \"k\"",
+		},
+		{
+			name:     "Undeclared location",
+			text:     "initial location a; edge a -> b;",
+			expected: "1:31: undeclared location \"b\"",
+		},
+		{
+			name:     "Missing action",
+			text:     "input a; initial location l; edge l -> l when true;",
+			expected: "1:42: expected an action but found \"when\"",
+		},
+		{
+			name:     "Wrong direction",
+			text:     "input a; initial location l; edge l -> l a!;",
+			expected: "1:43: the input \"a\" must be written as \"a?\"",
+		},
+		{
+			name:     "Expression before statement",
+			text:     "int i; initial location l; edge l -> l do i > 0; i' := 1;",
+			expected: "1:43: expected a statement but found an expression",
+		},
+		{
+			name:     "Empty bounds",
+			text:     "template T(i : int[5,0]);",
+			expected: "1:16: the lower bound 5 is greater than the upper bound 0",
+		},
+		{
+			name:     "Disjunctive clock guard",
+			text:     "clock x; initial location l; edge l -> l when x < 1 || x > 2;",
+			expected: "1:47: disjunctions of clock constraints are not supported",
+		},
+		{
+			name:     "Disjunctive clock invariant",
+			text:     "clock x; initial location l invariant !(x < 1 && x > 2);",
+			expected: "1:39: disjunctions of clock constraints are not supported",
+		},
+		{
+			name:     "No initial location",
+			text:     "location l;",
+			expected: "the automaton has no initial location",
+		},
+		{
+			name:     "Clock reset in loop",
+			text:     "clock x; initial location l; edge l -> l do while true do { x := 0 };",
+			expected: "1:61: clocks cannot be assigned in conditional statements and loops",
+		},
+		{
+			name:     "Function assigns global",
+			text:     "int i; function int f() { i := 1; return i };",
+			expected: "1:27: functions can only assign their parameters and local variables",
+		},
+		{
+			name:     "Function not called",
+			text:     "function int f() { return 1 }; initial location l invariant f > 0;",
+			expected: "1:61: the function \"f\" must be called",
+		},
+		{
+			name:     "Wrong number of arguments",
+			text:     "function int f(int n) { return n }; initial location l invariant f() > 0;",
+			expected: "1:66: \"f\" takes 1 arguments but is given 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+
+			// Act
+			_, err := Parse(tt.text, symbolsMap)
+
+			// Assert
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package automata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// The JSON format of ECDAR components.
+type ecdarComponent struct {
+	Name                   string          `json:"name"`
+	Declarations           string          `json:"declarations"`
+	Locations              []ecdarLocation `json:"locations"`
+	Edges                  []ecdarEdge     `json:"edges"`
+	Description            string          `json:"description"`
+	X                      float64         `json:"x"`
+	Y                      float64         `json:"y"`
+	Width                  float64         `json:"width"`
+	Height                 float64         `json:"height"`
+	Color                  string          `json:"color"`
+	IncludeInPeriodicCheck bool            `json:"includeInPeriodicCheck"`
+}
+
+type ecdarLocation struct {
+	ID        string  `json:"id"`
+	Nickname  string  `json:"nickname"`
+	Invariant string  `json:"invariant"`
+	Type      string  `json:"type"`
+	Urgency   string  `json:"urgency"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Color     string  `json:"color"`
+}
+
+type ecdarEdge struct {
+	ID             string `json:"id"`
+	Group          string `json:"group"`
+	SourceLocation string `json:"sourceLocation"`
+	TargetLocation string `json:"targetLocation"`
+	Status         string `json:"status"`
+	Select         string `json:"select"`
+	Guard          string `json:"guard"`
+	Update         string `json:"update"`
+	Sync           string `json:"sync"`
+	IsLocked       bool   `json:"isLocked"`
+	Nails          []any  `json:"nails"`
+}
+
+// Writes the automaton as an ECDAR component named by the name. The clocks of the automaton and the
+// variables are declared in the component and all expressions are written in the UPPAAL syntax.
+func (automaton *TIOAutomaton) ECDAR(
+	writer io.Writer, name string, store symbols.Store[any], variables language.Variables,
+) error {
+	label := func(expression language.Expression) string {
+		if boolean, ok := expression.(language.Boolean); ok && boolean.Value() {
+			return ""
+		}
+		var buffer bytes.Buffer
+		expression.Accept(language.NewUPPAALPrinter(&buffer, store, automaton.clocks))
+		return buffer.String()
+	}
+	identifier := func(symbol symbols.Symbol) string {
+		item, _ := store.Item(symbol)
+		return language.Identifier(fmt.Sprint(item))
+	}
+
+	component := ecdarComponent{
+		Name:         name,
+		Declarations: automaton.ecdarDeclarations(identifier, variables),
+		Width:        600,
+		Height:       600,
+		Color:        "0",
+	}
+
+	// The locations are written in the order of their keys and placed on a grid.
+	keys := make([]symbols.Symbol, 0)
+	automaton.Locations(func(key symbols.Symbol, _ Location) bool {
+		keys = append(keys, key)
+		return true
+	})
+	slices.Sort(keys)
+
+	ids := map[symbols.Symbol]string{}
+	for idx, key := range keys {
+		location, _ := automaton.Location(key)
+		ids[key] = fmt.Sprintf("L%v", idx)
+		kind := "NORMAL"
+		if key == automaton.initial {
+			kind = "INITIAL"
+		}
+		component.Locations = append(component.Locations, ecdarLocation{
+			ID:        ids[key],
+			Nickname:  location.name,
+			Invariant: label(location.invariant.condition),
+			Type:      kind,
+			Urgency:   "NORMAL",
+			X:         float64(100 + 150*(idx%4)),
+			Y:         float64(100 + 150*(idx/4)),
+			Color:     "0",
+		})
+	}
+
+	automaton.Edges(func(edge IOEdge) bool {
+		status := "OUTPUT"
+		if automaton.IsInput(edge.action) {
+			status = "INPUT"
+		}
+		var update bytes.Buffer
+		language.NewUPPAALPrinter(&update, store, automaton.clocks).Update(edge.update.expression)
+		component.Edges = append(component.Edges, ecdarEdge{
+			ID:             fmt.Sprintf("E%v", len(component.Edges)),
+			SourceLocation: ids[edge.source],
+			TargetLocation: ids[edge.destination],
+			Status:         status,
+			Guard:          label(edge.guard.condition),
+			Update:         update.String(),
+			Sync:           identifier(symbols.Symbol(edge.action)),
+			Nails:          []any{},
+		})
+		return true
+	})
+
+	encoded, err := json.MarshalIndent(component, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(encoded)
+	return err
+}
+
+func (automaton *TIOAutomaton) ecdarDeclarations(
+	identifier func(symbols.Symbol) string, variables language.Variables,
+) string {
+	var builder strings.Builder
+
+	clocks := make([]string, automaton.clocks.Dimensions()-1)
+	automaton.clocks.All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock != zones.Reference {
+			clocks[clock-1] = identifier(symbol)
+		}
+		return true
+	})
+	if len(clocks) > 0 {
+		fmt.Fprintf(&builder, "clock %s;\n", strings.Join(clocks, ", "))
+	}
+
+	if variables == nil {
+		return builder.String()
+	}
+	symbolsOfVariables := make([]symbols.Symbol, 0)
+	variables.All(func(symbol symbols.Symbol, _ language.Sort) bool {
+		symbolsOfVariables = append(symbolsOfVariables, symbol)
+		return true
+	})
+	slices.Sort(symbolsOfVariables)
+	for _, symbol := range symbolsOfVariables {
+		sort, _ := variables.Lookup(symbol)
+		fmt.Fprintf(&builder, "%s %s;\n", sort, identifier(symbol))
+	}
+	return builder.String()
+}
@@ -0,0 +1,60 @@
+package automata
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ECDAR(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	coin, tea := Action(symbolsMap.Insert("coin")), Action(symbolsMap.Insert("tea"))
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+
+	builder := NewIOAutomatonBuilder()
+	builder.AddInputs(coin)
+	builder.AddOutputs(tea)
+	idle := builder.AddInitial("idle")
+	busy := builder.AddLocation("busy", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(2, zones.Weak))),
+	))
+	builder.AddEdge(idle, coin, busy, WithUpdate(NewUpdate(
+		language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)),
+	)))
+	builder.AddEdge(busy, tea, idle, WithGuard(NewGuard(
+		language.NewClockConstraint(reference, x, zones.NewRelation(-1, zones.Strict)),
+	)))
+	automaton := NewTIOAutomaton(builder.Build(), clocks)
+	var buffer bytes.Buffer
+
+	// Act
+	err := automaton.ECDAR(&buffer, "Machine", symbolsMap, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	var component ecdarComponent
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &component))
+	assert.Equal(t, "Machine", component.Name)
+	assert.Equal(t, "clock x;\n", component.Declarations)
+	assert.Len(t, component.Locations, 2)
+	assert.Equal(t, "INITIAL", component.Locations[0].Type)
+	assert.Equal(t, "x <= 2", component.Locations[1].Invariant)
+	assert.Len(t, component.Edges, 2)
+	for _, edge := range component.Edges {
+		if edge.Sync == "coin" {
+			assert.Equal(t, "INPUT", edge.Status)
+			assert.Equal(t, "x = 0", edge.Update)
+		} else {
+			assert.Equal(t, "OUTPUT", edge.Status)
+			assert.Equal(t, "x > 1", edge.Guard)
+		}
+	}
+}
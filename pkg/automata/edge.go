@@ -31,6 +31,14 @@ func (edge Edge) Destination() symbols.Symbol {
 	return edge.destination
 }
 
+func (edge Edge) Guard() Guard {
+	return edge.guard
+}
+
+func (edge Edge) Update() Update {
+	return edge.update
+}
+
 func (edge Edge) IsEnabled(valuations language.Valuations, solver *Interpreter) bool {
 	return solver.IsSatisfied(valuations, edge.guard.condition)
 }
@@ -41,5 +49,6 @@ func (edge Edge) Traverse(state State, solver *Interpreter) State {
 		edge.destination,
 		valuations,
 		edge.update.Apply(state.constraint),
+		state.zone.Copy(),
 	)
 }
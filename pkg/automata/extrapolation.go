@@ -0,0 +1,100 @@
+package automata
+
+import (
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// The maximal lower and upper bounds (LU-bounds) the clocks are compared to in each location.
+// The bounds of a location include the bounds of the locations reachable from it as long as
+// the clock is not reset on the way. The bounds are indexed by the clocks excluding the reference.
+type Bounds struct {
+	lowers, uppers map[symbols.Symbol][]int
+	// True if the automaton has difference constraints between clocks.
+	diagonal bool
+}
+
+// Computes the bounds from the invariants and guards of the automaton.
+func NewBounds(automaton *SymbolicAutomaton, clocks language.Clocks) Bounds {
+	interpreter := language.NewZoneInterpreter(clocks)
+	size := int(clocks.Dimensions()) - 1
+	bounds := Bounds{
+		lowers: map[symbols.Symbol][]int{},
+		uppers: map[symbols.Symbol][]int{},
+	}
+
+	// The bounds of the constraints in the location itself.
+	automaton.Locations(func(key symbols.Symbol, location Location) bool {
+		lowers, uppers := make([]int, size), make([]int, size)
+		interpreter.Bounds(location.invariant.condition, lowers, uppers)
+		bounds.diagonal = bounds.diagonal || interpreter.HasDiagonalConstraints(location.invariant.condition)
+		for _, edge := range automaton.Outgoing(key) {
+			interpreter.Bounds(edge.guard.condition, lowers, uppers)
+			bounds.diagonal = bounds.diagonal || interpreter.HasDiagonalConstraints(edge.guard.condition)
+		}
+		bounds.lowers[key], bounds.uppers[key] = lowers, uppers
+		return true
+	})
+
+	// The bounds of the destinations are propagated backwards until they no longer change.
+	raise := func(bounds, by []int) (raised bool) {
+		for idx := range bounds {
+			if by[idx] > bounds[idx] {
+				bounds[idx], raised = by[idx], true
+			}
+		}
+		return raised
+	}
+	for raised := true; raised; {
+		raised = false
+		automaton.Edges(func(edge Edge) bool {
+			lowers, uppers := interpreter.BoundsBefore(
+				edge.update.expression, bounds.lowers[edge.destination], bounds.uppers[edge.destination],
+			)
+			raised = raise(bounds.lowers[edge.source], lowers) || raised
+			raised = raise(bounds.uppers[edge.source], uppers) || raised
+			return true
+		})
+	}
+
+	return bounds
+}
+
+// Returns the maximal lower bounds of the clocks in the location.
+func (bounds Bounds) Lowers(location symbols.Symbol) []int {
+	return bounds.lowers[location]
+}
+
+// Returns the maximal upper bounds of the clocks in the location.
+func (bounds Bounds) Uppers(location symbols.Symbol) []int {
+	return bounds.uppers[location]
+}
+
+// Returns the maximal constants of the clocks over all locations.
+func (bounds Bounds) Maximums() (maximums []int) {
+	for location := range bounds.lowers {
+		if maximums == nil {
+			maximums = make([]int, len(bounds.lowers[location]))
+		}
+		for idx := range maximums {
+			maximums[idx] = max(maximums[idx], bounds.lowers[location][idx], bounds.uppers[location][idx])
+		}
+	}
+	return maximums
+}
+
+// Returns true if the automaton has difference constraints between clocks.
+func (bounds Bounds) IsDiagonal() bool {
+	return bounds.diagonal
+}
+
+// Extrapolates the zone by the bounds of the location such that the exploration terminates.
+// The extrapolation is unsound with difference constraints between clocks so the zone is then
+// left as is. The exploration of such automata only terminates if their clocks are bounded.
+func (bounds Bounds) Extrapolate(location symbols.Symbol, zone zones.DBM) {
+	if bounds.diagonal {
+		return
+	}
+	zone.ExtraLU(bounds.lowers[location], bounds.uppers[location])
+}
@@ -0,0 +1,74 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Bounds(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	action := Action(symbolsMap.Insert("a"))
+
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+	builder := NewIOAutomatonBuilder()
+	builder.AddOutputs(action)
+	idle := builder.AddInitial("idle")
+	busy := builder.AddLocation("busy", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(7, zones.Weak))),
+	))
+	// x ≥ 3 without a reset such that the invariant of busy is also relevant in idle.
+	builder.AddEdge(idle, action, busy, WithGuard(NewGuard(
+		language.NewClockConstraint(reference, x, zones.NewRelation(-3, zones.Weak)),
+	)))
+	builder.AddEdge(busy, action, idle, WithUpdate(NewUpdate(
+		language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)),
+	)))
+
+	// Act
+	bounds := NewTIOAutomaton(builder.Build(), clocks).Bounds()
+
+	// Assert
+	assert.Equal(t, []int{3}, bounds.Lowers(idle))
+	assert.Equal(t, []int{7}, bounds.Uppers(idle))
+	assert.Equal(t, []int{0}, bounds.Lowers(busy))
+	assert.Equal(t, []int{7}, bounds.Uppers(busy))
+	assert.Equal(t, []int{7}, bounds.Maximums())
+}
+
+func Test_BoundsDiagonal(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x, y := symbolsMap.Insert("0"), symbolsMap.Insert("x"), symbolsMap.Insert("y")
+	action := Action(symbolsMap.Insert("a"))
+
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+	clocks.Declare(y)
+	builder := NewIOAutomatonBuilder()
+	builder.AddOutputs(action)
+	idle := builder.AddInitial("idle")
+	busy := builder.AddLocation("busy")
+	// x - y < 1 is a difference constraint which the extrapolation cannot preserve.
+	builder.AddEdge(idle, action, busy, WithGuard(NewGuard(
+		language.NewClockConstraint(x, y, zones.NewRelation(1, zones.Strict)),
+	)))
+	zone := zones.NewDBM(clocks.Dimensions(), zones.Zero)
+	zone.Up()
+	zone.ConstrainAndClose(zones.Reference, 1, zones.NewRelation(-5, zones.Weak))
+	extrapolated := zone.Copy()
+
+	// Act
+	bounds := NewTIOAutomaton(builder.Build(), clocks).Bounds()
+	bounds.Extrapolate(idle, extrapolated)
+
+	// Assert
+	assert.True(t, bounds.IsDiagonal())
+	assert.True(t, zone.Equals(extrapolated, zones.Reference, clocks.Dimensions()))
+}
@@ -0,0 +1,367 @@
+package automata
+
+import (
+	"slices"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/structures"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// Decides which of the actions are controlled by the controller.
+// The remaining actions are controlled by the environment.
+type Controller int
+
+const (
+	// The controller chooses the inputs and the environment the outputs.
+	InputController Controller = iota
+	// The controller chooses the outputs and the environment the inputs.
+	OutputController
+)
+
+// A timed game where the controller and the environment take turns choosing actions of the automaton whilst
+// time passes. The games are solved on-the-fly in the style of SOTGA: The states are explored forwards whilst
+// the states from which the objective can be forced are propagated backwards to the explored states such that
+// the search stops as soon as the initial state is decided. The states are grouped into nodes of the same
+// location and data where the clock valuations of each node are federations.
+type TimedGame struct {
+	automaton  TIOAutomaton
+	controller Controller
+	system     *SymbolicTransitionSystem
+	zones      language.ZoneInterpreter
+	dimensions zones.Clock
+}
+
+func NewTimedGame(automaton TIOAutomaton, controller Controller, interpreter *Interpreter) *TimedGame {
+	return &TimedGame{
+		automaton:  automaton,
+		controller: controller,
+		system:     automaton.TransitionSystem(interpreter),
+		zones:      language.NewZoneInterpreter(automaton.clocks),
+		dimensions: automaton.clocks.Dimensions(),
+	}
+}
+
+// Returns true if the action is chosen by the controller.
+func (game *TimedGame) IsControllable(action Action) bool {
+	if game.controller == InputController {
+		return game.automaton.IsInput(action)
+	}
+	return game.automaton.IsOutput(action)
+}
+
+// Returns the initial state of the game.
+func (game *TimedGame) Initial(valuations language.Valuations) State {
+	return game.system.Initial(valuations)
+}
+
+// Computes the states from which the controller can force the game into one of the goal locations.
+// If the controller wins from the initial state before all states are explored then the winning
+// states are those found so far.
+func (game *TimedGame) Reachability(valuations language.Valuations, goals ...symbols.Symbol) Strategy {
+	nodes, _ := game.solve(valuations, true, goals)
+	winning := make([]zones.Federation, len(nodes))
+	for idx, node := range nodes {
+		winning[idx] = node.attractor
+	}
+	return game.strategy(nodes, winning)
+}
+
+// Computes the states from which the controller can prevent the game from reaching any of the bad locations.
+// If the environment wins from the initial state before all states are explored then the controller has no
+// winning states as the states which have not been explored may still be losing.
+func (game *TimedGame) Safety(valuations language.Valuations, bad ...symbols.Symbol) Strategy {
+	nodes, lost := game.solve(valuations, false, bad)
+	winning := make([]zones.Federation, len(nodes))
+	for idx, node := range nodes {
+		winning[idx] = zones.NewFederation(game.dimensions)
+		if !lost {
+			winning[idx] = node.explored.Subtraction(node.attractor).Reduction()
+		}
+	}
+	return game.strategy(nodes, winning)
+}
+
+// The states of a location and data of the game. The reachable clock valuations have been reached
+// whilst the explored clock valuations have been reached and their successors have been reached too.
+// The attractor is the clock valuations from which the player of the objective can force it.
+type gameNode struct {
+	state                          State
+	reachable, explored, attractor zones.Federation
+	edges                          []gameEdge
+	// The nodes with an edge to the node.
+	sources []int
+}
+
+// An edge of the automaton between the explored clock valuations of two nodes.
+type gameEdge struct {
+	edge IOEdge
+	// The index of the edge in the outgoing edges of the location.
+	index       int
+	destination int
+}
+
+// Returns true if the states are in the same location with the same data regardless of their clock valuations.
+func isSameNode(state, other State, interpreter *Interpreter) bool {
+	state.zone = other.zone
+	return state.SubsetOf(other, interpreter) && other.SubsetOf(state, interpreter)
+}
+
+// Solves the game for the player of the controllable or uncontrollable actions whose objective is to reach one of
+// the target locations. The nodes whose attractor grows are propagated to their sources before further states are
+// explored. Returns the explored nodes and true if the player can force the objective from the initial state.
+func (game *TimedGame) solve(
+	valuations language.Valuations, controllable bool, targets []symbols.Symbol,
+) (nodes []*gameNode, decided bool) {
+	initial := game.system.Initial(valuations)
+	if !initial.zone.IsConsistent() {
+		return nil, false
+	}
+
+	type frontier struct {
+		state State
+		node  int
+	}
+	forward := structures.Stack[frontier]{}
+	backward, queued := structures.Queue[int]{}, []bool{}
+	propagate := func(index int) {
+		if !queued[index] {
+			queued[index] = true
+			backward.Enqueue(index)
+		}
+	}
+
+	// Adds the clock valuations of the state to its node. The states of the targets are not explored further.
+	reach := func(state State) int {
+		index := slices.IndexFunc(nodes, func(node *gameNode) bool {
+			return isSameNode(state, node.state, game.system.interpreter)
+		})
+		if index < 0 {
+			nodes = append(nodes, &gameNode{
+				state:     state,
+				reachable: zones.NewFederation(game.dimensions),
+				explored:  zones.NewFederation(game.dimensions),
+				attractor: zones.NewFederation(game.dimensions),
+			})
+			queued = append(queued, false)
+			index = len(nodes) - 1
+		}
+
+		node := nodes[index]
+		if node.reachable.Includes(state.zone) {
+			return index
+		}
+		node.reachable = node.reachable.Union(zones.NewFederation(game.dimensions, state.zone))
+		if slices.Contains(targets, state.location) {
+			node.attractor = node.reachable.Copy()
+			for _, source := range node.sources {
+				propagate(source)
+			}
+		} else {
+			forward.Push(frontier{state, index})
+		}
+		return index
+	}
+
+	reach(initial)
+	for !nodes[0].attractor.Includes(initial.zone) {
+		if !backward.IsEmpty() {
+			index := backward.Dequeue()
+			queued[index] = false
+			if game.attract(nodes, nodes[index], controllable) {
+				for _, source := range nodes[index].sources {
+					propagate(source)
+				}
+			}
+			continue
+		}
+		if forward.IsEmpty() {
+			return nodes, false
+		}
+
+		current := forward.Pop()
+		node := nodes[current.node]
+		for idx, edge := range game.automaton.Automaton.Outgoing(current.state.location) {
+			successor, enabled := game.system.Successor(current.state, edge.Edge)
+			if !enabled {
+				continue
+			}
+			destination := reach(successor)
+			if !slices.ContainsFunc(node.edges, func(explored gameEdge) bool {
+				return explored.index == idx && explored.destination == destination
+			}) {
+				node.edges = append(node.edges, gameEdge{edge, idx, destination})
+			}
+			if !slices.Contains(nodes[destination].sources, current.node) {
+				nodes[destination].sources = append(nodes[destination].sources, current.node)
+			}
+		}
+		node.explored = node.explored.Union(zones.NewFederation(game.dimensions, current.state.zone))
+		propagate(current.node)
+	}
+	return nodes, true
+}
+
+// Grows the attractor of the node by the explored clock valuations which can delay into the attractor or to an
+// edge of the player leading into the attractor of its destination without passing a state from which an edge
+// of the opponent leads out of it. Returns true if the attractor grew.
+func (game *TimedGame) attract(nodes []*gameNode, node *gameNode, controllable bool) bool {
+	good := node.attractor.Union(game.predecessors(nodes, node, controllable, func(destination *gameNode) zones.Federation {
+		return destination.attractor
+	}))
+	bad := game.predecessors(nodes, node, !controllable, func(destination *gameNode) zones.Federation {
+		return destination.reachable.Subtraction(destination.attractor)
+	})
+	grown := timePredecessor(good, bad).Intersection(node.explored)
+	if subset, _ := grown.Relation(node.attractor); subset {
+		return false
+	}
+	node.attractor = node.attractor.Union(grown).Reduction()
+	return true
+}
+
+// Returns the explored clock valuations of the node from which a controllable or uncontrollable
+// edge can be traversed such that the clock valuations afterwards are in the target.
+func (game *TimedGame) predecessors(
+	nodes []*gameNode, node *gameNode, controllable bool, target func(destination *gameNode) zones.Federation,
+) zones.Federation {
+	predecessors := zones.NewFederation(game.dimensions)
+	for _, edge := range node.edges {
+		if game.IsControllable(edge.edge.action) != controllable {
+			continue
+		}
+		predecessors = predecessors.Union(game.predecessor(node, edge.edge, target(nodes[edge.destination])))
+	}
+	return predecessors
+}
+
+// Returns the explored clock valuations of the node from which the edge leads to clock valuations in the target.
+func (game *TimedGame) predecessor(node *gameNode, edge IOEdge, target zones.Federation) zones.Federation {
+	guard := zones.NewDBM(game.dimensions, zones.Infinity)
+	if !game.zones.Constrain(guard, edge.guard.condition) {
+		return zones.NewFederation(game.dimensions)
+	}
+
+	predecessors := zones.NewFederation(game.dimensions)
+	for _, zone := range target.Zones() {
+		zone := zone.Copy()
+		if game.zones.Unapply(zone, edge.update.expression) {
+			predecessors = predecessors.Union(zones.NewFederation(game.dimensions, zone))
+		}
+	}
+	return predecessors.
+		Intersection(zones.NewFederation(game.dimensions, guard)).
+		Intersection(node.explored)
+}
+
+// Returns the strategy of the winning clock valuations of the nodes where the controller
+// takes the controllable edges which lead to the winning clock valuations of their destinations.
+func (game *TimedGame) strategy(nodes []*gameNode, winning []zones.Federation) Strategy {
+	strategy := Strategy{
+		interpreter: game.system.interpreter,
+		dimensions:  game.dimensions,
+		states:      make([]State, len(nodes)),
+		winning:     winning,
+		moves:       make([][]move, len(nodes)),
+	}
+	for idx, node := range nodes {
+		strategy.states[idx] = node.state
+		for _, edge := range node.edges {
+			if !game.IsControllable(edge.edge.action) {
+				continue
+			}
+			enabled := game.predecessor(node, edge.edge, winning[edge.destination]).Intersection(winning[idx])
+			if !enabled.IsEmpty() {
+				strategy.moves[idx] = append(strategy.moves[idx], move{edge.edge, enabled})
+			}
+		}
+	}
+	return strategy
+}
+
+// Returns the clock valuations which can delay into the good valuations without passing the bad valuations:
+//
+//	Pred_t(G, B) = ⋃_i ⋂_j ((G_i↓ \ B_j↓) ∪ ((G_i ∩ B_j↓) \ B_j)↓)
+func timePredecessor(good, bad zones.Federation) zones.Federation {
+	dimensions := good.Clocks()
+	predecessors := zones.NewFederation(dimensions)
+	for _, goodZone := range good.Zones() {
+		past := zones.NewFederation(dimensions, goodZone.Copy())
+		past.Down()
+
+		piece := past
+		for _, badZone := range bad.Zones() {
+			badPast := zones.NewFederation(dimensions, badZone.Copy())
+			badPast.Down()
+
+			// The good zone is reached before the bad zone.
+			before := zones.NewFederation(dimensions, goodZone).
+				Intersection(badPast).
+				Subtraction(zones.NewFederation(dimensions, badZone))
+			before.Down()
+
+			piece = piece.Intersection(past.Subtraction(badPast).Union(before))
+		}
+		predecessors = predecessors.Union(piece)
+	}
+	return predecessors.Reduction()
+}
+
+// A controllable edge and the clock valuations from which the controller should take it.
+type move struct {
+	edge  IOEdge
+	zones zones.Federation
+}
+
+// The winning states of a timed game and the moves the controller makes to win.
+// Whenever the controller has no move it delays. The states are those of the explored nodes.
+type Strategy struct {
+	interpreter *Interpreter
+	dimensions  zones.Clock
+	states      []State
+	winning     []zones.Federation
+	moves       [][]move
+}
+
+// Returns the index of the node of the state or -1 if it has not been explored.
+func (strategy Strategy) node(state State) int {
+	return slices.IndexFunc(strategy.states, func(node State) bool {
+		return isSameNode(state, node, strategy.interpreter)
+	})
+}
+
+// Returns true if the controller wins from all clock valuations of the state.
+func (strategy Strategy) IsWinning(state State) bool {
+	index := strategy.node(state)
+	if index < 0 {
+		return false
+	}
+	return strategy.winning[index].Includes(state.zone)
+}
+
+// Returns the winning clock valuations of the location over all of its data.
+func (strategy Strategy) Winning(location symbols.Symbol) zones.Federation {
+	winning := zones.NewFederation(strategy.dimensions)
+	for idx := range strategy.states {
+		if strategy.states[idx].location == location {
+			winning = winning.Union(strategy.winning[idx])
+		}
+	}
+	return winning
+}
+
+// Returns the controllable edge the controller takes from all clock valuations of the state.
+// If there is no such edge then the controller delays.
+func (strategy Strategy) Move(state State) (edge IOEdge, exists bool) {
+	index := strategy.node(state)
+	if index < 0 {
+		return edge, false
+	}
+	for _, move := range strategy.moves[index] {
+		if move.zones.Includes(state.zone) {
+			return move.edge, true
+		}
+	}
+	return edge, false
+}
@@ -0,0 +1,117 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a game where the environment outputs into the trap when "x ≥ 3" and
+// the controller can input into the goal when "x ≥ guard". Time can pass until "x ≤ 5".
+func race(guard int) (game *TimedGame, start, goal, trap symbols.Symbol, clock zones.Clock) {
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	input, output := Action(symbolsMap.Insert("in")), Action(symbolsMap.Insert("out"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	clocks := language.NewClocksMap(reference)
+	clock = clocks.Declare(x)
+	builder := NewIOAutomatonBuilder()
+	builder.AddInputs(input)
+	builder.AddOutputs(output)
+	start = builder.AddInitial("start", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(5, zones.Weak))),
+	))
+	goal = builder.AddLocation("goal")
+	trap = builder.AddLocation("trap")
+	builder.AddEdge(start, input, goal, WithGuard(NewGuard(
+		language.NewClockConstraint(reference, x, zones.NewRelation(-guard, zones.Weak)),
+	)))
+	builder.AddEdge(start, output, trap, WithGuard(NewGuard(
+		language.NewClockConstraint(reference, x, zones.NewRelation(-3, zones.Weak)),
+	)))
+
+	automaton := NewTIOAutomaton(builder.Build(), clocks)
+	return NewTimedGame(*automaton, InputController, interpreter), start, goal, trap, clock
+}
+
+// Returns the zone "lower ≤ x ≤ upper".
+func clockInterval(clock zones.Clock, lower, upper int) zones.DBM {
+	zone := zones.NewDBM(clock+1, zones.Infinity)
+	zone.ConstrainAndClose(zones.Reference, clock, zones.NewRelation(-lower, zones.Weak))
+	zone.ConstrainAndClose(clock, zones.Reference, zones.NewRelation(upper, zones.Weak))
+	return zone
+}
+
+func Test_Reachability(t *testing.T) {
+	// Arrange
+	game, start, goal, _, clock := race(2)
+	valuations := language.NewValuationsMap()
+
+	// Act
+	strategy := game.Reachability(valuations, goal)
+
+	// Assert
+	winning := strategy.Winning(start)
+	assert.True(t, winning.Includes(clockInterval(clock, 0, 2)))
+	assert.False(t, winning.Includes(clockInterval(clock, 3, 3)))
+	assert.False(t, strategy.IsWinning(game.Initial(valuations)))
+
+	edge, exists := strategy.Move(NewState(start, valuations, language.NewTrue(), clockInterval(clock, 2, 2)))
+	assert.True(t, exists)
+	assert.Equal(t, goal, edge.Destination())
+	_, exists = strategy.Move(NewState(start, valuations, language.NewTrue(), clockInterval(clock, 0, 1)))
+	assert.False(t, exists)
+}
+
+func Test_ReachabilityDecidedEarly(t *testing.T) {
+	// Arrange
+	game, start, goal, trap, _ := race(2)
+	valuations := language.NewValuationsMap()
+
+	// Act
+	strategy := game.Reachability(valuations, start)
+
+	// Assert
+	assert.True(t, strategy.IsWinning(game.Initial(valuations)))
+	// The search stops before the successors of the initial state are explored.
+	assert.True(t, strategy.Winning(goal).IsEmpty())
+	assert.True(t, strategy.Winning(trap).IsEmpty())
+}
+
+func Test_Safety(t *testing.T) {
+	tests := []struct {
+		name     string
+		guard    int
+		expected bool
+	}{
+		{
+			name:     "The controller escapes before the environment",
+			guard:    2,
+			expected: true,
+		},
+		{
+			name:     "The environment moves before the controller",
+			guard:    4,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			game, _, _, trap, _ := race(tt.guard)
+			valuations := language.NewValuationsMap()
+
+			// Act
+			strategy := game.Safety(valuations, trap)
+
+			// Assert
+			assert.Equal(t, tt.expected, strategy.IsWinning(game.Initial(valuations)))
+		})
+	}
+}
@@ -17,6 +17,10 @@ func NewGuard(condition language.Expression) Guard {
 	}
 }
 
+func (guard Guard) Condition() language.Expression {
+	return guard.condition
+}
+
 func NewTrueGuard() Guard {
 	return NewGuard(language.NewTrue())
 }
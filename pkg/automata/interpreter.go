@@ -6,35 +6,66 @@ import (
 )
 
 type Interpreter struct {
-	context   *z3.Context
-	variables language.Variables
+	context    *z3.Context
+	variables  language.Variables
+	unrollings int
+	err        error
 }
 
 func NewInterpreter(context *z3.Context, variables language.Variables) *Interpreter {
 	return &Interpreter{
-		context:   context,
-		variables: variables,
+		context:    context,
+		variables:  variables,
+		unrollings: language.DefaultUnrollings,
 	}
 }
 
+// Sets the maximum number of iterations of while loops which are unrolled.
+func (solver *Interpreter) SetUnrollings(unrollings int) {
+	solver.unrollings = unrollings
+}
+
+// Returns the first error of the interpretations if any. The results of the
+// interpretations are not reliable after an error such as an unbounded loop.
+func (solver *Interpreter) Err() error {
+	return solver.err
+}
+
+// Constructs the interpreter of a single interpretation of the valuations.
+func (solver *Interpreter) symbolic(valuations language.Valuations) *language.SymbolicInterpreter {
+	interpreter := language.NewSymbolicInterpreter(solver.context, solver.variables, valuations)
+	interpreter.SetUnrollings(solver.unrollings)
+	return interpreter
+}
+
+// Keeps the error of the interpretation if it is the first one.
+func (solver *Interpreter) collect(interpreter *language.SymbolicInterpreter) {
+	if solver.err == nil {
+		solver.err = interpreter.Err()
+	}
+}
+
+func (solver *Interpreter) Variables() language.Variables {
+	return solver.variables
+}
+
 func (solver *Interpreter) Interpret(
 	valuations language.Valuations,
 	expression language.Expression,
 ) {
-	interpreter := language.NewSymbolicInterpreter(
-		solver.context, solver.variables, valuations,
-	)
+	interpreter := solver.symbolic(valuations)
 	interpreter.Expression(expression)
+	solver.collect(interpreter)
 }
 
 func (solver *Interpreter) IsSatisfied(
 	valuations language.Valuations,
 	expression language.Expression,
 ) bool {
-	interpreter := language.NewSymbolicInterpreter(
-		solver.context, solver.variables, valuations.Copy(),
-	)
-	return interpreter.Satisfies(expression)
+	interpreter := solver.symbolic(valuations.Copy())
+	satisfied := interpreter.Satisfies(expression)
+	solver.collect(interpreter)
+	return satisfied
 }
 
 func (interpreter *Interpreter) IsSatisfiable(
@@ -43,7 +74,10 @@ func (interpreter *Interpreter) IsSatisfiable(
 	translator := language.NewZ3Translator(
 		interpreter.context, interpreter.variables,
 	)
-	translation := translator.Translate(expression)
+	// The calls are inlined as they cannot be translated when they read variables.
+	symbolic := interpreter.symbolic(language.NewValuationsMap())
+	translation := translator.Translate(symbolic.Inline(expression))
+	interpreter.collect(symbolic)
 	solver := interpreter.context.NewSolver()
-	return solver.HasSolutionFor(translation)
+	return solver.HasSolutionFor(z3.And(translation, translator.Ranges()))
 }
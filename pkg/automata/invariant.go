@@ -17,6 +17,10 @@ func NewInvariant(condition language.Expression) Invariant {
 	}
 }
 
+func (invariant Invariant) Condition() language.Expression {
+	return invariant.condition
+}
+
 func NewTrueInvariant() Invariant {
 	return NewInvariant(language.NewTrue())
 }
@@ -25,6 +29,16 @@ func NewFalseInvariant() Invariant {
 	return NewInvariant(language.NewFalse())
 }
 
+// Returns the invariant which is satisfied when all the invariants are satisfied.
+func (invariant Invariant) Conjunction(invariants ...Invariant) Invariant {
+	conditions := make([]language.Expression, len(invariants))
+	for idx := range invariants {
+		conditions[idx] = invariants[idx].condition
+	}
+	conjunction := language.Conjunction(invariant.condition, conditions...)
+	return NewInvariant(conjunction)
+}
+
 func (invariant Invariant) IsSatisfiable(valuations language.Valuations, solver *Interpreter) bool {
 	return solver.IsSatisfied(valuations, invariant.condition)
 }
@@ -5,6 +5,7 @@ import (
 	"io"
 	"slices"
 
+	"github.com/Brandhoej/gobion/pkg/graph"
 	"github.com/Brandhoej/gobion/pkg/symbols"
 )
 
@@ -22,6 +23,21 @@ func NewIOAutomaton(automaton *Automaton[Location, IOEdge], inputs, outputs []Ac
 	}
 }
 
+func (automaton IOAutomaton) Inputs() []Action {
+	return automaton.inputs
+}
+
+func (automaton IOAutomaton) Outputs() []Action {
+	return automaton.outputs
+}
+
+// Returns both the inputs and outputs of the automaton.
+func (automaton IOAutomaton) Actions() []Action {
+	actions := make([]Action, 0, len(automaton.inputs)+len(automaton.outputs))
+	actions = append(actions, automaton.inputs...)
+	return append(actions, automaton.outputs...)
+}
+
 func (automaton IOAutomaton) IsInput(action Action) bool {
 	return slices.Contains(automaton.inputs, action)
 }
@@ -54,6 +70,24 @@ func (automaton IOAutomaton) Outgoing(location symbols.Symbol, action Action) []
 	return edges
 }
 
+// Returns the automaton without actions such that it can be explored by a SymbolicTransitionSystem.
+func (automaton IOAutomaton) Symbolic() *SymbolicAutomaton {
+	locations := graph.NewVertexMap[symbols.Symbol, Location]()
+	automaton.Locations(func(key symbols.Symbol, location Location) bool {
+		locations.Add(location, key)
+		return true
+	})
+
+	edges := graph.NewEdgesMap[symbols.Symbol, Edge]()
+	automaton.Edges(func(edge IOEdge) bool {
+		edges.Connect(edge.Edge)
+		return true
+	})
+
+	dg := graph.NewLabeledDirected[symbols.Symbol, Edge, Location](locations, edges)
+	return NewSymbolicAutomaton(*NewAutomaton(dg, automaton.initial))
+}
+
 func (automaton *IOAutomaton) DOT(writer io.Writer, store symbols.Store[any]) {
 	// TODO: Merge edges with no update and guard.
 	// TODO: Mark the initial location with an arrow inwards.
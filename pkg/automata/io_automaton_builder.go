@@ -0,0 +1,68 @@
+package automata
+
+import (
+	"github.com/Brandhoej/gobion/pkg/graph"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+type IOAutomatonBuilder struct {
+	initial   symbols.Symbol
+	inputs    []Action
+	outputs   []Action
+	locations graph.Vertices[symbols.Symbol, Location]
+	edges     graph.Edges[symbols.Symbol, IOEdge]
+	factory   *symbols.SymbolsFactory
+}
+
+func NewIOAutomatonBuilder() *IOAutomatonBuilder {
+	return &IOAutomatonBuilder{
+		inputs:    make([]Action, 0),
+		outputs:   make([]Action, 0),
+		locations: graph.NewVertexMap[symbols.Symbol, Location](),
+		edges:     graph.NewEdgesMap[symbols.Symbol, IOEdge](),
+		factory:   symbols.NewSymbolsFactory(),
+	}
+}
+
+func (builder *IOAutomatonBuilder) AddInputs(actions ...Action) {
+	builder.inputs = append(builder.inputs, actions...)
+}
+
+func (builder *IOAutomatonBuilder) AddOutputs(actions ...Action) {
+	builder.outputs = append(builder.outputs, actions...)
+}
+
+func (builder *IOAutomatonBuilder) AddLocation(name string, configs ...LocationConfiguration) symbols.Symbol {
+	config := NewLocationConfig(configs...)
+	location := NewLocation(name, config.invariant)
+	location.urgency = config.urgency
+	symbol := symbols.Symbol(builder.factory.Next())
+	key := builder.locations.Add(location, symbol)
+	return key
+}
+
+func (builder *IOAutomatonBuilder) AddInitial(name string, configs ...LocationConfiguration) symbols.Symbol {
+	key := builder.AddLocation(name, configs...)
+	builder.initial = key
+	return key
+}
+
+// Makes the already added location the initial location.
+func (builder *IOAutomatonBuilder) SetInitial(location symbols.Symbol) {
+	builder.initial = location
+}
+
+func (builder *IOAutomatonBuilder) AddEdge(source symbols.Symbol, action Action, destination symbols.Symbol, configs ...EdgeConfiguration) {
+	config := NewEdgeConfig(configs...)
+	edge := NewIOEdge(source, action, config.guard, config.update, destination)
+	builder.edges.Connect(edge)
+}
+
+func (builder *IOAutomatonBuilder) AddLoop(location symbols.Symbol, action Action, configs ...EdgeConfiguration) {
+	builder.AddEdge(location, action, location, configs...)
+}
+
+func (builder *IOAutomatonBuilder) Build() IOAutomaton {
+	dg := graph.NewLabeledDirected(builder.locations, builder.edges)
+	return *NewIOAutomaton(NewAutomaton(dg, builder.initial), builder.inputs, builder.outputs)
+}
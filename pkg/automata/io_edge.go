@@ -15,3 +15,7 @@ func NewIOEdge(
 		action: action,
 	}
 }
+
+func (edge IOEdge) Action() Action {
+	return edge.action
+}
@@ -0,0 +1,197 @@
+package language
+
+import "github.com/Brandhoej/gobion/pkg/symbols"
+
+// The variables and clocks which are read and written by expressions. Variables are written by assignments
+// and clocks by clock statements whilst all other occurrences of variables and clocks are reads.
+type Accesses struct {
+	reads, writes map[symbols.Symbol]bool
+}
+
+func NewAccesses(expressions ...Expression) Accesses {
+	accesses := Accesses{
+		reads:  map[symbols.Symbol]bool{},
+		writes: map[symbols.Symbol]bool{},
+	}
+	for idx := range expressions {
+		expressions[idx].Accept(accesses)
+	}
+	return accesses
+}
+
+func (accesses Accesses) Reads(symbol symbols.Symbol) bool {
+	return accesses.reads[symbol]
+}
+
+func (accesses Accesses) Writes(symbol symbols.Symbol) bool {
+	return accesses.writes[symbol]
+}
+
+// Returns all read and written symbols.
+func (accesses Accesses) All(yield func(symbol symbols.Symbol) bool) bool {
+	for symbol := range accesses.reads {
+		if !yield(symbol) {
+			return false
+		}
+	}
+	for symbol := range accesses.writes {
+		if !accesses.reads[symbol] && !yield(symbol) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if one of the accesses writes a symbol which the other reads or writes.
+func (lhs Accesses) Conflicts(rhs Accesses) bool {
+	for symbol := range lhs.writes {
+		if rhs.reads[symbol] || rhs.writes[symbol] {
+			return true
+		}
+	}
+	for symbol := range rhs.writes {
+		if lhs.reads[symbol] {
+			return true
+		}
+	}
+	return false
+}
+
+func (accesses Accesses) Variable(variable Variable) {
+	accesses.reads[variable.symbol] = true
+}
+
+func (accesses Accesses) Binary(binary Binary) {
+	binary.lhs.Accept(accesses)
+	binary.rhs.Accept(accesses)
+}
+
+func (accesses Accesses) Integer(integer Integer) {}
+
+func (accesses Accesses) Boolean(boolean Boolean) {}
+
+func (accesses Accesses) Unary(unary Unary) {
+	unary.operand.Accept(accesses)
+}
+
+func (accesses Accesses) IfThenElse(ite IfThenElse) {
+	ite.condition.Accept(accesses)
+	ite.consequence.Accept(accesses)
+	ite.alternative.Accept(accesses)
+}
+
+func (accesses Accesses) BlockExpression(block BlockExpression) {
+	for idx := range block.statements {
+		block.statements[idx].Accept(accesses)
+	}
+	block.expression.Accept(accesses)
+}
+
+func (accesses Accesses) ClockConstraint(constraint ClockConstraint) {
+	accesses.reads[constraint.lhs] = true
+	accesses.reads[constraint.rhs] = true
+}
+
+func (accesses Accesses) Index(index Index) {
+	index.array.Accept(accesses)
+	index.index.Accept(accesses)
+}
+
+func (accesses Accesses) Member(member Member) {
+	member.record.Accept(accesses)
+}
+
+func (accesses Accesses) Array(array Array) {
+	for idx := range array.elements {
+		array.elements[idx].Accept(accesses)
+	}
+}
+
+func (accesses Accesses) Record(record Record) {
+	for idx := range record.values {
+		record.values[idx].Accept(accesses)
+	}
+}
+
+// The arguments are read and so are the variables read by the function except its parameters and local variables.
+func (accesses Accesses) Call(call Call) {
+	for idx := range call.arguments {
+		call.arguments[idx].Accept(accesses)
+	}
+	body := NewAccesses()
+	for idx := range call.function.body {
+		call.function.body[idx].Accept(body)
+	}
+	for symbol := range body.reads {
+		if _, local := call.function.variables.Lookup(symbol); !local {
+			accesses.reads[symbol] = true
+		}
+	}
+}
+
+// The assigned variable is written whereas the variables of the value are read. The variable of an assigned
+// element or field is also read as its other elements and fields are kept.
+func (accesses Accesses) Assignment(assignment Assignment) {
+	if variable, ok := assignment.lhs.(Variable); ok {
+		accesses.writes[variable.symbol] = true
+	} else {
+		if variable, ok := assignment.Variable(); ok {
+			accesses.writes[variable.symbol] = true
+		}
+		assignment.lhs.Accept(accesses)
+	}
+	assignment.rhs.Accept(accesses)
+}
+
+func (accesses Accesses) ClockAssignment(assignment ClockAssignment) {
+	accesses.writes[assignment.lhs] = true
+	accesses.reads[assignment.rhs] = true
+}
+
+func (accesses Accesses) ClockShift(shift ClockShift) {
+	accesses.reads[shift.clock] = true
+	accesses.writes[shift.clock] = true
+}
+
+func (accesses Accesses) ClockReset(reset ClockReset) {
+	accesses.writes[reset.clock] = true
+}
+
+func (accesses Accesses) Declaration(declaration Declaration) {
+	accesses.writes[declaration.symbol] = true
+	declaration.initial.Accept(accesses)
+}
+
+func (accesses Accesses) If(statement If) {
+	statement.condition.Accept(accesses)
+	for idx := range statement.consequence {
+		statement.consequence[idx].Accept(accesses)
+	}
+	for idx := range statement.alternative {
+		statement.alternative[idx].Accept(accesses)
+	}
+}
+
+func (accesses Accesses) While(loop While) {
+	loop.condition.Accept(accesses)
+	for idx := range loop.body {
+		loop.body[idx].Accept(accesses)
+	}
+}
+
+func (accesses Accesses) For(loop For) {
+	accesses.writes[loop.symbol] = true
+	for idx := range loop.body {
+		loop.body[idx].Accept(accesses)
+	}
+}
+
+func (accesses Accesses) Sequence(sequence Sequence) {
+	for idx := range sequence.statements {
+		sequence.statements[idx].Accept(accesses)
+	}
+}
+
+func (accesses Accesses) Return(statement Return) {
+	statement.expression.Accept(accesses)
+}
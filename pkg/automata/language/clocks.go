@@ -0,0 +1,166 @@
+package language
+
+import (
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// Represents the difference constraint "lhs - rhs ~ n" between two clocks.
+// Upper and lower bounds are expressed with the reference clock as rhs or lhs.
+type ClockConstraint struct {
+	lhs, rhs symbols.Symbol
+	relation zones.Relation
+}
+
+func NewClockConstraint(lhs, rhs symbols.Symbol, relation zones.Relation) ClockConstraint {
+	return ClockConstraint{
+		lhs:      lhs,
+		rhs:      rhs,
+		relation: relation,
+	}
+}
+
+func (constraint ClockConstraint) LHS() symbols.Symbol {
+	return constraint.lhs
+}
+
+func (constraint ClockConstraint) RHS() symbols.Symbol {
+	return constraint.rhs
+}
+
+func (constraint ClockConstraint) Relation() zones.Relation {
+	return constraint.relation
+}
+
+func (constraint ClockConstraint) Accept(visitor ExpressionVisitor) {
+	visitor.ClockConstraint(constraint)
+}
+
+// Represents the clock assignment "lhs := rhs".
+type ClockAssignment struct {
+	lhs, rhs symbols.Symbol
+}
+
+func NewClockAssignment(lhs, rhs symbols.Symbol) ClockAssignment {
+	return ClockAssignment{
+		lhs: lhs,
+		rhs: rhs,
+	}
+}
+
+func (assignment ClockAssignment) LHS() symbols.Symbol {
+	return assignment.lhs
+}
+
+func (assignment ClockAssignment) RHS() symbols.Symbol {
+	return assignment.rhs
+}
+
+func (assignment ClockAssignment) Accept(visitor StatementVisitor) {
+	visitor.ClockAssignment(assignment)
+}
+
+// Represents the compound assignment "clock := clock + limit".
+type ClockShift struct {
+	clock symbols.Symbol
+	limit int
+}
+
+func NewClockShift(clock symbols.Symbol, limit int) ClockShift {
+	return ClockShift{
+		clock: clock,
+		limit: limit,
+	}
+}
+
+func (shift ClockShift) Clock() symbols.Symbol {
+	return shift.clock
+}
+
+func (shift ClockShift) Limit() int {
+	return shift.limit
+}
+
+func (shift ClockShift) Accept(visitor StatementVisitor) {
+	visitor.ClockShift(shift)
+}
+
+// Represents the assignment "clock := limit".
+type ClockReset struct {
+	clock symbols.Symbol
+	limit int
+}
+
+func NewClockReset(clock symbols.Symbol, limit int) ClockReset {
+	return ClockReset{
+		clock: clock,
+		limit: limit,
+	}
+}
+
+func (reset ClockReset) Clock() symbols.Symbol {
+	return reset.clock
+}
+
+func (reset ClockReset) Limit() int {
+	return reset.limit
+}
+
+func (reset ClockReset) Accept(visitor StatementVisitor) {
+	visitor.ClockReset(reset)
+}
+
+// Maps clock symbols to the clocks (dimensions) of a DBM.
+type Clocks interface {
+	Declare(symbol symbols.Symbol) zones.Clock
+	Lookup(symbol symbols.Symbol) (clock zones.Clock, exists bool)
+	Dimensions() zones.Clock
+	All(yield func(symbol symbols.Symbol, clock zones.Clock) bool) bool
+}
+
+type ClocksMap struct {
+	clocks     map[symbols.Symbol]zones.Clock
+	dimensions zones.Clock
+}
+
+// Constructs a mapping where all references are symbols of the reference clock.
+func NewClocksMap(references ...symbols.Symbol) *ClocksMap {
+	mapping := &ClocksMap{
+		clocks:     map[symbols.Symbol]zones.Clock{},
+		dimensions: zones.Reference + 1,
+	}
+	for _, reference := range references {
+		mapping.clocks[reference] = zones.Reference
+	}
+	return mapping
+}
+
+// Declares the symbol as the next clock unless it is already declared.
+func (mapping *ClocksMap) Declare(symbol symbols.Symbol) zones.Clock {
+	if clock, exists := mapping.clocks[symbol]; exists {
+		return clock
+	}
+	clock := mapping.dimensions
+	mapping.clocks[symbol] = clock
+	mapping.dimensions += 1
+	return clock
+}
+
+func (mapping *ClocksMap) Lookup(symbol symbols.Symbol) (clock zones.Clock, exists bool) {
+	clock, exists = mapping.clocks[symbol]
+	return clock, exists
+}
+
+// Returns the number of clocks including the reference clock.
+func (mapping *ClocksMap) Dimensions() zones.Clock {
+	return mapping.dimensions
+}
+
+func (mapping *ClocksMap) All(yield func(symbol symbols.Symbol, clock zones.Clock) bool) bool {
+	for symbol, clock := range mapping.clocks {
+		if !yield(symbol, clock) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,5 +1,7 @@
 package language
 
+import "github.com/Brandhoej/gobion/pkg/symbols"
+
 type ConcreteInterpreter struct {
 	variables  Variables
 	valuations Valuations
@@ -47,10 +49,91 @@ func (interpreter ConcreteInterpreter) Interpret(expression Expression) Expressi
 		return interpreter.unary(cast)
 	case IfThenElse:
 		return interpreter.ifThenElse(cast)
+	case ClockConstraint:
+		// Clocks are interpreted by zones.
+		return NewTrue()
+	case Index:
+		return interpreter.index(cast)
+	case Member:
+		return interpreter.member(cast)
+	case Array:
+		elements := make([]Expression, len(cast.elements))
+		for idx := range elements {
+			elements[idx] = interpreter.Interpret(cast.elements[idx])
+		}
+		return NewArray(elements...)
+	case Record:
+		values := make([]Expression, len(cast.values))
+		for idx := range values {
+			values[idx] = interpreter.Interpret(cast.values[idx])
+		}
+		return NewRecord(cast.sort, values...)
+	case Call:
+		return interpreter.call(cast)
+	case BlockExpression:
+		return interpreter.blockExpression(cast)
 	}
 	panic("Unknown expression type")
 }
 
+func (interpreter ConcreteInterpreter) index(index Index) Expression {
+	array, isArray := interpreter.Interpret(index.array).(Array)
+	position, isInteger := interpreter.Interpret(index.index).(Integer)
+	if !isArray || !isInteger {
+		panic("Cannot perform indexing")
+	}
+	if position.value < 0 || position.value >= len(array.elements) {
+		panic("Index out of bounds")
+	}
+	return array.elements[position.value]
+}
+
+func (interpreter ConcreteInterpreter) member(member Member) Expression {
+	if record, ok := interpreter.Interpret(member.record).(Record); ok {
+		if value, exists := record.Value(member.field); exists {
+			return value
+		}
+	}
+	panic("Cannot perform member access")
+}
+
+// Returns true if the values are equal where arrays and records are equal if their elements and fields are.
+func (interpreter ConcreteInterpreter) equal(lhs, rhs Expression) (equal, ok bool) {
+	switch l := lhs.(type) {
+	case Integer:
+		r, ok := rhs.(Integer)
+		return ok && l.value == r.value, ok
+	case Boolean:
+		r, ok := rhs.(Boolean)
+		return ok && l.value == r.value, ok
+	case Array:
+		r, ok := rhs.(Array)
+		if !ok || len(l.elements) != len(r.elements) {
+			return false, false
+		}
+		return interpreter.equalAll(l.elements, r.elements)
+	case Record:
+		r, ok := rhs.(Record)
+		if !ok || !l.sort.Equals(r.sort) {
+			return false, false
+		}
+		return interpreter.equalAll(l.values, r.values)
+	}
+	return false, false
+}
+
+func (interpreter ConcreteInterpreter) equalAll(lhs, rhs []Expression) (equal, ok bool) {
+	equal = true
+	for idx := range lhs {
+		element, ok := interpreter.equal(lhs[idx], rhs[idx])
+		if !ok {
+			return false, false
+		}
+		equal = equal && element
+	}
+	return equal, true
+}
+
 func (interpreter ConcreteInterpreter) variable(variable Variable) Expression {
 	if valuation, exists := interpreter.valuations.Value(variable.Symbol()); exists {
 		return valuation
@@ -88,6 +171,11 @@ func (interpreter ConcreteInterpreter) binary(binary Binary) Expression {
 			return NewBoolean(r.Value() == l.Value())
 		}
 
+		// Equality between arrays or records
+		if equal, ok := interpreter.equal(lhs, rhs); ok {
+			return NewBoolean(equal)
+		}
+
 		panic("Cannot perform EQ")
 	case NotEqual:
 		lhs, rhs := interpreter.rightToLeft(binary.LHS(), binary.RHS())
@@ -102,6 +190,11 @@ func (interpreter ConcreteInterpreter) binary(binary Binary) Expression {
 			return NewBoolean(r.Value() != l.Value())
 		}
 
+		// Equality between arrays or records
+		if equal, ok := interpreter.equal(lhs, rhs); ok {
+			return NewBoolean(!equal)
+		}
+
 		panic("Cannot perform NEQ")
 	case LessThan:
 		lhs, rhs := interpreter.leftToRight(binary.LHS(), binary.RHS(), nil)
@@ -145,6 +238,13 @@ func (interpreter ConcreteInterpreter) binary(binary Binary) Expression {
 		}
 
 		panic("Cannot perform SUB")
+	case Multiplication, Division, Modulus, Minimum, Maximum, BitwiseAnd, BitwiseOr, BitwiseXor, LeftShift, RightShift:
+		lhs, rhs := interpreter.leftToRight(binary.LHS(), binary.RHS(), nil)
+		if l, okL, r, okR := CastBinary[Integer, Integer](lhs, rhs); okL && okR {
+			return NewInteger(interpreter.arithmetic(binary.Operator(), l.Value(), r.Value()))
+		}
+
+		panic("Cannot perform arithmetic")
 	case LogicalAnd:
 		lhs, rhs := interpreter.leftToRight(binary.LHS(), binary.RHS(), interpreter.isFalse)
 		if r, okR, l, okL := CastBinary[Boolean, Boolean](lhs, rhs); okR && okL {
@@ -177,6 +277,43 @@ func (interpreter ConcreteInterpreter) binary(binary Binary) Expression {
 	panic("Unknown binary operator")
 }
 
+// Bitwise operators are applied to the integers as 32-bit two's complement integers.
+func (interpreter ConcreteInterpreter) arithmetic(operator BinaryOperator, lhs, rhs int) int {
+	switch operator {
+	case Multiplication:
+		return lhs * rhs
+	case Division:
+		if rhs == 0 {
+			panic("Division by zero")
+		}
+		return lhs / rhs
+	case Modulus:
+		if rhs == 0 {
+			panic("Division by zero")
+		}
+		return lhs % rhs
+	case Minimum:
+		return min(lhs, rhs)
+	case Maximum:
+		return max(lhs, rhs)
+	case BitwiseAnd:
+		return int(int32(lhs) & int32(rhs))
+	case BitwiseOr:
+		return int(int32(lhs) | int32(rhs))
+	case BitwiseXor:
+		return int(int32(lhs) ^ int32(rhs))
+	case LeftShift, RightShift:
+		if rhs < 0 {
+			panic("Negative shift count")
+		}
+		if operator == LeftShift {
+			return int(int32(lhs) << rhs)
+		}
+		return int(int32(lhs) >> rhs)
+	}
+	panic("Unknown binary operator")
+}
+
 func (interpreter ConcreteInterpreter) integer(integer Integer) Expression {
 	return integer
 }
@@ -192,6 +329,14 @@ func (interpreter ConcreteInterpreter) unary(unary Unary) Expression {
 		if boolean, ok := operand.(Boolean); ok {
 			return NewBoolean(!boolean.Value())
 		}
+	case ArithmeticNegation:
+		if integer, ok := operand.(Integer); ok {
+			return NewInteger(-integer.Value())
+		}
+	case BitwiseNegation:
+		if integer, ok := operand.(Integer); ok {
+			return NewInteger(int(^int32(integer.Value())))
+		}
 	}
 	panic("Unknown unary operator")
 }
@@ -203,3 +348,114 @@ func (interpreter ConcreteInterpreter) ifThenElse(ite IfThenElse) Expression {
 	}
 	return interpreter.Interpret(ite.Alternative())
 }
+
+// Executes the statements of the block before its expression is interpreted in their scope.
+func (interpreter ConcreteInterpreter) blockExpression(block BlockExpression) Expression {
+	statements := append(block.statements[:len(block.statements):len(block.statements)], NewReturn(block.expression))
+	value, _ := interpreter.block(statements)
+	return value
+}
+
+// Executes the body of the function with its parameters assigned to the values of the arguments.
+// The valuations are copied such that the parameters and local variables do not outlive the call.
+func (interpreter ConcreteInterpreter) call(call Call) Expression {
+	valuations := interpreter.valuations.Copy()
+	for idx, parameter := range call.function.parameters {
+		valuations.Assign(parameter, interpreter.Interpret(call.arguments[idx]))
+	}
+	body := NewConcreteInterpreter(call.function.scope(interpreter.variables), valuations)
+	if value, returned := body.execute(call.function.body); returned {
+		return value
+	}
+	panic("The function did not return a value")
+}
+
+// Executes the statement on the valuations. Clock statements are ignored as clocks are interpreted by zones.
+func (interpreter ConcreteInterpreter) Statement(statement Statement) {
+	if _, returned := interpreter.block([]Statement{statement}); returned {
+		panic("Cannot return outside of a function")
+	}
+}
+
+// Executes the statements in a scope of their local variables.
+func (interpreter ConcreteInterpreter) block(statements []Statement) (value Expression, returned bool) {
+	return interpreter.scoped(NewVariablesMap(), func(scope ConcreteInterpreter) (Expression, bool) {
+		return scope.execute(statements)
+	})
+}
+
+// Executes in a scope of the local variables such that only the values of the variables outside of the scope outlive it.
+func (interpreter ConcreteInterpreter) scoped(
+	locals *VariablesMap, execute func(scope ConcreteInterpreter) (Expression, bool),
+) (value Expression, returned bool) {
+	scope := NewConcreteInterpreter(
+		scopedVariables{inner: locals, outer: interpreter.variables}, interpreter.valuations.Copy(),
+	)
+	value, returned = execute(scope)
+	scope.valuations.All(func(symbol symbols.Symbol, value Expression) bool {
+		if _, local := locals.Lookup(symbol); !local {
+			interpreter.valuations.Assign(symbol, value)
+		}
+		return true
+	})
+	return value, returned
+}
+
+// Executes the statements until a value is returned.
+func (interpreter ConcreteInterpreter) execute(statements []Statement) (value Expression, returned bool) {
+	for idx := range statements {
+		switch cast := any(statements[idx]).(type) {
+		case Assignment:
+			assignment := cast.Expand(interpreter.variables)
+			variable, _ := assignment.Variable()
+			interpreter.valuations.Assign(variable.symbol, interpreter.Interpret(assignment.rhs))
+		case ClockAssignment, ClockShift, ClockReset:
+			// Clocks are interpreted by zones.
+		case Declaration:
+			interpreter.variables.Declare(cast.symbol, cast.sort)
+			interpreter.valuations.Assign(cast.symbol, interpreter.Interpret(cast.initial))
+		case If:
+			branch := cast.alternative
+			if interpreter.Satisfies(cast.condition) {
+				branch = cast.consequence
+			}
+			if value, returned = interpreter.block(branch); returned {
+				return value, true
+			}
+		case While:
+			for interpreter.Satisfies(cast.condition) {
+				if value, returned = interpreter.block(cast.body); returned {
+					return value, true
+				}
+			}
+		case For:
+			if value, returned = interpreter.iterate(cast); returned {
+				return value, true
+			}
+		case Sequence:
+			if value, returned = interpreter.block(cast.statements); returned {
+				return value, true
+			}
+		case Return:
+			return interpreter.Interpret(cast.expression), true
+		default:
+			panic("Unknown statement type")
+		}
+	}
+	return nil, false
+}
+
+// Executes the body of the loop in a scope where the variable of the loop is declared.
+func (interpreter ConcreteInterpreter) iterate(loop For) (value Expression, returned bool) {
+	locals := NewVariablesMap()
+	locals.Declare(loop.symbol, IntegerSort)
+	return interpreter.scoped(locals, func(scope ConcreteInterpreter) (Expression, bool) {
+		for counter := loop.lower; counter <= loop.upper; counter++ {
+			scope.valuations.Assign(loop.symbol, NewInteger(counter))
+			if value, returned := scope.block(loop.body); returned {
+				return value, true
+			}
+		}
+		return nil, false
+	})
+}
@@ -10,6 +10,12 @@ type ExpressionVisitor interface {
 	Unary(unary Unary)
 	IfThenElse(ite IfThenElse)
 	BlockExpression(block BlockExpression)
+	ClockConstraint(constraint ClockConstraint)
+	Index(index Index)
+	Member(member Member)
+	Array(array Array)
+	Record(record Record)
+	Call(call Call)
 }
 
 type Expression interface {
@@ -92,8 +98,22 @@ const (
 	Addition         = BinaryOperator(8)
 	Subtraction      = BinaryOperator(9)
 	Implication      = BinaryOperator(10)
+	Multiplication   = BinaryOperator(11)
+	Division         = BinaryOperator(12) // Truncated towards zero.
+	Modulus          = BinaryOperator(13) // The remainder of the truncated division.
+	Minimum          = BinaryOperator(14)
+	Maximum          = BinaryOperator(15)
+	BitwiseAnd       = BinaryOperator(16)
+	BitwiseOr        = BinaryOperator(17)
+	BitwiseXor       = BinaryOperator(18)
+	LeftShift        = BinaryOperator(19)
+	RightShift       = BinaryOperator(20) // Arithmetic shift which preserves the sign.
 )
 
+// The width of integers in bitwise operations. Integers are truncated to
+// 32-bit two's complement before and sign-extended after the operation.
+const BitWidth = 32
+
 type Binary struct {
 	lhs, rhs Expression
 	operator BinaryOperator
@@ -152,7 +172,9 @@ func Disjunction(expression Expression, expressions ...Expression) (disjunction
 type UnaryOperator uint16
 
 const (
-	LogicalNegation = UnaryOperator(0)
+	LogicalNegation    = UnaryOperator(0)
+	ArithmeticNegation = UnaryOperator(1)
+	BitwiseNegation    = UnaryOperator(2)
 )
 
 type Unary struct {
@@ -183,6 +205,10 @@ func LogicalNegate(expression Expression) Unary {
 	return NewUnary(LogicalNegation, expression)
 }
 
+func Negate(expression Expression) Unary {
+	return NewUnary(ArithmeticNegation, expression)
+}
+
 func (binary Binary) Accept(visitor ExpressionVisitor) {
 	visitor.Binary(binary)
 }
@@ -230,3 +256,139 @@ func NewBlockExpression(expression Expression, statements ...Statement) BlockExp
 func (block BlockExpression) Accept(visitor ExpressionVisitor) {
 	visitor.BlockExpression(block)
 }
+
+// The element of an array at an index.
+type Index struct {
+	array, index Expression
+}
+
+func NewIndex(array, index Expression) Index {
+	return Index{
+		array: array,
+		index: index,
+	}
+}
+
+func (index Index) Array() Expression {
+	return index.array
+}
+
+func (index Index) Index() Expression {
+	return index.index
+}
+
+func (index Index) Accept(visitor ExpressionVisitor) {
+	visitor.Index(index)
+}
+
+// The field of a record with a name.
+type Member struct {
+	record Expression
+	field  string
+}
+
+func NewMember(record Expression, field string) Member {
+	return Member{
+		record: record,
+		field:  field,
+	}
+}
+
+func (member Member) Record() Expression {
+	return member.record
+}
+
+func (member Member) Field() string {
+	return member.field
+}
+
+func (member Member) Accept(visitor ExpressionVisitor) {
+	visitor.Member(member)
+}
+
+// An array of the values of its elements.
+type Array struct {
+	elements []Expression
+}
+
+func NewArray(elements ...Expression) Array {
+	if len(elements) == 0 {
+		panic("Arrays must have at least one element")
+	}
+	return Array{
+		elements: elements,
+	}
+}
+
+func (array Array) Elements() []Expression {
+	return array.elements
+}
+
+func (array Array) Accept(visitor ExpressionVisitor) {
+	visitor.Array(array)
+}
+
+// A record of the values of the fields of its sort in the order of the fields.
+type Record struct {
+	sort   Sort
+	values []Expression
+}
+
+func NewRecord(sort Sort, values ...Expression) Record {
+	if sort.kind != RecordKind || len(values) != len(sort.fields) {
+		panic("The values of a record must be the values of the fields of its sort")
+	}
+	return Record{
+		sort:   sort,
+		values: values,
+	}
+}
+
+func (record Record) Sort() Sort {
+	return record.sort
+}
+
+func (record Record) Values() []Expression {
+	return record.values
+}
+
+// Returns the value of the field with the name.
+func (record Record) Value(name string) (value Expression, exists bool) {
+	index, _, exists := record.sort.Field(name)
+	if !exists {
+		return nil, false
+	}
+	return record.values[index], true
+}
+
+func (record Record) Accept(visitor ExpressionVisitor) {
+	visitor.Record(record)
+}
+
+// The value returned by a function given the values of its arguments.
+type Call struct {
+	function  Function
+	arguments []Expression
+}
+
+func NewCall(function Function, arguments ...Expression) Call {
+	if len(arguments) != len(function.parameters) {
+		panic("The arguments of a call must be the arguments of the parameters of its function")
+	}
+	return Call{
+		function:  function,
+		arguments: arguments,
+	}
+}
+
+func (call Call) Function() Function {
+	return call.function
+}
+
+func (call Call) Arguments() []Expression {
+	return call.arguments
+}
+
+func (call Call) Accept(visitor ExpressionVisitor) {
+	visitor.Call(call)
+}
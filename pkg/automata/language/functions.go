@@ -0,0 +1,196 @@
+package language
+
+import "github.com/Brandhoej/gobion/pkg/symbols"
+
+// A side-effect free function whose body is executed with its parameters assigned to the arguments of a call.
+// The body can read global variables but only assign its parameters and local variables.
+type Function struct {
+	symbol     symbols.Symbol
+	sort       Sort
+	parameters []symbols.Symbol
+	variables  *VariablesMap
+	body       []Statement
+}
+
+// A parameter of a function with its sort.
+type Parameter struct {
+	symbol symbols.Symbol
+	sort   Sort
+}
+
+func NewParameter(symbol symbols.Symbol, sort Sort) Parameter {
+	return Parameter{
+		symbol: symbol,
+		sort:   sort,
+	}
+}
+
+func (parameter Parameter) Symbol() symbols.Symbol {
+	return parameter.symbol
+}
+
+func (parameter Parameter) Sort() Sort {
+	return parameter.sort
+}
+
+// Returns the function of the sort which returns a value by the body. Panics if the body
+// assigns other variables than the parameters and local variables or assigns clocks.
+func NewFunction(symbol symbols.Symbol, sort Sort, parameters []Parameter, body ...Statement) Function {
+	function := Function{
+		symbol:     symbol,
+		sort:       sort,
+		parameters: make([]symbols.Symbol, len(parameters)),
+		variables:  NewVariablesMap(),
+		body:       body,
+	}
+	for idx, parameter := range parameters {
+		function.parameters[idx] = parameter.symbol
+		function.variables.Declare(parameter.symbol, parameter.sort)
+	}
+	function.declare(body)
+
+	accesses := NewAccesses()
+	for idx := range body {
+		body[idx].Accept(accesses)
+	}
+	for symbol := range accesses.writes {
+		if _, exists := function.variables.Lookup(symbol); !exists {
+			panic("Functions can only assign their parameters and local variables")
+		}
+	}
+	return function
+}
+
+// Declares the local variables of the statements.
+func (function Function) declare(statements []Statement) {
+	for idx := range statements {
+		switch cast := any(statements[idx]).(type) {
+		case Declaration:
+			function.variables.Declare(cast.symbol, cast.sort)
+		case If:
+			function.declare(cast.consequence)
+			function.declare(cast.alternative)
+		case While:
+			function.declare(cast.body)
+		case For:
+			function.variables.Declare(cast.symbol, IntegerSort)
+			function.declare(cast.body)
+		case Sequence:
+			function.declare(cast.statements)
+		case ClockAssignment, ClockShift, ClockReset:
+			panic("Functions cannot assign clocks")
+		}
+	}
+}
+
+func (function Function) Symbol() symbols.Symbol {
+	return function.symbol
+}
+
+// Returns the sort of the values returned by the function.
+func (function Function) Sort() Sort {
+	return function.sort
+}
+
+func (function Function) Parameters() []Parameter {
+	parameters := make([]Parameter, len(function.parameters))
+	for idx, symbol := range function.parameters {
+		sort, _ := function.variables.Lookup(symbol)
+		parameters[idx] = NewParameter(symbol, sort)
+	}
+	return parameters
+}
+
+// Returns the parameters and local variables of the function.
+func (function Function) Variables() Variables {
+	return function.variables
+}
+
+func (function Function) Body() []Statement {
+	return function.body
+}
+
+// Returns the variables of the function in the scope of the variables where the parameters
+// and local variables of the function shadow the variables of the scope.
+func (function Function) scope(variables Variables) Variables {
+	return scopedVariables{
+		inner: function.variables,
+		outer: variables,
+	}
+}
+
+// Returns the variables of the inner scope which shadow the variables of the outer scope.
+func NewScopedVariables(inner *VariablesMap, outer Variables) Variables {
+	return scopedVariables{
+		inner: inner,
+		outer: outer,
+	}
+}
+
+type scopedVariables struct {
+	inner *VariablesMap
+	outer Variables
+}
+
+func (scope scopedVariables) Declare(symbol symbols.Symbol, sort Sort) {
+	scope.inner.Declare(symbol, sort)
+}
+
+func (scope scopedVariables) Lookup(symbol symbols.Symbol) (sort Sort, exists bool) {
+	if sort, exists = scope.inner.Lookup(symbol); exists {
+		return sort, exists
+	}
+	if scope.outer == nil {
+		return Sort{}, false
+	}
+	return scope.outer.Lookup(symbol)
+}
+
+func (scope scopedVariables) All(yield func(symbol symbols.Symbol, sort Sort) bool) bool {
+	if !scope.inner.All(yield) {
+		return false
+	}
+	if scope.outer == nil {
+		return true
+	}
+	return scope.outer.All(func(symbol symbols.Symbol, sort Sort) bool {
+		if _, shadowed := scope.inner.Lookup(symbol); shadowed {
+			return true
+		}
+		return yield(symbol, sort)
+	})
+}
+
+type Functions interface {
+	Declare(function Function)
+	Lookup(symbol symbols.Symbol) (function Function, exists bool)
+	All(yield func(function Function) bool) bool
+}
+
+type FunctionsMap struct {
+	functions map[symbols.Symbol]Function
+}
+
+func NewFunctionsMap() *FunctionsMap {
+	return &FunctionsMap{
+		functions: map[symbols.Symbol]Function{},
+	}
+}
+
+func (mapping *FunctionsMap) Declare(function Function) {
+	mapping.functions[function.symbol] = function
+}
+
+func (mapping *FunctionsMap) Lookup(symbol symbols.Symbol) (function Function, exists bool) {
+	function, exists = mapping.functions[symbol]
+	return function, exists
+}
+
+func (mapping *FunctionsMap) All(yield func(function Function) bool) bool {
+	for _, function := range mapping.functions {
+		if !yield(function) {
+			return false
+		}
+	}
+	return true
+}
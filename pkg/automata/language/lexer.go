@@ -0,0 +1,143 @@
+package language
+
+import (
+	"fmt"
+	"unicode"
+)
+
+type TokenKind uint16
+
+const (
+	EndOfInputToken = TokenKind(iota)
+	IdentifierToken
+	NumeralToken
+	// A name written in quotes which may contain any character except quotes and newlines.
+	NameToken
+	PunctuationToken
+)
+
+// The position of a token in the text which is being parsed.
+type Position struct {
+	Line, Column int
+}
+
+func (position Position) String() string {
+	return fmt.Sprintf("%d:%d", position.Line, position.Column)
+}
+
+type Token struct {
+	Kind     TokenKind
+	Text     string
+	Position Position
+}
+
+func (token Token) String() string {
+	switch token.Kind {
+	case EndOfInputToken:
+		return "end of input"
+	case NameToken:
+		return fmt.Sprintf("the name \"%s\"", token.Text)
+	}
+	return fmt.Sprintf("\"%s\"", token.Text)
+}
+
+// The punctuation ordered such that longer symbols are matched first. Most operators
+// have both the symbol written by the pretty printer and an ASCII alternative.
+var punctuation = []string{
+	"-->", "->", ":=", "+=", "-=", "++", "--", "<=", ">=", "==", "!=", "&&", "||", "<<", ">>", "<?", ">?",
+	"→", "≤", "≥", "≠", "∧", "∨", "¬", "∞",
+	"<", ">", "=", "!", "?", ":", ";", ",", "(", ")", "{", "}", "[", "]", "'", "+", "-",
+	"*", "/", "%", "&", "|", "^", "~", ".",
+}
+
+// Splits the text into tokens. Comments and whitespace are skipped.
+func tokenize(text string) (tokens []Token, err error) {
+	runes := []rune(text)
+	position := Position{Line: 1, Column: 1}
+	advance := func(count int) {
+		for idx := 0; idx < count; idx++ {
+			if runes[idx] == '\n' {
+				position.Line++
+				position.Column = 1
+			} else {
+				position.Column++
+			}
+		}
+		runes = runes[count:]
+	}
+	startsWith := func(prefix string) bool {
+		prefixRunes := []rune(prefix)
+		if len(prefixRunes) > len(runes) {
+			return false
+		}
+		for idx := range prefixRunes {
+			if runes[idx] != prefixRunes[idx] {
+				return false
+			}
+		}
+		return true
+	}
+	isPart := func(character rune) bool {
+		return unicode.IsLetter(character) || unicode.IsDigit(character) || character == '_' || character == '.'
+	}
+
+	for len(runes) > 0 {
+		start := position
+		switch {
+		case unicode.IsSpace(runes[0]):
+			advance(1)
+		case startsWith("//"):
+			for len(runes) > 0 && runes[0] != '\n' {
+				advance(1)
+			}
+		case startsWith("/*"):
+			advance(2)
+			for len(runes) > 0 && !startsWith("*/") {
+				advance(1)
+			}
+			if len(runes) == 0 {
+				return nil, ParseError{start, "unterminated comment"}
+			}
+			advance(2)
+		case runes[0] == '"':
+			length := 1
+			for length < len(runes) && runes[length] != '"' && runes[length] != '\n' {
+				length++
+			}
+			if length == len(runes) || runes[length] != '"' {
+				return nil, ParseError{start, "unterminated name"}
+			}
+			tokens = append(tokens, Token{NameToken, string(runes[1:length]), start})
+			advance(length + 1)
+		case unicode.IsDigit(runes[0]):
+			length := 0
+			for length < len(runes) && unicode.IsDigit(runes[length]) {
+				length++
+			}
+			tokens = append(tokens, Token{NumeralToken, string(runes[:length]), start})
+			advance(length)
+		case unicode.IsLetter(runes[0]) || runes[0] == '_':
+			length := 0
+			for length < len(runes) && isPart(runes[length]) {
+				length++
+			}
+			tokens = append(tokens, Token{IdentifierToken, string(runes[:length]), start})
+			advance(length)
+		default:
+			matched := false
+			for _, text := range punctuation {
+				if startsWith(text) {
+					tokens = append(tokens, Token{PunctuationToken, text, start})
+					advance(len([]rune(text)))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, ParseError{start, fmt.Sprintf("unexpected character '%c'", runes[0])}
+			}
+		}
+	}
+
+	return append(tokens, Token{EndOfInputToken, "", position}), nil
+}
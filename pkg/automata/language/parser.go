@@ -0,0 +1,1195 @@
+package language
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// The error of a text which could not be parsed.
+type ParseError struct {
+	Position Position
+	Message  string
+}
+
+func (err ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Position, err.Message)
+}
+
+// The words of expressions which cannot be used as identifiers.
+var keywords = map[string]bool{
+	"true": true, "false": true, "if": true, "then": true, "else": true,
+	"and": true, "or": true, "not": true, "imply": true,
+	"while": true, "do": true, "return": true, "for": true, "to": true,
+}
+
+// A recursive descent parser of expressions and statements. Errors are raised as panics
+// with a ParseError which are recovered by Parse. The methods for reading tokens are exported
+// such that languages which embed expressions, like the definitions of automata, can be parsed.
+type Parser struct {
+	tokens    []Token
+	index     int
+	symbols   symbols.Store[any]
+	clocks    Clocks
+	reference symbols.Symbol
+	resolve   func(token Token) symbols.Symbol
+	lookup    func(name string) (symbols.Symbol, bool)
+	variables Variables
+	functions Functions
+	constants Valuations
+	// The parameters and local variables of the function or the loop variables of the statements being parsed.
+	locals map[string]symbols.Symbol
+	scope  *VariablesMap
+	// True while the body of a function is parsed.
+	function bool
+}
+
+// Constructs a parser of the text where identifiers are registered as symbols in the store. Comparisons and
+// assignments of clocks are parsed as clock constraints and statements. The clocks may be nil if there are none.
+func NewParser(text string, store symbols.Store[any], clocks Clocks) (*Parser, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &Parser{
+		tokens:  tokens,
+		symbols: store,
+		clocks:  clocks,
+	}
+	parser.resolve = func(token Token) symbols.Symbol {
+		return store.Insert(token.Text)
+	}
+	parser.lookup = func(name string) (symbols.Symbol, bool) {
+		return store.Lookup(name)
+	}
+	if clocks != nil {
+		clocks.All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+			if clock == zones.Reference {
+				parser.reference = symbol
+				return false
+			}
+			return true
+		})
+	}
+	return parser, nil
+}
+
+// Parses the text as an expression or a sequence of statements separated by semicolons which may end with an
+// expression. Identifiers are registered as symbols of variables in the store. The syntax written by the pretty
+// printer is accepted as well as ASCII alternatives, such as "x <= 10 && y != 3" and "if c then a else b".
+func ParseExpression(text string, store symbols.Store[any]) (expression Expression, err error) {
+	parser, err := NewParser(text, store, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = parser.Parse(func(parser *Parser) {
+		expression = parser.Sequence(parser.IsEnd)
+		parser.Accept(";")
+	})
+	return expression, err
+}
+
+// Parses the text as a single assignment, such as "x' := x + 1" or "x = x + 1".
+func ParseStatement(text string, store symbols.Store[any]) (statement Statement, err error) {
+	parser, err := NewParser(text, store, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = parser.Parse(func(parser *Parser) {
+		statement = parser.Statement()
+		parser.Accept(";")
+	})
+	return statement, err
+}
+
+// Replaces how identifiers are resolved to symbols. By default, they are registered in the store.
+func (parser *Parser) SetResolver(resolve func(token Token) symbols.Symbol) {
+	parser.resolve = resolve
+}
+
+// Replaces how the names of records are looked up when identifiers "r.f" are split into their fields.
+// By default, they are looked up in the store.
+func (parser *Parser) SetLookup(lookup func(name string) (symbols.Symbol, bool)) {
+	parser.lookup = lookup
+}
+
+// Sets the variables whose sorts are used to parse the fields of records. Identifiers are lexed with
+// their dots such that "r.f" is the field f of r only if r is a declared variable of a record sort.
+func (parser *Parser) SetVariables(variables Variables) {
+	parser.variables = variables
+}
+
+// Sets the functions which can be called by the names of their symbols.
+func (parser *Parser) SetFunctions(functions Functions) {
+	parser.functions = functions
+}
+
+// Sets the constants whose values replace the variables of their symbols.
+func (parser *Parser) SetConstants(constants Valuations) {
+	parser.constants = constants
+}
+
+// Returns the variables and the parameters and local variables of the function being parsed.
+func (parser *Parser) sorts() Variables {
+	if parser.scope == nil {
+		return parser.variables
+	}
+	return scopedVariables{
+		inner: parser.scope,
+		outer: parser.variables,
+	}
+}
+
+// Parses the tokens by the rule and returns the ParseError if any. All tokens must be parsed by the rule.
+func (parser *Parser) Parse(rule func(parser *Parser)) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if parseError, ok := recovered.(ParseError); ok {
+				err = parseError
+				return
+			}
+			panic(recovered)
+		}
+	}()
+
+	rule(parser)
+	if token := parser.Peek(); token.Kind != EndOfInputToken {
+		parser.Fail(token, "unexpected %s", token)
+	}
+	return nil
+}
+
+func (parser *Parser) Fail(token Token, format string, arguments ...any) {
+	panic(ParseError{
+		Position: token.Position,
+		Message:  fmt.Sprintf(format, arguments...),
+	})
+}
+
+func (parser *Parser) Peek() Token {
+	return parser.tokens[parser.index]
+}
+
+func (parser *Parser) Lookahead(offset int) Token {
+	if parser.index+offset < len(parser.tokens) {
+		return parser.tokens[parser.index+offset]
+	}
+	return parser.tokens[len(parser.tokens)-1]
+}
+
+func (parser *Parser) Next() Token {
+	token := parser.tokens[parser.index]
+	if token.Kind != EndOfInputToken {
+		parser.index++
+	}
+	return token
+}
+
+// Returns true if the token is the end of the input.
+func (parser *Parser) IsEnd(token Token) bool {
+	return token.Kind == EndOfInputToken
+}
+
+// Returns true if the next token is an identifier or punctuation with one of the texts.
+func (parser *Parser) Is(texts ...string) bool {
+	token := parser.Peek()
+	if token.Kind != IdentifierToken && token.Kind != PunctuationToken {
+		return false
+	}
+	for _, text := range texts {
+		if token.Text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (parser *Parser) Accept(texts ...string) (Token, bool) {
+	if parser.Is(texts...) {
+		return parser.Next(), true
+	}
+	return Token{}, false
+}
+
+func (parser *Parser) Expect(text string) Token {
+	if token, ok := parser.Accept(text); ok {
+		return token
+	}
+	parser.Fail(parser.Peek(), "expected \"%s\" but found %s", text, parser.Peek())
+	return Token{}
+}
+
+// Returns the next token if it is an identifier which is not a keyword.
+func (parser *Parser) ExpectIdentifier() Token {
+	token := parser.Next()
+	if token.Kind != IdentifierToken || keywords[token.Text] {
+		parser.Fail(token, "expected an identifier but found %s", token)
+	}
+	return token
+}
+
+// Sequences of statements which may end with an expression:
+//
+//	sequence  := item {";" item}
+//	item      := statement | control | expression
+//	statement := identifier {selector} ["'"] ((":=" | "=" | "+=" | "-=") expression | "++" | "--")
+//	control   := "if" expression "then" block ["else" (block | "if" ...)]
+//	           | "while" expression "do" block
+//	           | "for" identifier ":=" integer "to" integer "do" block
+//	block     := "{" {(statement | control | block | local) [";"]} "}"
+//	local     := sort identifier dimensions [(":=" | "=") expression]
+//
+// Clocks cannot be assigned in the blocks of control statements. The local variables of the blocks are
+// declared in the control statement such that they cannot be declared twice in the same control statement. An "if" is a control statement
+// if its consequence is a block and otherwise the conditional expression "if c then a else b".
+//
+// The sequence continues after a semicolon unless the token following it satisfies the stop condition.
+// Only the last item can be an expression such that "v = e" is an assignment if it is followed by another
+// item. A sequence of statements is a block with the expression true and a single expression is itself.
+func (parser *Parser) Sequence(stop func(token Token) bool) Expression {
+	statements := make([]Statement, 0)
+	for {
+		if parser.isControl() {
+			statements = append(statements, parser.control())
+			if !parser.Is(";") || stop(parser.Lookahead(1)) {
+				return NewBlockExpression(NewTrue(), statements...)
+			}
+			parser.Next()
+			continue
+		}
+		if !parser.isStatement() {
+			start := parser.index
+			expression := parser.Expression()
+			if !parser.Is(";") || stop(parser.Lookahead(1)) {
+				if len(statements) == 0 {
+					return expression
+				}
+				return NewBlockExpression(expression, statements...)
+			}
+
+			parser.index = start
+			if !parser.isAssignment() {
+				parser.Fail(parser.Peek(), "expected a statement but found an expression")
+			}
+		}
+
+		statements = append(statements, parser.Statement())
+		if !parser.Is(";") || stop(parser.Lookahead(1)) {
+			return NewBlockExpression(NewTrue(), statements...)
+		}
+		parser.Next()
+	}
+}
+
+// Returns true if the next tokens can only be the start of a statement.
+func (parser *Parser) isStatement() bool {
+	if parser.Peek().Kind != IdentifierToken || keywords[parser.Peek().Text] {
+		return false
+	}
+	next := parser.Lookahead(parser.selectors(1))
+	return next.Kind == PunctuationToken &&
+		(next.Text == "'" || next.Text == ":=" || next.Text == "+=" || next.Text == "-=" ||
+			next.Text == "++" || next.Text == "--")
+}
+
+// Returns true if the next tokens are the start of a control statement rather than a conditional expression.
+func (parser *Parser) isControl() bool {
+	if parser.Is("while", "for") {
+		return true
+	}
+	if !parser.Is("if") {
+		return false
+	}
+	start := parser.index
+	defer func() {
+		parser.index = start
+	}()
+	parser.Next()
+	parser.Expression()
+	if _, ok := parser.Accept("then"); !ok || !parser.Is("{") {
+		return false
+	}
+	// The consequence "{f: e}" is a record.
+	next := parser.Lookahead(2)
+	return parser.Lookahead(1).Kind != IdentifierToken || next.Kind != PunctuationToken || next.Text != ":"
+}
+
+// Returns true if the next tokens are the start of a statement or an assignment written as "v = e".
+func (parser *Parser) isAssignment() bool {
+	if parser.isStatement() {
+		return true
+	}
+	next := parser.Lookahead(parser.selectors(1))
+	return parser.Peek().Kind == IdentifierToken && !keywords[parser.Peek().Text] &&
+		next.Kind == PunctuationToken && next.Text == "="
+}
+
+// Returns the offset of the first token after the selectors "[...]" and ".f" from the offset.
+func (parser *Parser) selectors(offset int) int {
+	for {
+		token := parser.Lookahead(offset)
+		switch {
+		case token.Kind == PunctuationToken && token.Text == "[":
+			for depth := 0; ; offset++ {
+				token := parser.Lookahead(offset)
+				if parser.IsEnd(token) {
+					return offset
+				}
+				if token.Kind == PunctuationToken && token.Text == "[" {
+					depth++
+				} else if token.Kind == PunctuationToken && token.Text == "]" {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			offset++
+		case token.Kind == PunctuationToken && token.Text == "." &&
+			parser.Lookahead(offset+1).Kind == IdentifierToken:
+			offset += 2
+		default:
+			return offset
+		}
+	}
+}
+
+func (parser *Parser) Statement() Statement {
+	name := parser.ExpectIdentifier()
+	lhs := parser.postfix(parser.variable(name))
+	variable, isVariable := lhs.(Variable)
+	isClock := isVariable && parser.isClock(variable.Symbol())
+	if primed, ok := parser.Accept("'"); ok && isClock {
+		parser.Fail(primed, "clocks are assigned without a prime")
+	}
+
+	var value Expression
+	switch operator := parser.Next(); operator.Text {
+	case ":=", "=":
+		value = parser.Expression()
+	case "+=":
+		value = NewBinary(lhs, Addition, parser.Expression())
+	case "-=":
+		value = NewBinary(lhs, Subtraction, parser.Expression())
+	case "++":
+		value = NewBinary(lhs, Addition, NewInteger(1))
+	case "--":
+		value = NewBinary(lhs, Subtraction, NewInteger(1))
+	default:
+		parser.Fail(operator, "expected an assignment but found %s", operator)
+	}
+
+	if isClock {
+		return parser.clockUpdate(name, variable.Symbol(), value)
+	}
+	if parser.hasClocks(value) {
+		parser.Fail(name, "clocks cannot be assigned to variables")
+	}
+	return NewAssignment(lhs, value)
+}
+
+// Expressions ordered by their precedence from lowest to highest:
+//
+//	expression  := implication ["?" expression ":" expression]
+//	implication := disjunction [("→" | "->" | "imply") implication]
+//	disjunction := conjunction {("∨" | "||" | "or") conjunction}
+//	conjunction := negation {("∧" | "&&" | "and") negation}
+//	negation    := "not" negation | bitwise
+//	bitwise     := comparison {("|" | "^" | "&") comparison}
+//	comparison  := minmax [("<" | "≤" | "<=" | "=" | "==" | "≠" | "!=" | "≥" | ">=" | ">") (minmax | "∞")]
+//	minmax      := shift {("<?" | ">?") shift}
+//	shift       := additive {("<<" | ">>") additive}
+//	additive    := term {("+" | "-") term}
+//	term        := unary {("*" | "/" | "%") unary}
+//	unary       := ("¬" | "!" | "-" | "~") unary | primary
+//	primary     := integer | "true" | "false" | (identifier | call | "(" expression ")") {selector} | "{" sequence "}"
+//	             | ("min" | "max") "(" expression "," expression ")"
+//	             | "[" expression {"," expression} "]" | "{" identifier ":" expression {"," identifier ":" expression} "}"
+//	             | "if" expression "then" expression "else" expression
+//	selector    := "[" expression "]" | "." identifier
+//	call        := identifier "(" [expression {"," expression}] ")"
+//
+// The bitwise operators are ordered by their precedence from "|" over "^" to "&".
+func (parser *Parser) Expression() Expression {
+	condition := parser.implication()
+	if _, ok := parser.Accept("?"); ok {
+		consequence := parser.Expression()
+		parser.Expect(":")
+		alternative := parser.Expression()
+		return NewIfThenElse(condition, consequence, alternative)
+	}
+	return condition
+}
+
+func (parser *Parser) implication() Expression {
+	lhs := parser.disjunction()
+	if _, ok := parser.Accept("→", "->", "imply"); ok {
+		return NewBinary(lhs, Implication, parser.implication())
+	}
+	return lhs
+}
+
+func (parser *Parser) disjunction() Expression {
+	lhs := parser.conjunction()
+	for {
+		if _, ok := parser.Accept("∨", "||", "or"); !ok {
+			return lhs
+		}
+		lhs = NewBinary(lhs, LogicalOr, parser.conjunction())
+	}
+}
+
+func (parser *Parser) conjunction() Expression {
+	lhs := parser.negation()
+	for {
+		if _, ok := parser.Accept("∧", "&&", "and"); !ok {
+			return lhs
+		}
+		lhs = NewBinary(lhs, LogicalAnd, parser.negation())
+	}
+}
+
+// The textual negation binds weaker than comparisons such that "not x > 3" is "¬(x > 3)".
+func (parser *Parser) negation() Expression {
+	if _, ok := parser.Accept("not"); ok {
+		return LogicalNegate(parser.negation())
+	}
+	return parser.bitwise(0)
+}
+
+// The bitwise operators from the lowest to the highest precedence.
+var bitwiseOperators = []struct {
+	text     string
+	operator BinaryOperator
+}{
+	{"|", BitwiseOr},
+	{"^", BitwiseXor},
+	{"&", BitwiseAnd},
+}
+
+// Parses the bitwise operators whose precedence is at least the precedence of the level.
+func (parser *Parser) bitwise(level int) Expression {
+	if level == len(bitwiseOperators) {
+		return parser.comparison()
+	}
+	lhs := parser.bitwise(level + 1)
+	for {
+		if _, ok := parser.Accept(bitwiseOperators[level].text); !ok {
+			return lhs
+		}
+		lhs = NewBinary(lhs, bitwiseOperators[level].operator, parser.bitwise(level+1))
+	}
+}
+
+var comparisons = map[string]BinaryOperator{
+	"<":  LessThan,
+	"≤":  LessThanEqual,
+	"<=": LessThanEqual,
+	"=":  Equal,
+	"==": Equal,
+	"≠":  NotEqual,
+	"!=": NotEqual,
+	"≥":  GreaterThanEqual,
+	">=": GreaterThanEqual,
+	">":  GreaterThan,
+}
+
+func (parser *Parser) comparison() Expression {
+	start := parser.Peek()
+	lhs := parser.minmax()
+	token, ok := parser.Accept("<", "≤", "<=", "=", "==", "≠", "!=", "≥", ">=", ">")
+	if !ok {
+		return lhs
+	}
+	operator := comparisons[token.Text]
+
+	if infinity, ok := parser.Accept("∞"); ok {
+		if operator != LessThan && operator != LessThanEqual {
+			parser.Fail(infinity, "clocks can only be less than infinity")
+		}
+		positive, negative, constant := parser.difference(start, lhs)
+		if constant != 0 || positive == negative {
+			parser.Fail(start, "clock constraints must be of the form \"x - y ~ n\"")
+		}
+		return NewClockConstraint(positive, negative, zones.NewInfinity())
+	}
+
+	rhs := parser.minmax()
+	if parser.hasClocks(lhs) || parser.hasClocks(rhs) {
+		return parser.clockConstraint(start, lhs, operator, rhs)
+	}
+	return NewBinary(lhs, operator, rhs)
+}
+
+// The minimum "<?" and maximum ">?" of UPPAAL.
+func (parser *Parser) minmax() Expression {
+	lhs := parser.shift()
+	for {
+		token, ok := parser.Accept("<?", ">?")
+		if !ok {
+			return lhs
+		}
+		operator := Minimum
+		if token.Text == ">?" {
+			operator = Maximum
+		}
+		lhs = NewBinary(lhs, operator, parser.shift())
+	}
+}
+
+func (parser *Parser) shift() Expression {
+	lhs := parser.additive()
+	for {
+		token, ok := parser.Accept("<<", ">>")
+		if !ok {
+			return lhs
+		}
+		operator := LeftShift
+		if token.Text == ">>" {
+			operator = RightShift
+		}
+		lhs = NewBinary(lhs, operator, parser.additive())
+	}
+}
+
+func (parser *Parser) additive() Expression {
+	lhs := parser.term()
+	for {
+		token, ok := parser.Accept("+", "-")
+		if !ok {
+			return lhs
+		}
+		operator := Addition
+		if token.Text == "-" {
+			operator = Subtraction
+		}
+		lhs = NewBinary(lhs, operator, parser.term())
+	}
+}
+
+var multiplicatives = map[string]BinaryOperator{
+	"*": Multiplication,
+	"/": Division,
+	"%": Modulus,
+}
+
+func (parser *Parser) term() Expression {
+	lhs := parser.unary()
+	for {
+		token, ok := parser.Accept("*", "/", "%")
+		if !ok {
+			return lhs
+		}
+		lhs = NewBinary(lhs, multiplicatives[token.Text], parser.unary())
+	}
+}
+
+func (parser *Parser) unary() Expression {
+	if _, ok := parser.Accept("¬", "!"); ok {
+		return LogicalNegate(parser.unary())
+	}
+	if _, ok := parser.Accept("-"); ok {
+		operand := parser.unary()
+		if integer, ok := operand.(Integer); ok {
+			return NewInteger(-integer.Value())
+		}
+		return Negate(operand)
+	}
+	if _, ok := parser.Accept("~"); ok {
+		return NewUnary(BitwiseNegation, parser.unary())
+	}
+	return parser.primary()
+}
+
+func (parser *Parser) primary() Expression {
+	start := parser.Next()
+	switch start.Kind {
+	case NumeralToken:
+		value, err := strconv.Atoi(start.Text)
+		if err != nil {
+			parser.Fail(start, "invalid integer %s", start)
+		}
+		return NewInteger(value)
+	case IdentifierToken:
+		switch start.Text {
+		case "true":
+			return NewTrue()
+		case "false":
+			return NewFalse()
+		case "if":
+			condition := parser.Expression()
+			parser.Expect("then")
+			consequence := parser.Expression()
+			parser.Expect("else")
+			return NewIfThenElse(condition, consequence, parser.Expression())
+		}
+		if keywords[start.Text] {
+			break
+		}
+		if start.Text == "min" || start.Text == "max" {
+			if _, ok := parser.Accept("("); ok {
+				lhs := parser.Expression()
+				parser.Expect(",")
+				rhs := parser.Expression()
+				parser.Expect(")")
+				if start.Text == "min" {
+					return NewBinary(lhs, Minimum, rhs)
+				}
+				return NewBinary(lhs, Maximum, rhs)
+			}
+		}
+		if parser.functions != nil && parser.Is("(") {
+			if _, local := parser.locals[start.Text]; !local {
+				return parser.postfix(parser.call(start))
+			}
+		}
+		return parser.postfix(parser.variable(start))
+	case PunctuationToken:
+		switch start.Text {
+		case "(":
+			expression := parser.Expression()
+			parser.Expect(")")
+			return parser.postfix(expression)
+		case "[":
+			elements := []Expression{parser.Expression()}
+			for {
+				if _, ok := parser.Accept(","); !ok {
+					break
+				}
+				elements = append(elements, parser.Expression())
+			}
+			parser.Expect("]")
+			return parser.postfix(NewArray(elements...))
+		case "{":
+			if next := parser.Lookahead(1); parser.Peek().Kind == IdentifierToken &&
+				next.Kind == PunctuationToken && next.Text == ":" {
+				return parser.record()
+			}
+			block := parser.Sequence(func(token Token) bool {
+				return token.Kind == PunctuationToken && token.Text == "}"
+			})
+			parser.Accept(";")
+			parser.Expect("}")
+			if _, ok := block.(BlockExpression); !ok {
+				block = NewBlockExpression(block)
+			}
+			return block
+		}
+	}
+	parser.Fail(start, "expected an expression but found %s", start)
+	return nil
+}
+
+// Returns the variable of the identifier or the fields of a record if the identifier is "r.f" where r is a record.
+// Constants are replaced by their values.
+func (parser *Parser) variable(token Token) Expression {
+	if symbol, exists := parser.locals[token.Text]; exists {
+		return NewVariable(symbol)
+	}
+	if variables := parser.sorts(); variables != nil && strings.Contains(token.Text, ".") {
+		parts := strings.Split(token.Text, ".")
+		for length := 1; length < len(parts); length++ {
+			name := strings.Join(parts[:length], ".")
+			symbol, exists := parser.locals[name]
+			if !exists {
+				if symbol, exists = parser.lookup(name); !exists {
+					continue
+				}
+			}
+			if sort, exists := variables.Lookup(symbol); exists && sort.Kind() == RecordKind {
+				expression := Expression(NewVariable(symbol))
+				for _, field := range parts[length:] {
+					expression = NewMember(expression, field)
+				}
+				return expression
+			}
+		}
+	}
+	symbol := parser.resolve(token)
+	if parser.constants != nil {
+		if value, exists := parser.constants.Value(symbol); exists {
+			return value
+		}
+	}
+	return NewVariable(symbol)
+}
+
+// Parses the arguments of the call of the function with the name.
+func (parser *Parser) call(name Token) Expression {
+	function, exists := parser.functions.Lookup(parser.resolve(name))
+	if !exists {
+		parser.Fail(name, "\"%s\" is not a function", name.Text)
+	}
+	parser.Expect("(")
+	arguments := []Expression{}
+	if _, ok := parser.Accept(")"); !ok {
+		for {
+			start := parser.Peek()
+			argument := parser.Expression()
+			if parser.hasClocks(argument) {
+				parser.Fail(start, "clocks cannot be passed to functions")
+			}
+			arguments = append(arguments, argument)
+			if _, ok := parser.Accept(","); !ok {
+				break
+			}
+		}
+		parser.Expect(")")
+	}
+	if len(arguments) != len(function.parameters) {
+		parser.Fail(name, "\"%s\" takes %d arguments but is given %d", name.Text, len(function.parameters), len(arguments))
+	}
+	return NewCall(function, arguments...)
+}
+
+// Parses the indices and fields following the expression.
+func (parser *Parser) postfix(expression Expression) Expression {
+	for {
+		if _, ok := parser.Accept("["); ok {
+			index := parser.Expression()
+			parser.Expect("]")
+			expression = NewIndex(expression, index)
+		} else if _, ok := parser.Accept("."); ok {
+			expression = NewMember(expression, parser.ExpectIdentifier().Text)
+		} else {
+			return expression
+		}
+	}
+}
+
+// Parses the fields of a record whose sort is given by the sorts of the values of the fields.
+func (parser *Parser) record() Expression {
+	fields, values := []Field{}, []Expression{}
+	for {
+		name := parser.ExpectIdentifier()
+		parser.Expect(":")
+		value := parser.Expression()
+		sort, exists := SortOf(parser.sorts(), value)
+		if !exists {
+			parser.Fail(name, "the sort of the field \"%s\" is unknown", name.Text)
+		}
+		for _, field := range fields {
+			if field.name == name.Text {
+				parser.Fail(name, "the field \"%s\" is given more than once", name.Text)
+			}
+		}
+		fields, values = append(fields, NewField(name.Text, sort)), append(values, value)
+		if _, ok := parser.Accept(","); !ok {
+			break
+		}
+	}
+	parser.Expect("}")
+	return NewRecord(NewRecordSort(fields...), values...)
+}
+
+func (parser *Parser) isClock(symbol symbols.Symbol) bool {
+	if parser.clocks == nil {
+		return false
+	}
+	_, exists := parser.clocks.Lookup(symbol)
+	return exists
+}
+
+func (parser *Parser) hasClocks(expression Expression) bool {
+	switch cast := any(expression).(type) {
+	case Variable:
+		return parser.isClock(cast.Symbol())
+	case Binary:
+		return parser.hasClocks(cast.LHS()) || parser.hasClocks(cast.RHS())
+	case Unary:
+		return parser.hasClocks(cast.Operand())
+	case IfThenElse:
+		return parser.hasClocks(cast.Condition()) ||
+			parser.hasClocks(cast.Consequence()) ||
+			parser.hasClocks(cast.Alternative())
+	}
+	return false
+}
+
+// Accumulates the coefficients of the clocks and the constant of a sum of clocks and integers.
+func (parser *Parser) linearize(
+	start Token, expression Expression, sign int, coefficients map[symbols.Symbol]int, constant *int,
+) {
+	switch cast := any(expression).(type) {
+	case Integer:
+		*constant += sign * cast.Value()
+		return
+	case Variable:
+		if parser.isClock(cast.Symbol()) {
+			coefficients[cast.Symbol()] += sign
+			return
+		}
+	case Unary:
+		if cast.Operator() == ArithmeticNegation {
+			parser.linearize(start, cast.Operand(), -sign, coefficients, constant)
+			return
+		}
+	case Binary:
+		if value, ok := parser.evaluate(start, cast); ok {
+			if integer, ok := value.(Integer); ok {
+				*constant += sign * integer.Value()
+				return
+			}
+		}
+		switch cast.Operator() {
+		case Addition:
+			parser.linearize(start, cast.LHS(), sign, coefficients, constant)
+			parser.linearize(start, cast.RHS(), sign, coefficients, constant)
+			return
+		case Subtraction:
+			parser.linearize(start, cast.LHS(), sign, coefficients, constant)
+			parser.linearize(start, cast.RHS(), -sign, coefficients, constant)
+			return
+		}
+	}
+	parser.Fail(start, "clocks can only be compared to clocks and integer constants")
+}
+
+// Returns the clocks and constant of the expression "positive - negative + constant"
+// where the reference clock is used for the clocks which are absent.
+func (parser *Parser) difference(
+	start Token, expression Expression,
+) (positive, negative symbols.Symbol, constant int) {
+	coefficients := map[symbols.Symbol]int{}
+	parser.linearize(start, expression, 1, coefficients, &constant)
+
+	positive, negative = parser.reference, parser.reference
+	for symbol, coefficient := range coefficients {
+		switch {
+		case coefficient == 0:
+			continue
+		case coefficient == 1 && positive == parser.reference:
+			positive = symbol
+		case coefficient == -1 && negative == parser.reference:
+			negative = symbol
+		default:
+			parser.Fail(start, "clock constraints must be of the form \"x - y ~ n\"")
+		}
+	}
+	return positive, negative, constant
+}
+
+// Translates the comparison "lhs ~ rhs" into clock constraints of the form "x - y ~ n".
+func (parser *Parser) clockConstraint(
+	start Token, lhs Expression, operator BinaryOperator, rhs Expression,
+) Expression {
+	// lhs - rhs ~ 0 is rewritten to "positive - negative ~ -constant".
+	positive, negative, constant := parser.difference(start, NewBinary(lhs, Subtraction, rhs))
+	limit := -constant
+
+	switch operator {
+	case LessThan:
+		return NewClockConstraint(positive, negative, zones.NewRelation(limit, zones.Strict))
+	case LessThanEqual:
+		return NewClockConstraint(positive, negative, zones.NewRelation(limit, zones.Weak))
+	case GreaterThan:
+		return NewClockConstraint(negative, positive, zones.NewRelation(-limit, zones.Strict))
+	case GreaterThanEqual:
+		return NewClockConstraint(negative, positive, zones.NewRelation(-limit, zones.Weak))
+	case Equal:
+		return NewBinary(
+			NewClockConstraint(positive, negative, zones.NewRelation(limit, zones.Weak)),
+			LogicalAnd,
+			NewClockConstraint(negative, positive, zones.NewRelation(-limit, zones.Weak)),
+		)
+	}
+	parser.Fail(start, "clocks cannot be compared by inequality")
+	return nil
+}
+
+// Translates the assignment of a clock into a reset "x := n", an assignment "x := y" or a shift "x := x + n".
+func (parser *Parser) clockUpdate(start Token, clock symbols.Symbol, value Expression) Statement {
+	coefficients, constant := map[symbols.Symbol]int{}, 0
+	parser.linearize(start, value, 1, coefficients, &constant)
+
+	source, assigned := symbols.Symbol(0), false
+	for symbol, coefficient := range coefficients {
+		switch {
+		case coefficient == 0:
+			continue
+		case coefficient == 1 && !assigned:
+			source, assigned = symbol, true
+		default:
+			parser.Fail(start, "clocks can only be assigned integers, clocks or be shifted")
+		}
+	}
+
+	switch {
+	case !assigned:
+		if constant < 0 {
+			parser.Fail(start, "clocks cannot be assigned negative values")
+		}
+		return NewClockReset(clock, constant)
+	case source == clock:
+		return NewClockShift(clock, constant)
+	case constant == 0:
+		return NewClockAssignment(clock, source)
+	}
+	parser.Fail(start, "clocks can only be assigned integers, clocks or be shifted")
+	return nil
+}
+
+// Sorts of variables where integers may be bounded and the lengths of arrays follow the names of variables:
+//
+//	sort       := "int" ["[" expression "," expression "]"] | "bool"
+//	            | "struct" "{" {sort identifier dimensions {"," identifier dimensions} ";"} "}"
+//	dimensions := {"[" expression "]"}
+//
+// The bounds and lengths are constant expressions.
+func (parser *Parser) Sort() Sort {
+	kind := parser.Next()
+	switch kind.Text {
+	case "bool":
+		return BooleanSort
+	case "struct":
+		parser.Expect("{")
+		fields, names := []Field{}, map[string]bool{}
+		for {
+			if _, ok := parser.Accept("}"); ok {
+				break
+			}
+			sort := parser.Sort()
+			for {
+				name := parser.ExpectIdentifier()
+				if names[name.Text] {
+					parser.Fail(name, "the field \"%s\" is already declared", name.Text)
+				}
+				names[name.Text] = true
+				fields = append(fields, NewField(name.Text, parser.Dimensions(sort)))
+				if _, ok := parser.Accept(","); !ok {
+					break
+				}
+			}
+			parser.Expect(";")
+		}
+		if len(fields) == 0 {
+			parser.Fail(kind, "records must have at least one field")
+		}
+		return NewRecordSort(fields...)
+	case "int":
+		if _, ok := parser.Accept("["); !ok {
+			return IntegerSort
+		}
+		lower := parser.integer()
+		parser.Expect(",")
+		upper := parser.integer()
+		if lower > upper {
+			parser.Fail(kind, "the lower bound %d is greater than the upper bound %d", lower, upper)
+		}
+		parser.Expect("]")
+		return NewBoundedIntegerSort(lower, upper)
+	}
+	parser.Fail(kind, "expected a sort but found %s", kind)
+	return Sort{}
+}
+
+// Returns true if the next token is the start of a sort.
+func (parser *Parser) IsSort() bool {
+	return parser.Is("int", "bool", "struct")
+}
+
+// Returns the sort of arrays of the sort with the lengths "[n]..." following the name of a variable or field.
+// As in C, "a[2][4]" is an array of two arrays of four elements.
+func (parser *Parser) Dimensions(sort Sort) Sort {
+	lengths := []int{}
+	for {
+		bracket, ok := parser.Accept("[")
+		if !ok {
+			break
+		}
+		length := parser.integer()
+		if length < 1 {
+			parser.Fail(bracket, "arrays must have at least one element")
+		}
+		parser.Expect("]")
+		lengths = append(lengths, length)
+	}
+	for idx := len(lengths) - 1; idx >= 0; idx-- {
+		sort = NewArraySort(sort, lengths[idx])
+	}
+	return sort
+}
+
+// Returns the value of the next expression which must be a constant integer.
+func (parser *Parser) integer() int {
+	token := parser.Peek()
+	value, _ := parser.evaluate(token, parser.Expression())
+	integer, ok := value.(Integer)
+	if !ok {
+		parser.Fail(token, "expected an integer")
+	}
+	return integer.Value()
+}
+
+// Returns the value of the next expression which must be a constant expression of literals and constants.
+func (parser *Parser) Constant() Expression {
+	token := parser.Peek()
+	value, ok := parser.evaluate(token, parser.Expression())
+	if !ok {
+		parser.Fail(token, "expected a constant expression")
+	}
+	return value
+}
+
+// Returns the value of the expression if it is a literal or an arithmetic expression of integers.
+func (parser *Parser) evaluate(start Token, expression Expression) (Expression, bool) {
+	switch cast := any(expression).(type) {
+	case Integer, Boolean:
+		return expression, true
+	case Binary:
+		lhs, _ := parser.evaluate(start, cast.LHS())
+		rhs, _ := parser.evaluate(start, cast.RHS())
+		lhsInteger, lhsOk := lhs.(Integer)
+		rhsInteger, rhsOk := rhs.(Integer)
+		if lhsOk && rhsOk {
+			operator := cast.Operator()
+			if (operator == Division || operator == Modulus) && rhsInteger.Value() == 0 {
+				parser.Fail(start, "division by zero in constant expression")
+			}
+			return NewConcreteInterpreter(nil, nil).Interpret(NewBinary(lhsInteger, operator, rhsInteger)), true
+		}
+	case Unary:
+		if operand, ok := parser.evaluate(start, cast.Operand()); ok {
+			if integer, ok := operand.(Integer); ok {
+				return NewConcreteInterpreter(nil, nil).Interpret(NewUnary(cast.Operator(), integer)), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Functions whose bodies are statements which can only assign the parameters and local variables:
+//
+//	function  := "(" [sort identifier dimensions {"," sort identifier dimensions}] ")" block
+//	block     := "{" {body [";"]} "}"
+//	body      := control | block
+//	           | "return" expression
+//	           | sort identifier dimensions [(":=" | "=") expression]
+//	           | statement
+//
+// Returns the function of the symbol and sort whose parameters and body follow its name.
+func (parser *Parser) Function(symbol symbols.Symbol, sort Sort) Function {
+	parser.locals, parser.scope, parser.function = map[string]symbols.Symbol{}, NewVariablesMap(), true
+	defer func() {
+		parser.locals, parser.scope, parser.function = nil, nil, false
+	}()
+
+	parser.Expect("(")
+	parameters := []Parameter{}
+	if _, ok := parser.Accept(")"); !ok {
+		for {
+			sort := parser.Sort()
+			name := parser.ExpectIdentifier()
+			sort = parser.Dimensions(sort)
+			parameters = append(parameters, NewParameter(parser.local(name, sort), sort))
+			if _, ok := parser.Accept(","); !ok {
+				break
+			}
+		}
+		parser.Expect(")")
+	}
+	return NewFunction(symbol, sort, parameters, parser.block()...)
+}
+
+// Declares the parameter or local variable of the function being parsed.
+func (parser *Parser) local(name Token, sort Sort) symbols.Symbol {
+	if _, exists := parser.locals[name.Text]; exists {
+		parser.Fail(name, "\"%s\" is already declared", name.Text)
+	}
+	symbol := parser.symbols.Insert(name.Text)
+	parser.locals[name.Text] = symbol
+	parser.scope.Declare(symbol, sort)
+	return symbol
+}
+
+func (parser *Parser) block() []Statement {
+	parser.Expect("{")
+	statements := []Statement{}
+	for {
+		if _, ok := parser.Accept("}"); ok {
+			return statements
+		}
+		statements = append(statements, parser.body())
+		parser.Accept(";")
+	}
+}
+
+func (parser *Parser) body() Statement {
+	start := parser.Peek()
+	switch {
+	case parser.Is("if", "while", "for"):
+		return parser.control()
+	case parser.Is("{"):
+		return NewSequence(parser.block()...)
+	case parser.function && parser.Is("return"):
+		parser.Next()
+		return NewReturn(parser.Expression())
+	case parser.IsSort() && !parser.isAssignment():
+		sort := parser.Sort()
+		name := parser.ExpectIdentifier()
+		sort = parser.Dimensions(sort)
+		var initial Expression
+		if _, ok := parser.Accept(":=", "="); ok {
+			initial = parser.Expression()
+		}
+		return NewDeclaration(parser.local(name, sort), sort, initial)
+	}
+
+	statement := parser.Statement()
+	assignment, ok := statement.(Assignment)
+	if !ok && parser.function {
+		parser.Fail(start, "functions cannot assign clocks")
+	} else if !ok {
+		parser.Fail(start, "clocks cannot be assigned in conditional statements and loops")
+	}
+	variable, _ := assignment.Variable()
+	if _, local := parser.scope.Lookup(variable.symbol); parser.function && !local {
+		parser.Fail(start, "functions can only assign their parameters and local variables")
+	}
+	return assignment
+}
+
+// Parses the control statement where the variables of loops are declared as local variables.
+func (parser *Parser) control() Statement {
+	if parser.locals == nil {
+		parser.locals, parser.scope = map[string]symbols.Symbol{}, NewVariablesMap()
+		defer func() {
+			parser.locals, parser.scope = nil, nil
+		}()
+	}
+
+	switch start := parser.Next(); start.Text {
+	case "if":
+		condition := parser.Expression()
+		parser.Expect("then")
+		consequence := parser.block()
+		var alternative []Statement
+		if _, ok := parser.Accept("else"); ok {
+			if parser.Is("if") {
+				alternative = append(alternative, parser.control())
+			} else {
+				alternative = parser.block()
+			}
+		}
+		return NewIf(condition, consequence, alternative)
+	case "while":
+		condition := parser.Expression()
+		parser.Expect("do")
+		return NewWhile(condition, parser.block()...)
+	case "for":
+		name := parser.ExpectIdentifier()
+		parser.Expect(":=")
+		lower := parser.integer()
+		parser.Expect("to")
+		upper := parser.integer()
+		parser.Expect("do")
+		symbol := parser.local(name, IntegerSort)
+		return NewFor(symbol, lower, upper, parser.block()...)
+	default:
+		parser.Fail(start, "expected a control statement but found %s", start)
+		return nil
+	}
+}
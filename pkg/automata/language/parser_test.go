@@ -0,0 +1,441 @@
+package language
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseExpression(t *testing.T) {
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x, y := NewVariable(symbolsMap.Insert("x")), NewVariable(symbolsMap.Insert("y"))
+	c := NewVariable(symbolsMap.Insert("c"))
+
+	tests := []struct {
+		name     string
+		text     string
+		expected Expression
+	}{
+		{
+			name: "ASCII operators",
+			text: "x <= 10 && y != 3",
+			expected: NewBinary(
+				NewBinary(x, LessThanEqual, NewInteger(10)),
+				LogicalAnd,
+				NewBinary(y, NotEqual, NewInteger(3)),
+			),
+		},
+		{
+			name: "Pretty printed operators",
+			text: "x ≤ 10 ∧ ¬(y ≠ 3)",
+			expected: NewBinary(
+				NewBinary(x, LessThanEqual, NewInteger(10)),
+				LogicalAnd,
+				LogicalNegate(NewBinary(y, NotEqual, NewInteger(3))),
+			),
+		},
+		{
+			name:     "If then else",
+			text:     "if c then x else y + 1",
+			expected: NewIfThenElse(c, x, NewBinary(y, Addition, NewInteger(1))),
+		},
+		{
+			name:     "Conditional",
+			text:     "c ? x : y",
+			expected: NewIfThenElse(c, x, y),
+		},
+		{
+			name: "Block",
+			text: "{ x = x + 1; true }",
+			expected: NewBlockExpression(
+				NewTrue(),
+				NewAssignment(x, NewBinary(x, Addition, NewInteger(1))),
+			),
+		},
+		{
+			name: "Statements",
+			text: "x' := 1; y -= x",
+			expected: NewBlockExpression(
+				NewTrue(),
+				NewAssignment(x, NewInteger(1)),
+				NewAssignment(y, NewBinary(y, Subtraction, x)),
+			),
+		},
+		{
+			name: "Left associativity",
+			text: "x - y - 1",
+			expected: NewBinary(
+				NewBinary(x, Subtraction, y),
+				Subtraction,
+				NewInteger(1),
+			),
+		},
+		{
+			name: "UPPAAL operators",
+			text: "not x > 3 and c imply y == 1 or c",
+			expected: NewBinary(
+				NewBinary(
+					LogicalNegate(NewBinary(x, GreaterThan, NewInteger(3))),
+					LogicalAnd,
+					c,
+				),
+				Implication,
+				NewBinary(NewBinary(y, Equal, NewInteger(1)), LogicalOr, c),
+			),
+		},
+		{
+			name: "Arithmetic precedence",
+			text: "-x * 2 + y % 3 << 1",
+			expected: NewBinary(
+				NewBinary(
+					NewBinary(Negate(x), Multiplication, NewInteger(2)),
+					Addition,
+					NewBinary(y, Modulus, NewInteger(3)),
+				),
+				LeftShift,
+				NewInteger(1),
+			),
+		},
+		{
+			name: "Bitwise precedence",
+			text: "x | y ^ ~x & 1 = 1",
+			expected: NewBinary(
+				x,
+				BitwiseOr,
+				NewBinary(
+					y,
+					BitwiseXor,
+					NewBinary(NewUnary(BitwiseNegation, x), BitwiseAnd, NewBinary(NewInteger(1), Equal, NewInteger(1))),
+				),
+			),
+		},
+		{
+			name:     "Minimum and maximum",
+			text:     "min(x, max(y, 0)) / 2",
+			expected: NewBinary(NewBinary(x, Minimum, NewBinary(y, Maximum, NewInteger(0))), Division, NewInteger(2)),
+		},
+		{
+			name:     "UPPAAL minimum and maximum",
+			text:     "x <? y >? 0 < 2",
+			expected: NewBinary(NewBinary(NewBinary(x, Minimum, y), Maximum, NewInteger(0)), LessThan, NewInteger(2)),
+		},
+		{
+			name: "Increments",
+			text: "x++; y--",
+			expected: NewBlockExpression(
+				NewTrue(),
+				NewAssignment(x, NewBinary(x, Addition, NewInteger(1))),
+				NewAssignment(y, NewBinary(y, Subtraction, NewInteger(1))),
+			),
+		},
+		{
+			name: "Right associative implication",
+			text: "c -> c → c",
+			expected: NewBinary(
+				c,
+				Implication,
+				NewBinary(c, Implication, c),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			expression, err := ParseExpression(tt.text, symbolsMap)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expression)
+		})
+	}
+}
+
+func Test_ParseExpressionPrettyPrinted(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x, y := NewVariable(symbolsMap.Insert("x")), NewVariable(symbolsMap.Insert("y"))
+	expression := NewBinary(
+		NewIfThenElse(
+			NewBinary(x, GreaterThan, y),
+			NewBinary(x, Subtraction, NewBinary(y, Subtraction, NewInteger(1))),
+			NewInteger(-1),
+		),
+		Equal,
+		NewBlockExpression(
+			NewBinary(y, GreaterThanEqual, NewInteger(0)),
+			NewAssignment(y, NewBinary(y, Addition, NewInteger(1))),
+		),
+	)
+	var buffer bytes.Buffer
+	expression.Accept(NewPrettyPrinter(&buffer, symbolsMap))
+
+	// Act
+	parsed, err := ParseExpression(buffer.String(), symbolsMap)
+
+	// Assert
+	assert.Equal(t, "(x > y ? x - (y - 1) : -1) = {y' := y + 1; y ≥ 0}", buffer.String())
+	assert.NoError(t, err)
+	assert.Equal(t, expression, parsed)
+}
+
+func Test_ParseArithmeticPrettyPrinted(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x, y := NewVariable(symbolsMap.Insert("x")), NewVariable(symbolsMap.Insert("y"))
+	expression := NewBinary(
+		Negate(NewBinary(x, Addition, y)),
+		Multiplication,
+		NewBinary(
+			NewBinary(x, BitwiseAnd, NewInteger(3)),
+			BitwiseOr,
+			NewBinary(y, Minimum, NewBinary(x, RightShift, NewInteger(1))),
+		),
+	)
+	var buffer bytes.Buffer
+	expression.Accept(NewPrettyPrinter(&buffer, symbolsMap))
+
+	// Act
+	parsed, err := ParseExpression(buffer.String(), symbolsMap)
+
+	// Assert
+	assert.Equal(t, "-(x + y) * (x & 3 | min(y, x >> 1))", buffer.String())
+	assert.NoError(t, err)
+	assert.Equal(t, expression, parsed)
+}
+
+func Test_ParseStatement(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x := NewVariable(symbolsMap.Insert("x"))
+
+	// Act
+	statement, err := ParseStatement("x = x + 1", symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, NewAssignment(x, NewBinary(x, Addition, NewInteger(1))), statement)
+}
+
+func Test_ParseConstants(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	clocks := NewClocksMap(reference)
+	x, n := symbolsMap.Insert("x"), symbolsMap.Insert("N")
+	clocks.Declare(x)
+	constants := NewValuationsMap()
+	constants.Assign(n, NewInteger(5))
+	parser, _ := NewParser("x <= N * 2 / 3 && N > 1", symbolsMap, clocks)
+	parser.SetConstants(constants)
+
+	// Act
+	var expression Expression
+	err := parser.Parse(func(parser *Parser) {
+		expression = parser.Expression()
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, NewBinary(
+		NewClockConstraint(x, reference, zones.NewRelation(3, zones.Weak)),
+		LogicalAnd,
+		NewBinary(NewInteger(5), GreaterThan, NewInteger(1)),
+	), expression)
+}
+
+func Test_ParseSelectors(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	a, r, i := symbolsMap.Insert("a"), symbolsMap.Insert("r"), symbolsMap.Insert("i")
+	variables := NewVariablesMap()
+	variables.Declare(a, NewArraySort(NewArraySort(IntegerSort, 3), 2))
+	variables.Declare(r, NewRecordSort(NewField("x", IntegerSort), NewField("b", BooleanSort)))
+	variables.Declare(i, IntegerSort)
+	parser, _ := NewParser("a[i][1] := r.x + [1, 2][i]; r.b", symbolsMap, nil)
+	parser.SetVariables(variables)
+
+	// Act
+	var expression Expression
+	err := parser.Parse(func(parser *Parser) {
+		expression = parser.Sequence(parser.IsEnd)
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, NewBlockExpression(
+		NewMember(NewVariable(r), "b"),
+		NewAssignment(
+			NewIndex(NewIndex(NewVariable(a), NewVariable(i)), NewInteger(1)),
+			NewBinary(
+				NewMember(NewVariable(r), "x"),
+				Addition,
+				NewIndex(NewArray(NewInteger(1), NewInteger(2)), NewVariable(i)),
+			),
+		),
+	), expression)
+}
+
+func Test_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "Missing parenthesis",
+			text:     "(x <= 3",
+			expected: "1:8: expected \")\" but found end of input",
+		},
+		{
+			name:     "Missing else",
+			text:     "if x then\n  y",
+			expected: "2:4: expected \"else\" but found end of input",
+		},
+		{
+			name:     "Expression before statement",
+			text:     "{ x > 0; y := 1 }",
+			expected: "1:3: expected a statement but found an expression",
+		},
+		{
+			name:     "Unbounded for loop",
+			text:     "for i := 0 to n do { x := i }",
+			expected: "1:15: expected an integer",
+		},
+		{
+			name:     "Return outside of a function",
+			text:     "while x > 0 do { return x }",
+			expected: "1:18: expected an identifier but found \"return\"",
+		},
+		{
+			name:     "Unexpected character",
+			text:     "x # y",
+			expected: "1:3: unexpected character '#'",
+		},
+		{
+			name:     "Trailing tokens",
+			text:     "x y",
+			expected: "1:3: unexpected \"y\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+
+			// Act
+			_, err := ParseExpression(tt.text, symbolsMap)
+
+			// Assert
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func Test_ParseFunction(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	y := symbolsMap.Insert("y")
+	variables := NewVariablesMap()
+	variables.Declare(y, IntegerSort)
+	valuations := NewValuationsMap()
+	valuations.Assign(y, NewInteger(3))
+	text := "(int n, bool b) { int s := 0; while n > 0 do { s := s + y; n := n - 1 }; " +
+		"if b then { return s } else if s > 5 then { return 5 }; return 0 }"
+	parser, _ := NewParser(text, symbolsMap, nil)
+	parser.SetVariables(variables)
+
+	// Act
+	var function Function
+	err := parser.Parse(func(parser *Parser) {
+		function = parser.Function(symbolsMap.Insert("f"), IntegerSort)
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, function.Parameters(), 2)
+	concrete := NewConcreteInterpreter(variables, valuations)
+	assert.Equal(t, NewInteger(6), concrete.Interpret(NewCall(function, NewInteger(2), NewTrue())))
+	assert.Equal(t, NewInteger(5), concrete.Interpret(NewCall(function, NewInteger(2), NewFalse())))
+	assert.Equal(t, NewInteger(0), concrete.Interpret(NewCall(function, NewInteger(1), NewFalse())))
+}
+
+func Test_ParseControlStatementsPrettyPrinted(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x, y, k := NewVariable(symbolsMap.Insert("x")), NewVariable(symbolsMap.Insert("y")), symbolsMap.Insert("k")
+	expression := NewBlockExpression(
+		NewTrue(),
+		NewIf(
+			NewBinary(x, GreaterThan, NewInteger(0)),
+			[]Statement{NewAssignment(y, NewInteger(1))},
+			[]Statement{NewIf(NewBinary(x, Equal, NewInteger(0)), []Statement{NewAssignment(y, NewInteger(2))}, nil)},
+		),
+		NewFor(k, 1, 3, NewAssignment(y, NewBinary(y, Addition, NewVariable(k)))),
+		NewWhile(
+			NewBinary(y, GreaterThan, NewInteger(4)),
+			NewAssignment(y, NewBinary(y, Subtraction, NewInteger(1))),
+		),
+	)
+	var buffer bytes.Buffer
+	expression.Accept(NewPrettyPrinter(&buffer, symbolsMap))
+
+	// Act
+	parsed, err := ParseExpression(buffer.String(), symbolsMap)
+
+	// Assert
+	assert.Equal(t,
+		"if x > 0 then {y' := 1} else {if x = 0 then {y' := 2}}; "+
+			"for k := 1 to 3 do {y' := y + k}; while y > 4 do {y' := y - 1}",
+		buffer.String(),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, expression, parsed)
+}
+
+func Test_ParseConditionalExpressionAfterStatement(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x, y := NewVariable(symbolsMap.Insert("x")), NewVariable(symbolsMap.Insert("y"))
+
+	// Act
+	parsed, err := ParseExpression("x := 1; if x > 0 then ({y := 1; y > 0}) else false", symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, NewBlockExpression(
+		NewIfThenElse(
+			NewBinary(x, GreaterThan, NewInteger(0)),
+			NewBlockExpression(NewBinary(y, GreaterThan, NewInteger(0)), NewAssignment(y, NewInteger(1))),
+			NewFalse(),
+		),
+		NewAssignment(x, NewInteger(1)),
+	), parsed)
+}
+
+func Test_ParseLocalsInControlStatements(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x, y := NewVariable(symbolsMap.Insert("x")), NewVariable(symbolsMap.Insert("y"))
+
+	// Act
+	parsed, err := ParseExpression("if x > y then {int t := x; x := y; y := t}", symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	temporary, _ := symbolsMap.Lookup("t")
+	assert.Equal(t, NewBlockExpression(
+		NewTrue(),
+		NewIf(
+			NewBinary(x, GreaterThan, y),
+			[]Statement{
+				NewDeclaration(temporary, IntegerSort, x),
+				NewAssignment(x, y),
+				NewAssignment(y, NewVariable(temporary)),
+			},
+			nil,
+		),
+	), parsed)
+}
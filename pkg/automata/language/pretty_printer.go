@@ -8,9 +8,58 @@ import (
 	"github.com/Brandhoej/gobion/pkg/zones"
 )
 
+// Writes expressions and statements with the fewest parentheses needed to preserve the precedence of the operators.
 type PrettyPrinter struct {
 	writer  io.Writer
 	symbols symbols.Store[any]
+	// The lowest precedence an operand can have without being parenthesised.
+	precedence int
+	nested     bool
+}
+
+// The precedence of the operators from the lowest to the highest.
+const (
+	conditionalPrecedence = iota
+	implicationPrecedence
+	disjunctionPrecedence
+	conjunctionPrecedence
+	bitwiseOrPrecedence
+	bitwiseXorPrecedence
+	bitwiseAndPrecedence
+	comparisonPrecedence
+	shiftPrecedence
+	additivePrecedence
+	multiplicativePrecedence
+	unaryPrecedence
+	postfixPrecedence
+)
+
+// Returns the precedence of the binary operator.
+func (operator BinaryOperator) precedence() int {
+	switch operator {
+	case Implication:
+		return implicationPrecedence
+	case LogicalOr:
+		return disjunctionPrecedence
+	case LogicalAnd:
+		return conjunctionPrecedence
+	case BitwiseOr:
+		return bitwiseOrPrecedence
+	case BitwiseXor:
+		return bitwiseXorPrecedence
+	case BitwiseAnd:
+		return bitwiseAndPrecedence
+	case LeftShift, RightShift:
+		return shiftPrecedence
+	case Addition, Subtraction:
+		return additivePrecedence
+	case Multiplication, Division, Modulus:
+		return multiplicativePrecedence
+	case Minimum, Maximum:
+		// Written as calls which are never parenthesised.
+		return unaryPrecedence
+	}
+	return comparisonPrecedence
 }
 
 func NewPrettyPrinter(
@@ -27,15 +76,37 @@ func (printer PrettyPrinter) WriteString(text string) {
 	io.WriteString(printer.writer, text)
 }
 
+// Returns the printer of an operand which is parenthesised if its precedence is lower than the precedence.
+func (printer PrettyPrinter) operand(precedence int) PrettyPrinter {
+	printer.precedence = precedence
+	printer.nested = true
+	return printer
+}
+
+func (printer PrettyPrinter) open(precedence int) {
+	if precedence < printer.precedence {
+		printer.WriteString("(")
+	}
+}
+
+func (printer PrettyPrinter) close(precedence int) {
+	if precedence < printer.precedence {
+		printer.WriteString(")")
+	}
+}
+
 func (printer PrettyPrinter) Assignment(assignment Assignment) {
-	if variable, ok := assignment.lhs.(Variable); ok {
-		printer.Variable(variable)
+	if _, ok := assignment.Variable(); ok {
+		assignment.lhs.Accept(printer.operand(postfixPrecedence))
 		printer.WriteString("' := ")
-		assignment.rhs.Accept(printer)
+		assignment.rhs.Accept(printer.operand(conditionalPrecedence))
 	}
 }
 
 func (printer PrettyPrinter) ClockConstraint(constraint ClockConstraint) {
+	printer.open(comparisonPrecedence)
+	defer printer.close(comparisonPrecedence)
+
 	lhs, _ := printer.symbols.Item(constraint.lhs)
 	rhs, _ := printer.symbols.Item(constraint.rhs)
 	printer.WriteString(fmt.Sprintf("%s - %s", lhs, rhs))
@@ -79,7 +150,32 @@ func (printer PrettyPrinter) Variable(variable Variable) {
 }
 
 func (printer PrettyPrinter) Binary(binary Binary) {
-	binary.LHS().Accept(printer)
+	switch binary.Operator() {
+	case Minimum, Maximum:
+		if binary.Operator() == Minimum {
+			printer.WriteString("min(")
+		} else {
+			printer.WriteString("max(")
+		}
+		binary.LHS().Accept(printer.operand(conditionalPrecedence))
+		printer.WriteString(", ")
+		binary.RHS().Accept(printer.operand(conditionalPrecedence))
+		printer.WriteString(")")
+		return
+	}
+
+	// Implication is right-associative, comparisons are non-associative and the other operators are left-associative.
+	precedence := binary.Operator().precedence()
+	lhs, rhs := precedence, precedence+1
+	switch precedence {
+	case implicationPrecedence:
+		lhs, rhs = precedence+1, precedence
+	case comparisonPrecedence:
+		lhs = precedence + 1
+	}
+
+	printer.open(precedence)
+	binary.LHS().Accept(printer.operand(lhs))
 	switch binary.Operator() {
 	case Equal:
 		printer.WriteString(" = ")
@@ -90,7 +186,7 @@ func (printer PrettyPrinter) Binary(binary Binary) {
 	case LessThanEqual:
 		printer.WriteString(" ≤ ")
 	case GreaterThan:
-		printer.WriteString(" = ")
+		printer.WriteString(" > ")
 	case GreaterThanEqual:
 		printer.WriteString(" ≥ ")
 	case LogicalAnd:
@@ -103,10 +199,27 @@ func (printer PrettyPrinter) Binary(binary Binary) {
 		printer.WriteString(" - ")
 	case Implication:
 		printer.WriteString(" → ")
+	case Multiplication:
+		printer.WriteString(" * ")
+	case Division:
+		printer.WriteString(" / ")
+	case Modulus:
+		printer.WriteString(" % ")
+	case BitwiseAnd:
+		printer.WriteString(" & ")
+	case BitwiseOr:
+		printer.WriteString(" | ")
+	case BitwiseXor:
+		printer.WriteString(" ^ ")
+	case LeftShift:
+		printer.WriteString(" << ")
+	case RightShift:
+		printer.WriteString(" >> ")
 	default:
 		panic("Unknown binary operator")
 	}
-	binary.RHS().Accept(printer)
+	binary.RHS().Accept(printer.operand(rhs))
+	printer.close(precedence)
 }
 
 func (printer PrettyPrinter) Integer(integer Integer) {
@@ -121,25 +234,42 @@ func (printer PrettyPrinter) Boolean(boolean Boolean) {
 	)
 }
 
+// Arithmetic and bitwise negations bind tighter than all binary operators whereas the logical negation
+// is always followed by parentheses.
 func (printer PrettyPrinter) Unary(unary Unary) {
 	switch unary.Operator() {
 	case LogicalNegation:
 		printer.WriteString("¬")
+	case ArithmeticNegation, BitwiseNegation:
+		if unary.Operator() == ArithmeticNegation {
+			printer.WriteString("-")
+		} else {
+			printer.WriteString("~")
+		}
+		unary.Operand().Accept(printer.operand(unaryPrecedence))
+		return
 	default:
 		panic("Unknown unary operator")
 	}
 	printer.WriteString("(")
-	unary.Operand().Accept(printer)
+	unary.Operand().Accept(printer.operand(conditionalPrecedence))
 	printer.WriteString(")")
 }
 
+// Statements of a block whose expression is true are written without braces unless the block is nested.
 func (printer PrettyPrinter) BlockExpression(block BlockExpression) {
+	if len(block.statements) == 0 {
+		block.expression.Accept(printer)
+		return
+	}
+
 	tautology := false
 	if boolean, ok := block.expression.(Boolean); ok && boolean.value {
 		tautology = true
 	}
+	braces := !tautology || printer.nested
 
-	if !tautology {
+	if braces {
 		printer.WriteString("{")
 	}
 
@@ -147,19 +277,144 @@ func (printer PrettyPrinter) BlockExpression(block BlockExpression) {
 		if idx > 0 {
 			printer.WriteString("; ")
 		}
-		block.statements[idx].Accept(printer)
+		block.statements[idx].Accept(printer.operand(conditionalPrecedence))
 	}
 
 	if !tautology {
-		block.expression.Accept(printer)
+		printer.WriteString("; ")
+		block.expression.Accept(printer.operand(conditionalPrecedence))
+	}
+
+	if braces {
 		printer.WriteString("}")
 	}
 }
 
 func (printer PrettyPrinter) IfThenElse(ite IfThenElse) {
-	ite.condition.Accept(printer)
+	printer.open(conditionalPrecedence)
+	ite.condition.Accept(printer.operand(implicationPrecedence))
 	printer.WriteString(" ? ")
-	ite.consequence.Accept(printer)
+	ite.consequence.Accept(printer.operand(conditionalPrecedence))
 	printer.WriteString(" : ")
-	ite.alternative.Accept(printer)
+	ite.alternative.Accept(printer.operand(conditionalPrecedence))
+	printer.close(conditionalPrecedence)
+}
+
+func (printer PrettyPrinter) Index(index Index) {
+	index.array.Accept(printer.operand(postfixPrecedence))
+	printer.WriteString("[")
+	index.index.Accept(printer.operand(conditionalPrecedence))
+	printer.WriteString("]")
+}
+
+func (printer PrettyPrinter) Member(member Member) {
+	member.record.Accept(printer.operand(postfixPrecedence))
+	printer.WriteString(".")
+	printer.WriteString(member.field)
+}
+
+// Arrays are written as "[a, b]" and records as "{x: a, y: b}".
+func (printer PrettyPrinter) Array(array Array) {
+	printer.WriteString("[")
+	for idx := range array.elements {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		array.elements[idx].Accept(printer.operand(conditionalPrecedence))
+	}
+	printer.WriteString("]")
+}
+
+func (printer PrettyPrinter) Record(record Record) {
+	printer.WriteString("{")
+	for idx := range record.values {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		printer.WriteString(record.sort.fields[idx].name)
+		printer.WriteString(": ")
+		record.values[idx].Accept(printer.operand(conditionalPrecedence))
+	}
+	printer.WriteString("}")
+}
+
+func (printer PrettyPrinter) Call(call Call) {
+	name, _ := printer.symbols.Item(call.function.symbol)
+	printer.WriteString(fmt.Sprintf("%v(", name))
+	for idx := range call.arguments {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		call.arguments[idx].Accept(printer.operand(conditionalPrecedence))
+	}
+	printer.WriteString(")")
+}
+
+// Writes the function as "function int f(int x) {return x + 1}".
+func (printer PrettyPrinter) Function(function Function) {
+	name, _ := printer.symbols.Item(function.symbol)
+	printer.WriteString(fmt.Sprintf("function %s %v(", function.sort, name))
+	for idx, parameter := range function.Parameters() {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		name, _ := printer.symbols.Item(parameter.symbol)
+		printer.WriteString(parameter.sort.Declaration(fmt.Sprint(name)))
+	}
+	printer.WriteString(") ")
+	printer.statements(function.body)
+}
+
+// Writes the statements separated by semicolons in braces.
+func (printer PrettyPrinter) statements(statements []Statement) {
+	printer.WriteString("{")
+	for idx := range statements {
+		if idx > 0 {
+			printer.WriteString("; ")
+		}
+		statements[idx].Accept(printer.operand(conditionalPrecedence))
+	}
+	printer.WriteString("}")
+}
+
+func (printer PrettyPrinter) Declaration(declaration Declaration) {
+	name, _ := printer.symbols.Item(declaration.symbol)
+	printer.WriteString(declaration.sort.Declaration(fmt.Sprint(name)))
+	printer.WriteString(" := ")
+	declaration.initial.Accept(printer.operand(conditionalPrecedence))
+}
+
+// The alternative is omitted if it is empty.
+func (printer PrettyPrinter) If(statement If) {
+	printer.WriteString("if ")
+	statement.condition.Accept(printer.operand(conditionalPrecedence))
+	printer.WriteString(" then ")
+	printer.statements(statement.consequence)
+	if len(statement.alternative) > 0 {
+		printer.WriteString(" else ")
+		printer.statements(statement.alternative)
+	}
+}
+
+func (printer PrettyPrinter) While(loop While) {
+	printer.WriteString("while ")
+	loop.condition.Accept(printer.operand(conditionalPrecedence))
+	printer.WriteString(" do ")
+	printer.statements(loop.body)
+}
+
+// Writes the loop as "for i := 0 to 3 do {…}".
+func (printer PrettyPrinter) For(loop For) {
+	name, _ := printer.symbols.Item(loop.symbol)
+	printer.WriteString(fmt.Sprintf("for %v := %d to %d do ", name, loop.lower, loop.upper))
+	printer.statements(loop.body)
+}
+
+func (printer PrettyPrinter) Sequence(sequence Sequence) {
+	printer.statements(sequence.statements)
+}
+
+func (printer PrettyPrinter) Return(statement Return) {
+	printer.WriteString("return ")
+	statement.expression.Accept(printer.operand(conditionalPrecedence))
 }
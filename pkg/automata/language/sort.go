@@ -1,8 +1,253 @@
 package language
 
-type Sort uint16
+import (
+	"fmt"
+	"strings"
+)
+
+type SortKind uint16
 
 const (
-	BooleanSort = Sort(0)
-	IntegerSort = Sort(1)
+	BooleanKind = SortKind(iota)
+	IntegerKind
+	ArrayKind
+	RecordKind
+)
+
+// The sort of a variable. Integer sorts are unbounded unless they have the inclusive range "int[lower,upper]".
+// Arrays have a fixed number of elements which are indexed from zero and records have named fields.
+type Sort struct {
+	kind         SortKind
+	bounded      bool
+	lower, upper int
+	element      *Sort
+	length       int
+	fields       []Field
+}
+
+var (
+	BooleanSort = Sort{kind: BooleanKind}
+	IntegerSort = Sort{kind: IntegerKind}
 )
+
+func NewBoundedIntegerSort(lower, upper int) Sort {
+	if lower > upper {
+		panic("The lower bound of the sort is greater than its upper bound")
+	}
+	return Sort{
+		kind:    IntegerKind,
+		bounded: true,
+		lower:   lower,
+		upper:   upper,
+	}
+}
+
+// Returns the sort of arrays with the number of elements of the sort.
+func NewArraySort(element Sort, length int) Sort {
+	if length < 1 {
+		panic("Arrays must have at least one element")
+	}
+	return Sort{
+		kind:    ArrayKind,
+		element: &element,
+		length:  length,
+	}
+}
+
+// Returns the sort of records with the fields in the order they are given.
+func NewRecordSort(fields ...Field) Sort {
+	if len(fields) == 0 {
+		panic("Records must have at least one field")
+	}
+	names := map[string]bool{}
+	for _, field := range fields {
+		if names[field.name] {
+			panic(fmt.Sprintf("The field %s is declared more than once", field.name))
+		}
+		names[field.name] = true
+	}
+	return Sort{
+		kind:   RecordKind,
+		fields: fields,
+	}
+}
+
+func (sort Sort) Kind() SortKind {
+	return sort.kind
+}
+
+// Returns the inclusive range of the sort if it is a bounded integer sort.
+func (sort Sort) Bounds() (lower, upper int, bounded bool) {
+	return sort.lower, sort.upper, sort.bounded
+}
+
+// Returns true if the integer is within the range of the sort. Unbounded sorts contain all integers.
+func (sort Sort) Contains(value int) bool {
+	return !sort.bounded || (sort.lower <= value && value <= sort.upper)
+}
+
+// Returns the sort and number of the elements if the sort is an array sort.
+func (sort Sort) Element() (element Sort, length int) {
+	if sort.kind != ArrayKind {
+		panic("The sort is not an array sort")
+	}
+	return *sort.element, sort.length
+}
+
+func (sort Sort) Fields() []Field {
+	return sort.fields
+}
+
+// Returns the position and field with the name if the sort is a record sort with the field.
+func (sort Sort) Field(name string) (index int, field Field, exists bool) {
+	for index, field = range sort.fields {
+		if field.name == name {
+			return index, field, true
+		}
+	}
+	return -1, Field{}, false
+}
+
+// Returns true if the sorts have the same structure.
+func (sort Sort) Equals(other Sort) bool {
+	return sort.String() == other.String()
+}
+
+// Returns the sort as in C where the lengths of arrays follow the sort of their elements, e.g. "int[0,3][2][4]"
+// is an array of two arrays of four bounded integers.
+func (sort Sort) String() string {
+	switch sort.kind {
+	case BooleanKind:
+		return "bool"
+	case ArrayKind:
+		base, lengths := sort, ""
+		for base.kind == ArrayKind {
+			lengths += fmt.Sprintf("[%d]", base.length)
+			base = *base.element
+		}
+		return base.String() + lengths
+	case RecordKind:
+		var builder strings.Builder
+		builder.WriteString("struct {")
+		for _, field := range sort.fields {
+			builder.WriteString(" ")
+			builder.WriteString(field.sort.Declaration(field.name))
+			builder.WriteString(";")
+		}
+		builder.WriteString(" }")
+		return builder.String()
+	}
+	if sort.bounded {
+		return fmt.Sprintf("int[%d,%d]", sort.lower, sort.upper)
+	}
+	return "int"
+}
+
+// Returns the declaration of a variable of the sort as in C where the lengths of arrays follow the name,
+// e.g. "int[0,3] a[2][4]".
+func (sort Sort) Declaration(name string) string {
+	base, lengths := sort, ""
+	for base.kind == ArrayKind {
+		lengths += fmt.Sprintf("[%d]", base.length)
+		base = *base.element
+	}
+	return fmt.Sprintf("%s %s%s", base, name, lengths)
+}
+
+// A named field of a record sort.
+type Field struct {
+	name string
+	sort Sort
+}
+
+func NewField(name string, sort Sort) Field {
+	return Field{
+		name: name,
+		sort: sort,
+	}
+}
+
+func (field Field) Name() string {
+	return field.name
+}
+
+func (field Field) Sort() Sort {
+	return field.sort
+}
+
+// Returns the initial value of variables of the sort. Integers are zero unless their range does
+// not contain it in which case they are the lower bound.
+func (sort Sort) Zero() Expression {
+	switch sort.kind {
+	case BooleanKind:
+		return NewFalse()
+	case ArrayKind:
+		elements := make([]Expression, sort.length)
+		for idx := range elements {
+			elements[idx] = sort.element.Zero()
+		}
+		return NewArray(elements...)
+	case RecordKind:
+		values := make([]Expression, len(sort.fields))
+		for idx := range values {
+			values[idx] = sort.fields[idx].sort.Zero()
+		}
+		return NewRecord(sort, values...)
+	}
+	if !sort.Contains(0) {
+		return NewInteger(sort.lower)
+	}
+	return NewInteger(0)
+}
+
+// Returns the sort of the expression where the sorts of the variables are declared in the variables.
+// Arithmetic is of the integer sort and logic of the boolean sort. The sort does not exist if the
+// expression refers to an undeclared variable, indexes a non-array or accesses a missing field.
+func SortOf(variables Variables, expression Expression) (sort Sort, exists bool) {
+	switch cast := any(expression).(type) {
+	case Variable:
+		if variables == nil {
+			return Sort{}, false
+		}
+		return variables.Lookup(cast.symbol)
+	case Integer:
+		return IntegerSort, true
+	case Boolean, ClockConstraint:
+		return BooleanSort, true
+	case Binary:
+		switch cast.operator {
+		case Equal, NotEqual, LessThan, LessThanEqual, GreaterThan, GreaterThanEqual,
+			LogicalAnd, LogicalOr, Implication:
+			return BooleanSort, true
+		}
+		return IntegerSort, true
+	case Unary:
+		if cast.operator == LogicalNegation {
+			return BooleanSort, true
+		}
+		return IntegerSort, true
+	case IfThenElse:
+		return SortOf(variables, cast.consequence)
+	case BlockExpression:
+		return SortOf(variables, cast.expression)
+	case Call:
+		return cast.function.sort, true
+	case Index:
+		if array, exists := SortOf(variables, cast.array); exists && array.kind == ArrayKind {
+			return *array.element, true
+		}
+	case Member:
+		if record, exists := SortOf(variables, cast.record); exists && record.kind == RecordKind {
+			if _, field, exists := record.Field(cast.field); exists {
+				return field.sort, true
+			}
+		}
+	case Array:
+		if element, exists := SortOf(variables, cast.elements[0]); exists {
+			return NewArraySort(element, len(cast.elements)), true
+		}
+	case Record:
+		return cast.sort, true
+	}
+	return Sort{}, false
+}
@@ -1,7 +1,18 @@
 package language
 
+import "github.com/Brandhoej/gobion/pkg/symbols"
+
 type StatementVisitor interface {
 	Assignment(assignment Assignment)
+	ClockAssignment(assignment ClockAssignment)
+	ClockShift(shift ClockShift)
+	ClockReset(reset ClockReset)
+	Declaration(declaration Declaration)
+	If(statement If)
+	While(loop While)
+	For(loop For)
+	Sequence(sequence Sequence)
+	Return(statement Return)
 }
 
 type Statement interface {
@@ -20,6 +31,250 @@ func NewAssignment(variable Expression, valuation Expression) Assignment {
 	}
 }
 
+func (assignment Assignment) LHS() Expression {
+	return assignment.lhs
+}
+
+func (assignment Assignment) RHS() Expression {
+	return assignment.rhs
+}
+
+// Returns the variable whose element or field is assigned if the assigned expression is one.
+func (assignment Assignment) Variable() (Variable, bool) {
+	lhs := assignment.lhs
+	for {
+		switch cast := lhs.(type) {
+		case Variable:
+			return cast, true
+		case Index:
+			lhs = cast.array
+		case Member:
+			lhs = cast.record
+		default:
+			return Variable{}, false
+		}
+	}
+}
+
+// Returns the assignment of the whole variable which is equivalent to the assignment of its element or field.
+// The element "a[i] := e" is assigned as "a := [i = 0 ? e : a[0], ..., i = n-1 ? e : a[n-1]]" unless the index
+// is an integer and the field "r.f := e" is assigned as the record of the fields of r where f is e.
+func (assignment Assignment) Expand(variables Variables) Assignment {
+	lhs, rhs := assignment.lhs, assignment.rhs
+	for {
+		switch cast := lhs.(type) {
+		case Index:
+			sort, exists := SortOf(variables, cast.array)
+			if !exists || sort.kind != ArrayKind {
+				panic("Only arrays can be indexed")
+			}
+			elements := make([]Expression, sort.length)
+			for idx := range elements {
+				element := Expression(NewIndex(cast.array, NewInteger(idx)))
+				if integer, ok := cast.index.(Integer); ok {
+					if integer.value == idx {
+						element = rhs
+					}
+				} else {
+					element = NewIfThenElse(NewBinary(cast.index, Equal, NewInteger(idx)), rhs, element)
+				}
+				elements[idx] = element
+			}
+			lhs, rhs = cast.array, NewArray(elements...)
+		case Member:
+			sort, exists := SortOf(variables, cast.record)
+			if !exists || sort.kind != RecordKind {
+				panic("Only records have fields")
+			}
+			if _, _, exists := sort.Field(cast.field); !exists {
+				panic("The record does not have the field")
+			}
+			values := make([]Expression, len(sort.fields))
+			for idx, field := range sort.fields {
+				values[idx] = NewMember(cast.record, field.name)
+				if field.name == cast.field {
+					values[idx] = rhs
+				}
+			}
+			lhs, rhs = cast.record, NewRecord(sort, values...)
+		default:
+			return NewAssignment(lhs, rhs)
+		}
+	}
+}
+
 func (assignment Assignment) Accept(visitor StatementVisitor) {
 	visitor.Assignment(assignment)
 }
+
+// The declaration of a local variable of a sort with an initial value.
+type Declaration struct {
+	symbol  symbols.Symbol
+	sort    Sort
+	initial Expression
+}
+
+// Returns the declaration of the variable whose initial value is the zero of its sort if it is nil.
+func NewDeclaration(symbol symbols.Symbol, sort Sort, initial Expression) Declaration {
+	if initial == nil {
+		initial = sort.Zero()
+	}
+	return Declaration{
+		symbol:  symbol,
+		sort:    sort,
+		initial: initial,
+	}
+}
+
+func (declaration Declaration) Symbol() symbols.Symbol {
+	return declaration.symbol
+}
+
+func (declaration Declaration) Sort() Sort {
+	return declaration.sort
+}
+
+func (declaration Declaration) Initial() Expression {
+	return declaration.initial
+}
+
+func (declaration Declaration) Accept(visitor StatementVisitor) {
+	visitor.Declaration(declaration)
+}
+
+// Executes the consequence if the condition is true and otherwise the alternative.
+type If struct {
+	condition   Expression
+	consequence []Statement
+	alternative []Statement
+}
+
+func NewIf(condition Expression, consequence, alternative []Statement) If {
+	return If{
+		condition:   condition,
+		consequence: consequence,
+		alternative: alternative,
+	}
+}
+
+func (statement If) Condition() Expression {
+	return statement.condition
+}
+
+func (statement If) Consequence() []Statement {
+	return statement.consequence
+}
+
+func (statement If) Alternative() []Statement {
+	return statement.alternative
+}
+
+func (statement If) Accept(visitor StatementVisitor) {
+	visitor.If(statement)
+}
+
+// Executes the body as long as the condition is true.
+type While struct {
+	condition Expression
+	body      []Statement
+}
+
+func NewWhile(condition Expression, body ...Statement) While {
+	return While{
+		condition: condition,
+		body:      body,
+	}
+}
+
+func (loop While) Condition() Expression {
+	return loop.condition
+}
+
+func (loop While) Body() []Statement {
+	return loop.body
+}
+
+func (loop While) Accept(visitor StatementVisitor) {
+	visitor.While(loop)
+}
+
+// Executes the body for each integer from the lower to the upper bound, both inclusive,
+// assigned to the local variable of the loop. The bounds are constant such that the loop terminates.
+type For struct {
+	symbol       symbols.Symbol
+	lower, upper int
+	body         []Statement
+}
+
+func NewFor(symbol symbols.Symbol, lower, upper int, body ...Statement) For {
+	return For{
+		symbol: symbol,
+		lower:  lower,
+		upper:  upper,
+		body:   body,
+	}
+}
+
+func (loop For) Symbol() symbols.Symbol {
+	return loop.symbol
+}
+
+func (loop For) Lower() int {
+	return loop.lower
+}
+
+func (loop For) Upper() int {
+	return loop.upper
+}
+
+func (loop For) Body() []Statement {
+	return loop.body
+}
+
+func (loop For) Accept(visitor StatementVisitor) {
+	visitor.For(loop)
+}
+
+// Executes the statements from first to last in a scope of their local variables.
+type Sequence struct {
+	statements []Statement
+}
+
+func NewSequence(statements ...Statement) Sequence {
+	return Sequence{
+		statements: statements,
+	}
+}
+
+func (sequence Sequence) Statements() []Statement {
+	return sequence.statements
+}
+
+func (sequence Sequence) Accept(visitor StatementVisitor) {
+	visitor.Sequence(sequence)
+}
+
+// Returns the value of the expression from the function.
+type Return struct {
+	expression Expression
+}
+
+func NewReturn(expression Expression) Return {
+	return Return{
+		expression: expression,
+	}
+}
+
+func (statement Return) Expression() Expression {
+	return statement.expression
+}
+
+func (statement Return) Accept(visitor StatementVisitor) {
+	visitor.Return(statement)
+}
+
+// Returns the statements of all blocks of the expression in the order they are executed.
+// The statements in the bodies of control statements are part of the control statements.
+func Statements(expression Expression) []Statement {
+	return collectStatements(expression, nil)
+}
@@ -0,0 +1,202 @@
+package language
+
+import "github.com/Brandhoej/gobion/pkg/symbols"
+
+// Rewrites expressions and statements by replacing variables by expressions and renaming variables and clocks.
+type Substitution struct {
+	expressions map[symbols.Symbol]Expression
+	renamings   map[symbols.Symbol]symbols.Symbol
+	expression  Expression
+	statement   Statement
+}
+
+func NewSubstitution() *Substitution {
+	return &Substitution{
+		expressions: map[symbols.Symbol]Expression{},
+		renamings:   map[symbols.Symbol]symbols.Symbol{},
+	}
+}
+
+// Replaces all occurrences of the variable by the expression.
+func (substitution *Substitution) Replace(symbol symbols.Symbol, expression Expression) {
+	substitution.expressions[symbol] = expression
+}
+
+// Renames all occurrences of the variable or clock.
+func (substitution *Substitution) Rename(symbol, renamed symbols.Symbol) {
+	substitution.renamings[symbol] = renamed
+}
+
+// Returns the renamed symbol or the symbol itself if it is not renamed.
+func (substitution *Substitution) Symbol(symbol symbols.Symbol) symbols.Symbol {
+	if renamed, exists := substitution.renamings[symbol]; exists {
+		return renamed
+	}
+	return symbol
+}
+
+// Returns the expression where the replacements and renamings have been applied.
+func (substitution *Substitution) Substitute(expression Expression) Expression {
+	expression.Accept(substitution)
+	return substitution.expression
+}
+
+func (substitution *Substitution) substituteStatement(statement Statement) Statement {
+	statement.Accept(substitution)
+	return substitution.statement
+}
+
+func (substitution *Substitution) Variable(variable Variable) {
+	if expression, exists := substitution.expressions[variable.symbol]; exists {
+		substitution.expression = expression
+		return
+	}
+	substitution.expression = NewVariable(substitution.Symbol(variable.symbol))
+}
+
+func (substitution *Substitution) Binary(binary Binary) {
+	lhs := substitution.Substitute(binary.lhs)
+	rhs := substitution.Substitute(binary.rhs)
+	substitution.expression = NewBinary(lhs, binary.operator, rhs)
+}
+
+func (substitution *Substitution) Integer(integer Integer) {
+	substitution.expression = integer
+}
+
+func (substitution *Substitution) Boolean(boolean Boolean) {
+	substitution.expression = boolean
+}
+
+func (substitution *Substitution) Unary(unary Unary) {
+	substitution.expression = NewUnary(unary.operator, substitution.Substitute(unary.operand))
+}
+
+func (substitution *Substitution) IfThenElse(ite IfThenElse) {
+	condition := substitution.Substitute(ite.condition)
+	consequence := substitution.Substitute(ite.consequence)
+	alternative := substitution.Substitute(ite.alternative)
+	substitution.expression = NewIfThenElse(condition, consequence, alternative)
+}
+
+func (substitution *Substitution) BlockExpression(block BlockExpression) {
+	statements := substitution.substituteStatements(block.statements)
+	substitution.expression = NewBlockExpression(substitution.Substitute(block.expression), statements...)
+}
+
+func (substitution *Substitution) ClockConstraint(constraint ClockConstraint) {
+	substitution.expression = NewClockConstraint(
+		substitution.Symbol(constraint.lhs), substitution.Symbol(constraint.rhs), constraint.relation,
+	)
+}
+
+func (substitution *Substitution) Index(index Index) {
+	array := substitution.Substitute(index.array)
+	substitution.expression = NewIndex(array, substitution.Substitute(index.index))
+}
+
+func (substitution *Substitution) Member(member Member) {
+	substitution.expression = NewMember(substitution.Substitute(member.record), member.field)
+}
+
+func (substitution *Substitution) Array(array Array) {
+	elements := make([]Expression, len(array.elements))
+	for idx := range array.elements {
+		elements[idx] = substitution.Substitute(array.elements[idx])
+	}
+	substitution.expression = NewArray(elements...)
+}
+
+func (substitution *Substitution) Record(record Record) {
+	values := make([]Expression, len(record.values))
+	for idx := range record.values {
+		values[idx] = substitution.Substitute(record.values[idx])
+	}
+	substitution.expression = NewRecord(record.sort, values...)
+}
+
+func (substitution *Substitution) Assignment(assignment Assignment) {
+	lhs := substitution.Substitute(assignment.lhs)
+	rhs := substitution.Substitute(assignment.rhs)
+	substitution.statement = NewAssignment(lhs, rhs)
+}
+
+func (substitution *Substitution) ClockAssignment(assignment ClockAssignment) {
+	substitution.statement = NewClockAssignment(
+		substitution.Symbol(assignment.lhs), substitution.Symbol(assignment.rhs),
+	)
+}
+
+func (substitution *Substitution) ClockShift(shift ClockShift) {
+	substitution.statement = NewClockShift(substitution.Symbol(shift.clock), shift.limit)
+}
+
+func (substitution *Substitution) ClockReset(reset ClockReset) {
+	substitution.statement = NewClockReset(substitution.Symbol(reset.clock), reset.limit)
+}
+
+// The arguments are substituted and so is the body of the function except its parameters and local variables.
+func (substitution *Substitution) Call(call Call) {
+	arguments := make([]Expression, len(call.arguments))
+	for idx := range call.arguments {
+		arguments[idx] = substitution.Substitute(call.arguments[idx])
+	}
+	body := NewSubstitution()
+	for symbol, expression := range substitution.expressions {
+		if _, local := call.function.variables.Lookup(symbol); !local {
+			body.Replace(symbol, expression)
+		}
+	}
+	for symbol, renamed := range substitution.renamings {
+		if _, local := call.function.variables.Lookup(symbol); !local {
+			body.Rename(symbol, renamed)
+		}
+	}
+	function := call.function
+	substitution.expression = NewCall(
+		NewFunction(function.symbol, function.sort, function.Parameters(), body.substituteStatements(function.body)...),
+		arguments...,
+	)
+}
+
+func (substitution *Substitution) substituteStatements(statements []Statement) []Statement {
+	substituted := make([]Statement, len(statements))
+	for idx := range statements {
+		substituted[idx] = substitution.substituteStatement(statements[idx])
+	}
+	return substituted
+}
+
+func (substitution *Substitution) Declaration(declaration Declaration) {
+	substitution.statement = NewDeclaration(
+		substitution.Symbol(declaration.symbol), declaration.sort, substitution.Substitute(declaration.initial),
+	)
+}
+
+func (substitution *Substitution) If(statement If) {
+	substitution.statement = NewIf(
+		substitution.Substitute(statement.condition),
+		substitution.substituteStatements(statement.consequence),
+		substitution.substituteStatements(statement.alternative),
+	)
+}
+
+func (substitution *Substitution) While(loop While) {
+	substitution.statement = NewWhile(
+		substitution.Substitute(loop.condition), substitution.substituteStatements(loop.body)...,
+	)
+}
+
+func (substitution *Substitution) For(loop For) {
+	substitution.statement = NewFor(
+		substitution.Symbol(loop.symbol), loop.lower, loop.upper, substitution.substituteStatements(loop.body)...,
+	)
+}
+
+func (substitution *Substitution) Sequence(sequence Sequence) {
+	substitution.statement = NewSequence(substitution.substituteStatements(sequence.statements)...)
+}
+
+func (substitution *Substitution) Return(statement Return) {
+	substitution.statement = NewReturn(substitution.Substitute(statement.expression))
+}
@@ -1,6 +1,8 @@
 package language
 
 import (
+	"fmt"
+
 	"github.com/Brandhoej/gobion/internal/z3"
 	"github.com/Brandhoej/gobion/pkg/symbols"
 )
@@ -11,6 +13,31 @@ type SymbolicInterpreter struct {
 	valuations Valuations
 	pc         *z3.AST
 	z3solver   *z3.Solver
+	unrollings int
+	err        error
+	// The condition under which the inlined function has returned and the value it returns.
+	// The condition is nil outside of functions. The number of nested calls being inlined.
+	returned Expression
+	result   Expression
+	inlined  int
+	// The violations are recorded if the ranges are checked.
+	ranges     bool
+	violations []RangeViolation
+}
+
+// A variable which an assignment can assign a value outside of the range of its bounded integer sort.
+// The sort is the sort of the assigned element or field if the assignment assigns one.
+type RangeViolation struct {
+	variable symbols.Symbol
+	sort     Sort
+}
+
+func (violation RangeViolation) Variable() symbols.Symbol {
+	return violation.variable
+}
+
+func (violation RangeViolation) Sort() Sort {
+	return violation.sort
 }
 
 func NewSymbolicInterpreter(
@@ -24,9 +51,32 @@ func NewSymbolicInterpreter(
 		valuations: valuations,
 		pc:         context.NewTrue(),
 		z3solver:   nil,
+		unrollings: DefaultUnrollings,
 	}
 }
 
+// Sets the maximum number of iterations of while loops which are unrolled.
+func (interpreter *SymbolicInterpreter) SetUnrollings(unrollings int) {
+	interpreter.unrollings = unrollings
+}
+
+// Sets whether the assignments are checked for values outside of the ranges of bounded integer sorts.
+func (interpreter *SymbolicInterpreter) SetRangeChecks(ranges bool) {
+	interpreter.ranges = ranges
+}
+
+// Returns the violations of the ranges by the assignments on any path in the order they are found.
+// A variable is only violated once even if it is assigned by multiple assignments or iterations.
+func (interpreter *SymbolicInterpreter) RangeViolations() []RangeViolation {
+	return interpreter.violations
+}
+
+// Returns the first error of the interpretation if any. It is an error if the condition of a while loop
+// can still be true after the maximum number of unrollings where the remaining iterations are omitted.
+func (interpreter *SymbolicInterpreter) Err() error {
+	return interpreter.err
+}
+
 func (interpreter *SymbolicInterpreter) solver() *z3.Solver {
 	// Upon a change in valuations the backing z3 solver will be discarded.
 	// However, if the solver exists then we can gurantee that it is valid.
@@ -39,11 +89,21 @@ func (interpreter *SymbolicInterpreter) solver() *z3.Solver {
 	// An arbritary capacity is chosen. It will grow to fit the size of the valuation set.
 	assertions := make([]*z3.AST, 1, 16)
 	assertions[0] = interpreter.pc
+	// The variables without values can only have values within the ranges of their sorts.
+	translator := NewZ3Translator(interpreter.context, interpreter.variables)
+	interpreter.variables.All(func(symbol symbols.Symbol, _ Sort) bool {
+		if _, valued := interpreter.valuations.Value(symbol); !valued {
+			assertions = append(assertions, translator.Range(symbol))
+		}
+		return true
+	})
+	// The values are translated rather than interpreted as interpreting conditions, such as those
+	// of assigned array elements, would constrain the path and discard the solver being built.
 	interpreter.valuations.All(func(symbol symbols.Symbol, value Expression) bool {
+		sort, _ := interpreter.variables.Lookup(symbol)
 		constant := interpreter.symbolVariable(symbol)
-		valuation := interpreter.Expression(value)
-		equality := z3.Eq(constant, valuation)
-		assertions = append(assertions, equality)
+		valuation := translator.Translate(value)
+		assertions = append(assertions, equality(constant, valuation, sort))
 		return true
 	})
 
@@ -76,32 +136,338 @@ func (interpreter *SymbolicInterpreter) canBeTrue(expression *z3.AST) bool {
 }
 
 func (interpreter *SymbolicInterpreter) sort(sort Sort) *z3.Sort {
-	if sort == BooleanSort {
-		return interpreter.context.BooleanSort()
-	}
-	if sort == IntegerSort {
-		return interpreter.context.IntegerSort()
-	}
-	panic("Unknown sort")
+	return translateSort(interpreter.context, sort)
 }
 
+// The maximum number of iterations of while loops which are unrolled unless it is set otherwise.
+const DefaultUnrollings = 32
+
 func (interpreter *SymbolicInterpreter) Statement(statement Statement) {
 	switch cast := any(statement).(type) {
 	case Assignment:
 		interpreter.Assignment(cast)
+	case ClockAssignment, ClockShift, ClockReset:
+		// Clocks are interpreted by zones.
+	case Declaration:
+		interpreter.variables.Declare(cast.symbol, cast.sort)
+		interpreter.valuations.Assign(cast.symbol, interpreter.substitute(cast.initial))
+		interpreter.z3solver = nil
+	case If:
+		interpreter.If(cast)
+	case While:
+		interpreter.While(cast)
+	case For:
+		interpreter.For(cast)
+	case Sequence:
+		interpreter.block(cast.statements)
+	case Return:
+		if interpreter.returned == nil {
+			panic("Cannot return outside of a function")
+		}
+		interpreter.result = interpreter.substitute(cast.expression)
+		interpreter.returned = NewTrue()
 	default:
 		panic("Unknown statement type")
 	}
 }
 
+// Returns the expression where the current values of the variables are substituted into it
+// and the calls are inlined such that the values do not contain calls.
+func (interpreter *SymbolicInterpreter) substitute(expression Expression) Expression {
+	substitution := NewSubstitution()
+	interpreter.valuations.All(func(symbol symbols.Symbol, value Expression) bool {
+		substitution.Replace(symbol, value)
+		return true
+	})
+	return interpreter.Inline(substitution.Substitute(expression))
+}
+
+// Returns the expression where the calls are replaced by the values they return.
+func (interpreter *SymbolicInterpreter) Inline(expression Expression) Expression {
+	switch cast := any(expression).(type) {
+	case Binary:
+		return NewBinary(interpreter.Inline(cast.lhs), cast.operator, interpreter.Inline(cast.rhs))
+	case Unary:
+		return NewUnary(cast.operator, interpreter.Inline(cast.operand))
+	case IfThenElse:
+		return NewIfThenElse(
+			interpreter.Inline(cast.condition),
+			interpreter.Inline(cast.consequence),
+			interpreter.Inline(cast.alternative),
+		)
+	case Index:
+		return NewIndex(interpreter.Inline(cast.array), interpreter.Inline(cast.index))
+	case Member:
+		return NewMember(interpreter.Inline(cast.record), cast.field)
+	case Array:
+		return NewArray(interpreter.inlineAll(cast.elements)...)
+	case Record:
+		return NewRecord(cast.sort, interpreter.inlineAll(cast.values)...)
+	case Call:
+		return interpreter.inline(cast)
+	}
+	return expression
+}
+
+func (interpreter *SymbolicInterpreter) inlineAll(expressions []Expression) []Expression {
+	inlined := make([]Expression, len(expressions))
+	for idx := range expressions {
+		inlined[idx] = interpreter.Inline(expressions[idx])
+	}
+	return inlined
+}
+
+// Executes the body of the function with its parameters assigned to the arguments and returns the value it
+// returns on each path as an if-then-else expression. Calls which read no variables are executed concretely.
+// Like while loops, the nesting of calls is bounded by the number of unrollings such that recursion terminates.
+func (interpreter *SymbolicInterpreter) inline(call Call) Expression {
+	arguments := interpreter.inlineAll(call.arguments)
+	call = NewCall(call.function, arguments...)
+	if isClosed(call) {
+		return NewConcreteInterpreter(interpreter.variables, NewValuationsMap()).Interpret(call)
+	}
+	function := call.function
+	if interpreter.inlined == interpreter.unrollings {
+		if interpreter.err == nil {
+			interpreter.err = fmt.Errorf("the call is not bounded by %d inlinings", interpreter.unrollings)
+		}
+		return function.sort.Zero()
+	}
+
+	returned, result := interpreter.returned, interpreter.result
+	interpreter.returned, interpreter.result = NewFalse(), function.sort.Zero()
+	interpreter.inlined++
+	locals := NewVariablesMap()
+	for _, parameter := range function.Parameters() {
+		locals.Declare(parameter.symbol, parameter.sort)
+	}
+	interpreter.scoped(locals, func() {
+		for idx, parameter := range function.parameters {
+			interpreter.valuations.Assign(parameter, arguments[idx])
+		}
+		interpreter.z3solver = nil
+		interpreter.block(function.body)
+	})
+	interpreter.inlined--
+
+	translation := NewZ3Translator(interpreter.context, interpreter.variables).Translate(interpreter.returned)
+	if interpreter.canBeTrue(z3.Not(translation)) && interpreter.err == nil {
+		interpreter.err = fmt.Errorf("the function does not return a value on all paths")
+	}
+	value := interpreter.result
+	interpreter.returned, interpreter.result = returned, result
+	return value
+}
+
+// Assigns the value to the variable where the current values are substituted into the value
+// such that a variable which refers to itself, as in "i' := i + 1", is not defined by itself.
+// Elements and fields are assigned by assigning the whole variable.
 func (interpreter *SymbolicInterpreter) Assignment(assignment Assignment) {
+	if interpreter.ranges {
+		interpreter.checkRange(assignment)
+	}
+	assignment = assignment.Expand(interpreter.variables)
 	if variable, ok := assignment.lhs.(Variable); ok {
-		symbol := variable.Symbol()
-		interpreter.valuations.Assign(symbol, assignment.rhs)
+		interpreter.valuations.Assign(variable.Symbol(), interpreter.substitute(assignment.rhs))
 		interpreter.z3solver = nil
 	}
 }
 
+// Records a violation if the value of the assignment can be outside of the range of the assigned
+// variable, element, or field on the current path. The path includes the conditions of the branches.
+func (interpreter *SymbolicInterpreter) checkRange(assignment Assignment) {
+	variable, ok := assignment.Variable()
+	if !ok {
+		return
+	}
+	for _, violation := range interpreter.violations {
+		if violation.variable == variable.symbol {
+			return
+		}
+	}
+	sort, ok := SortOf(interpreter.variables, assignment.lhs)
+	if !ok {
+		return
+	}
+	lower, upper, bounded := sort.Bounds()
+	if !bounded {
+		return
+	}
+
+	value := NewZ3Translator(interpreter.context, interpreter.variables).Translate(interpreter.substitute(assignment.rhs))
+	outside := z3.Or(
+		z3.LT(value, interpreter.context.NewInt(lower, interpreter.context.IntegerSort())),
+		z3.GT(value, interpreter.context.NewInt(upper, interpreter.context.IntegerSort())),
+	)
+	if interpreter.canBeTrue(outside) {
+		interpreter.violations = append(interpreter.violations, RangeViolation{
+			variable: variable.symbol,
+			sort:     sort,
+		})
+	}
+}
+
+// Executes the branch of the condition if it is determined. Otherwise, both branches are executed
+// and the variables they assign are assigned the value of either branch by the condition.
+func (interpreter *SymbolicInterpreter) If(statement If) {
+	accesses := NewAccesses()
+	statement.Accept(accesses)
+	interpreter.branch(statement.condition, accesses, func() {
+		interpreter.block(statement.consequence)
+	}, func() {
+		interpreter.block(statement.alternative)
+	})
+}
+
+// Unrolls the loop until its condition is false. The iterations with an undetermined condition
+// are branches such that the values of the variables after the loop depend on the iterations.
+func (interpreter *SymbolicInterpreter) While(loop While) {
+	accesses := NewAccesses()
+	loop.Accept(accesses)
+	var unroll func(bound int)
+	unroll = func(bound int) {
+		condition := loop.condition
+		if interpreter.returned != nil {
+			// The loop is exited once the function has returned.
+			condition = NewBinary(NewUnary(LogicalNegation, interpreter.returned), LogicalAnd, condition)
+		}
+		interpreter.branch(condition, accesses, func() {
+			if bound == 0 {
+				if interpreter.err == nil {
+					interpreter.err = fmt.Errorf("the while loop is not bounded by %d unrollings", interpreter.unrollings)
+				}
+				return
+			}
+			interpreter.block(loop.body)
+			unroll(bound - 1)
+		}, func() {})
+	}
+	unroll(interpreter.unrollings)
+}
+
+func (interpreter *SymbolicInterpreter) For(loop For) {
+	locals := NewVariablesMap()
+	locals.Declare(loop.symbol, IntegerSort)
+	interpreter.scoped(locals, func() {
+		for counter := loop.lower; counter <= loop.upper; counter++ {
+			interpreter.valuations.Assign(loop.symbol, NewInteger(counter))
+			interpreter.z3solver = nil
+			interpreter.block(loop.body)
+		}
+	})
+}
+
+// Executes the consequence if the condition is true and the alternative if it is false. If it can be both
+// then both are executed on the current values and the written variables are assigned if-then-else values.
+// The condition is translated rather than interpreted such that its equalities do not constrain the path.
+func (interpreter *SymbolicInterpreter) branch(
+	condition Expression, accesses Accesses, consequence, alternative func(),
+) {
+	condition = interpreter.substitute(condition)
+	translation := NewZ3Translator(interpreter.context, interpreter.variables).Translate(condition)
+	if interpreter.isTrue(translation) {
+		consequence()
+		return
+	}
+	if interpreter.isFalse(translation) {
+		alternative()
+		return
+	}
+
+	pc, valuations := interpreter.pc, interpreter.valuations
+	returned, result := interpreter.returned, interpreter.result
+	execute := func(constraint *z3.AST, branch func()) (Valuations, Expression, Expression) {
+		interpreter.pc, interpreter.valuations = pc, valuations.Copy()
+		interpreter.returned, interpreter.result = returned, result
+		interpreter.constrain(constraint)
+		branch()
+		return interpreter.valuations, interpreter.returned, interpreter.result
+	}
+	consequences, consequenceReturned, consequenceResult := execute(translation, consequence)
+	alternatives, alternativeReturned, alternativeResult := execute(z3.Not(translation), alternative)
+	interpreter.pc, interpreter.valuations, interpreter.z3solver = pc, valuations, nil
+	interpreter.returned, interpreter.result = returned, result
+	if returned != nil && !(isFalseLiteral(consequenceReturned) && isFalseLiteral(alternativeReturned)) {
+		interpreter.returned = NewIfThenElse(condition, consequenceReturned, alternativeReturned)
+		interpreter.result = NewIfThenElse(condition, consequenceResult, alternativeResult)
+	}
+
+	value := func(valuations Valuations, symbol symbols.Symbol) Expression {
+		if value, exists := valuations.Value(symbol); exists {
+			return value
+		}
+		return NewVariable(symbol)
+	}
+	accesses.All(func(symbol symbols.Symbol) bool {
+		if !accesses.Writes(symbol) {
+			return true
+		}
+		if _, declared := interpreter.variables.Lookup(symbol); declared {
+			valuations.Assign(symbol, NewIfThenElse(
+				condition, value(consequences, symbol), value(alternatives, symbol),
+			))
+		}
+		return true
+	})
+}
+
+// Executes the statements in a scope of their local variables.
+func (interpreter *SymbolicInterpreter) block(statements []Statement) {
+	interpreter.scoped(NewVariablesMap(), func() {
+		interpreter.sequence(statements)
+	})
+}
+
+// Executes the statements until the inlined function has returned. If it has returned on some paths
+// then the remaining statements are executed on the paths where it has not returned.
+func (interpreter *SymbolicInterpreter) sequence(statements []Statement) {
+	for idx := range statements {
+		if interpreter.returned != nil && !isFalseLiteral(interpreter.returned) {
+			if isTrueLiteral(interpreter.returned) {
+				return
+			}
+			remaining := statements[idx:]
+			accesses := NewAccesses()
+			for _, statement := range remaining {
+				statement.Accept(accesses)
+			}
+			interpreter.branch(NewUnary(LogicalNegation, interpreter.returned), accesses, func() {
+				interpreter.returned = NewFalse()
+				interpreter.sequence(remaining)
+			}, func() {})
+			return
+		}
+		interpreter.Statement(statements[idx])
+	}
+}
+
+// Returns true if the expression is the true literal.
+func isTrueLiteral(expression Expression) bool {
+	boolean, ok := expression.(Boolean)
+	return ok && boolean.value
+}
+
+// Returns true if the expression is the false literal.
+func isFalseLiteral(expression Expression) bool {
+	boolean, ok := expression.(Boolean)
+	return ok && !boolean.value
+}
+
+// Executes in a scope of the local variables such that only the values of the variables outside of the scope outlive it.
+func (interpreter *SymbolicInterpreter) scoped(locals *VariablesMap, execute func()) {
+	variables, valuations := interpreter.variables, interpreter.valuations
+	interpreter.variables = scopedVariables{inner: locals, outer: variables}
+	interpreter.valuations = valuations.Copy()
+	execute()
+	interpreter.valuations.All(func(symbol symbols.Symbol, value Expression) bool {
+		if _, local := locals.Lookup(symbol); !local {
+			valuations.Assign(symbol, value)
+		}
+		return true
+	})
+	interpreter.variables, interpreter.valuations, interpreter.z3solver = variables, valuations, nil
+}
+
 func (interpreter *SymbolicInterpreter) Expression(expression Expression) *z3.AST {
 	switch cast := any(expression).(type) {
 	case Variable:
@@ -118,10 +484,32 @@ func (interpreter *SymbolicInterpreter) Expression(expression Expression) *z3.AS
 		return interpreter.IfThenElse(cast)
 	case BlockExpression:
 		return interpreter.BlockExpression(cast)
+	case ClockConstraint:
+		// Clocks are interpreted by zones.
+		return interpreter.context.NewTrue()
+	case Index:
+		return z3.Select(interpreter.Expression(cast.array), interpreter.Expression(cast.index))
+	case Member:
+		sort, _ := SortOf(interpreter.variables, cast.record)
+		return member(interpreter.Expression(cast.record), sort, cast.field)
+	case Array:
+		return array(interpreter.expressions(cast.elements))
+	case Record:
+		return record(cast.sort, interpreter.expressions(cast.values))
+	case Call:
+		return interpreter.Call(cast)
 	}
 	panic("Unknown expression type")
 }
 
+func (interpreter *SymbolicInterpreter) expressions(expressions []Expression) []*z3.AST {
+	interpretations := make([]*z3.AST, len(expressions))
+	for idx := range expressions {
+		interpretations[idx] = interpreter.Expression(expressions[idx])
+	}
+	return interpretations
+}
+
 func (interpreter *SymbolicInterpreter) Satisfies(expression Expression) bool {
 	interpretation := interpreter.Expression(expression)
 	return interpreter.canBeTrue(interpretation)
@@ -135,8 +523,9 @@ func (interpreter *SymbolicInterpreter) symbolVariable(symbol symbols.Symbol) *z
 }
 
 func (interpreter *SymbolicInterpreter) Variable(variable Variable) *z3.AST {
+	// Values are terms rather than guards so they are translated such that their equalities do not constrain the path.
 	if value, exists := interpreter.valuations.Value(variable.symbol); exists {
-		valuation := interpreter.Expression(value)
+		valuation := NewZ3Translator(interpreter.context, interpreter.variables).Translate(value)
 		return valuation
 	}
 	if constant := interpreter.symbolVariable(variable.symbol); constant != nil {
@@ -164,13 +553,13 @@ func (interpreter *SymbolicInterpreter) Binary(binary Binary) *z3.AST {
 	switch binary.operator {
 	case Equal:
 		lhs, rhs := interpreter.rightToLeft(binary.lhs, binary.rhs)
-		equality := z3.Eq(lhs, rhs)
+		equality := interpreter.equality(binary.lhs, lhs, rhs)
 		interpreter.constrain(equality)
 		equal := interpreter.canBeTrue(equality)
 		return interpreter.context.NewBoolean(equal)
 	case NotEqual:
 		lhs, rhs := interpreter.rightToLeft(binary.lhs, binary.rhs)
-		equal := interpreter.canBeTrue(z3.Eq(lhs, rhs))
+		equal := interpreter.canBeTrue(interpreter.equality(binary.lhs, lhs, rhs))
 		return interpreter.context.NewBoolean(!equal)
 	case LessThan:
 		lhs, rhs := interpreter.leftToRight(binary.lhs, binary.rhs, nil)
@@ -217,7 +606,16 @@ func (interpreter *SymbolicInterpreter) Binary(binary Binary) *z3.AST {
 		}
 		return rhs
 	}
-	panic("Unknown binary operator")
+	lhs, rhs := interpreter.leftToRight(binary.lhs, binary.rhs, nil)
+	return arithmetic(binary.operator, lhs, rhs)
+}
+
+// Returns the equality of the interpretations where the operand is the left operand of the equality.
+func (interpreter *SymbolicInterpreter) equality(operand Expression, lhs, rhs *z3.AST) *z3.AST {
+	if sort, exists := SortOf(interpreter.variables, operand); exists {
+		return equality(lhs, rhs, sort)
+	}
+	return z3.Eq(lhs, rhs)
 }
 
 func (interpreter *SymbolicInterpreter) Integer(integer Integer) *z3.AST {
@@ -237,7 +635,7 @@ func (interpreter *SymbolicInterpreter) Unary(unary Unary) *z3.AST {
 	case LogicalNegation:
 		return z3.Not(operand)
 	}
-	panic("Unknown unary operator")
+	return negation(unary.operator, operand)
 }
 
 func (interpreter *SymbolicInterpreter) IfThenElse(ite IfThenElse) *z3.AST {
@@ -259,9 +657,21 @@ func (interpreter *SymbolicInterpreter) IfThenElse(ite IfThenElse) *z3.AST {
 	return interpreter.Expression(ite.alternative)
 }
 
+// Executes the statements of the block before its expression is interpreted in their scope.
 func (interpreter *SymbolicInterpreter) BlockExpression(blockExpression BlockExpression) *z3.AST {
-	for idx := range blockExpression.statements {
-		interpreter.Statement(blockExpression.statements[idx])
-	}
-	return interpreter.Expression(blockExpression.expression)
+	var interpretation *z3.AST
+	interpreter.scoped(NewVariablesMap(), func() {
+		for idx := range blockExpression.statements {
+			interpreter.Statement(blockExpression.statements[idx])
+		}
+		interpretation = interpreter.Expression(blockExpression.expression)
+	})
+	return interpretation
+}
+
+// Inlines the call where the values of the variables are substituted into it. The value is translated
+// rather than interpreted such that the equalities of its conditions do not constrain the path.
+func (interpreter *SymbolicInterpreter) Call(call Call) *z3.AST {
+	value := interpreter.substitute(call)
+	return NewZ3Translator(interpreter.context, interpreter.variables).Translate(value)
 }
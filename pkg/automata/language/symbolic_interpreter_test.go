@@ -269,3 +269,552 @@ func Test_SymbolicInterpretation(t *testing.T) {
 		})
 	}
 }
+
+func Test_SymbolicInterpretationRanges(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	x, i := symbols.Insert("x"), symbols.Insert("i")
+	variables := NewVariablesMap()
+	variables.Declare(x, NewBoundedIntegerSort(0, 5))
+	variables.Declare(i, NewBoundedIntegerSort(0, 5))
+	valuations := NewValuationsMap()
+	valuations.Assign(i, NewInteger(5))
+
+	tests := []struct {
+		name       string
+		expression Expression
+		expected   bool
+	}{
+		{
+			name:       "Within range",
+			expression: NewBinary(NewVariable(x), GreaterThanEqual, NewInteger(5)),
+			expected:   true,
+		},
+		{
+			name:       "Outside range",
+			expression: NewBinary(NewVariable(x), GreaterThan, NewInteger(5)),
+			expected:   false,
+		},
+		{
+			name: "Assigned outside range",
+			expression: NewBlockExpression(
+				NewBinary(NewVariable(i), GreaterThan, NewInteger(5)),
+				NewAssignment(NewVariable(i), NewBinary(NewVariable(i), Addition, NewInteger(1))),
+			),
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interpreter := NewSymbolicInterpreter(context, variables, valuations.Copy())
+
+			// Act
+			satisfied := interpreter.Satisfies(tt.expression)
+
+			// Assert
+			assert.Equal(t, tt.expected, satisfied)
+		})
+	}
+}
+
+func Test_SymbolicInterpretationArithmetic(t *testing.T) {
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	x, y := symbols.Insert("x"), symbols.Insert("y")
+
+	variables := NewVariablesMap()
+	variables.Declare(x, IntegerSort)
+	variables.Declare(y, IntegerSort)
+
+	tests := []struct {
+		name       string
+		expression Expression
+		expected   int
+	}{
+		{
+			name:       "x * y",
+			expression: NewBinary(NewVariable(x), Multiplication, NewVariable(y)),
+			expected:   -14,
+		},
+		{
+			name:       "x / y",
+			expression: NewBinary(NewVariable(x), Division, NewVariable(y)),
+			expected:   -3,
+		},
+		{
+			name:       "x % y",
+			expression: NewBinary(NewVariable(x), Modulus, NewVariable(y)),
+			expected:   -1,
+		},
+		{
+			name:       "x / -y",
+			expression: NewBinary(NewVariable(x), Division, Negate(NewVariable(y))),
+			expected:   3,
+		},
+		{
+			name:       "min(x, y)",
+			expression: NewBinary(NewVariable(x), Minimum, NewVariable(y)),
+			expected:   -7,
+		},
+		{
+			name:       "max(x, y)",
+			expression: NewBinary(NewVariable(x), Maximum, NewVariable(y)),
+			expected:   2,
+		},
+		{
+			name:       "x & 6",
+			expression: NewBinary(NewVariable(x), BitwiseAnd, NewInteger(6)),
+			expected:   0,
+		},
+		{
+			name:       "x | y",
+			expression: NewBinary(NewVariable(x), BitwiseOr, NewVariable(y)),
+			expected:   -5,
+		},
+		{
+			name:       "x ^ y",
+			expression: NewBinary(NewVariable(x), BitwiseXor, NewVariable(y)),
+			expected:   -5,
+		},
+		{
+			name:       "~x",
+			expression: NewUnary(BitwiseNegation, NewVariable(x)),
+			expected:   6,
+		},
+		{
+			name:       "x << y",
+			expression: NewBinary(NewVariable(x), LeftShift, NewVariable(y)),
+			expected:   -28,
+		},
+		{
+			name:       "x >> 1",
+			expression: NewBinary(NewVariable(x), RightShift, NewInteger(1)),
+			expected:   -4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			valuations := NewValuationsMap()
+			valuations.Assign(x, NewInteger(-7))
+			valuations.Assign(y, NewInteger(2))
+			symbolic := NewSymbolicInterpreter(context, variables, valuations)
+			concrete := NewConcreteInterpreter(variables, valuations)
+			expected := context.NewInt(tt.expected, context.IntegerSort())
+
+			// Act
+			interpretation := symbolic.Expression(tt.expression)
+			value := concrete.Interpret(tt.expression)
+
+			// Assert
+			assert.True(t, context.NewSolver().Proven(z3.Eq(interpretation, expected)))
+			assert.Equal(t, NewInteger(tt.expected), value)
+		})
+	}
+}
+
+func Test_SymbolicInterpretationAggregates(t *testing.T) {
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	a, r, i := symbols.Insert("a"), symbols.Insert("r"), symbols.Insert("i")
+	record := NewRecordSort(NewField("x", IntegerSort), NewField("b", BooleanSort))
+
+	variables := NewVariablesMap()
+	variables.Declare(a, NewArraySort(NewBoundedIntegerSort(0, 9), 3))
+	variables.Declare(r, record)
+	variables.Declare(i, IntegerSort)
+
+	tests := []struct {
+		name       string
+		expression Expression
+		expected   int
+	}{
+		{
+			name:       "a[i]",
+			expression: NewIndex(NewVariable(a), NewVariable(i)),
+			expected:   2,
+		},
+		{
+			name:       "r.x",
+			expression: NewMember(NewVariable(r), "x"),
+			expected:   4,
+		},
+		{
+			name: "r == {x: 4, b: true} ? 1 : 0",
+			expression: NewIfThenElse(
+				NewBinary(NewVariable(r), Equal, NewRecord(record, NewInteger(4), NewTrue())),
+				NewInteger(1),
+				NewInteger(0),
+			),
+			expected: 1,
+		},
+		{
+			name: "a != [1, 2, 3] ? 1 : 0",
+			expression: NewIfThenElse(
+				NewBinary(NewVariable(a), NotEqual, NewArray(NewInteger(1), NewInteger(2), NewInteger(3))),
+				NewInteger(1),
+				NewInteger(0),
+			),
+			expected: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			valuations := NewValuationsMap()
+			valuations.Assign(a, NewArray(NewInteger(1), NewInteger(2), NewInteger(3)))
+			valuations.Assign(r, NewRecord(record, NewInteger(4), NewTrue()))
+			valuations.Assign(i, NewInteger(1))
+			symbolic := NewSymbolicInterpreter(context, variables, valuations)
+			concrete := NewConcreteInterpreter(variables, valuations)
+			expected := context.NewInt(tt.expected, context.IntegerSort())
+
+			// Act
+			interpretation := symbolic.Expression(tt.expression)
+			value := concrete.Interpret(tt.expression)
+
+			// Assert
+			assert.True(t, context.NewSolver().Proven(z3.Eq(interpretation, expected)))
+			assert.Equal(t, NewInteger(tt.expected), value)
+		})
+	}
+}
+
+func Test_SymbolicInterpretationElementAssignment(t *testing.T) {
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	a, r, i := symbols.Insert("a"), symbols.Insert("r"), symbols.Insert("i")
+	record := NewRecordSort(NewField("x", IntegerSort), NewField("b", BooleanSort))
+
+	variables := NewVariablesMap()
+	variables.Declare(a, NewArraySort(NewBoundedIntegerSort(0, 9), 3))
+	variables.Declare(r, record)
+	variables.Declare(i, IntegerSort)
+
+	tests := []struct {
+		name       string
+		expression Expression
+		expected   int
+	}{
+		{
+			name: "{ a[i] := 7; a[i] + a[0] }",
+			expression: NewBlockExpression(
+				NewBinary(NewIndex(NewVariable(a), NewVariable(i)), Addition, NewIndex(NewVariable(a), NewInteger(0))),
+				NewAssignment(NewIndex(NewVariable(a), NewVariable(i)), NewInteger(7)),
+			),
+			expected: 8,
+		},
+		{
+			name: "{ r.x := r.x + a[2]; r.x }",
+			expression: NewBlockExpression(
+				NewMember(NewVariable(r), "x"),
+				NewAssignment(
+					NewMember(NewVariable(r), "x"),
+					NewBinary(NewMember(NewVariable(r), "x"), Addition, NewIndex(NewVariable(a), NewInteger(2))),
+				),
+			),
+			expected: 7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			valuations := NewValuationsMap()
+			valuations.Assign(a, NewArray(NewInteger(1), NewInteger(2), NewInteger(3)))
+			valuations.Assign(r, NewRecord(record, NewInteger(4), NewTrue()))
+			valuations.Assign(i, NewInteger(1))
+			symbolic := NewSymbolicInterpreter(context, variables, valuations)
+			expected := context.NewInt(tt.expected, context.IntegerSort())
+
+			// Act
+			interpretation := symbolic.Expression(tt.expression)
+
+			// Assert
+			assert.True(t, context.NewSolver().Proven(z3.Eq(interpretation, expected)))
+		})
+	}
+}
+
+func Test_SymbolicInterpretationFunctions(t *testing.T) {
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	y, i, n, s := symbols.Insert("y"), symbols.Insert("i"), symbols.Insert("n"), symbols.Insert("s")
+
+	variables := NewVariablesMap()
+	variables.Declare(y, IntegerSort)
+	variables.Declare(i, IntegerSort)
+
+	// function int f(int n) { int s := 0; while n > 0 do { s := s + y; n := n - 1 }; if s > 5 then { return 5 }; return s }
+	f := NewFunction(
+		symbols.Insert("f"), IntegerSort, []Parameter{NewParameter(n, IntegerSort)},
+		NewDeclaration(s, IntegerSort, NewInteger(0)),
+		NewWhile(
+			NewBinary(NewVariable(n), GreaterThan, NewInteger(0)),
+			NewAssignment(NewVariable(s), NewBinary(NewVariable(s), Addition, NewVariable(y))),
+			NewAssignment(NewVariable(n), NewBinary(NewVariable(n), Subtraction, NewInteger(1))),
+		),
+		NewIf(
+			NewBinary(NewVariable(s), GreaterThan, NewInteger(5)),
+			[]Statement{NewReturn(NewInteger(5))},
+			nil,
+		),
+		NewReturn(NewVariable(s)),
+	)
+
+	tests := []struct {
+		name       string
+		expression Expression
+		expected   int
+	}{
+		{
+			name:       "f(2)",
+			expression: NewCall(f, NewInteger(2)),
+			expected:   4,
+		},
+		{
+			name:       "f(i) + f(0)",
+			expression: NewBinary(NewCall(f, NewVariable(i)), Addition, NewCall(f, NewInteger(0))),
+			expected:   5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			valuations := NewValuationsMap()
+			valuations.Assign(y, NewInteger(2))
+			valuations.Assign(i, NewInteger(4))
+			symbolic := NewSymbolicInterpreter(context, variables, valuations)
+			concrete := NewConcreteInterpreter(variables, valuations)
+			expected := context.NewInt(tt.expected, context.IntegerSort())
+
+			// Act
+			interpretation := symbolic.Expression(tt.expression)
+			value := concrete.Interpret(tt.expression)
+
+			// Assert
+			assert.True(t, context.NewSolver().Proven(z3.Eq(interpretation, expected)))
+			assert.Equal(t, NewInteger(tt.expected), value)
+		})
+	}
+}
+
+func Test_SymbolicInterpretationInlinedFunctions(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	i, n := symbols.Insert("i"), symbols.Insert("n")
+	variables := NewVariablesMap()
+	variables.Declare(i, IntegerSort)
+	zero := context.NewInt(0, context.IntegerSort())
+	f := NewFunction(
+		symbols.Insert("f"), IntegerSort, []Parameter{NewParameter(n, IntegerSort)},
+		NewReturn(NewBinary(NewVariable(n), Multiplication, NewVariable(n))),
+	)
+	// The absolute value returns early for negative integers.
+	abs := NewFunction(
+		symbols.Insert("abs"), IntegerSort, []Parameter{NewParameter(n, IntegerSort)},
+		NewIf(
+			NewBinary(NewVariable(n), LessThan, NewInteger(0)),
+			[]Statement{NewReturn(NewUnary(ArithmeticNegation, NewVariable(n)))}, nil,
+		),
+		NewReturn(NewVariable(n)),
+	)
+	symbolic := NewSymbolicInterpreter(context, variables, NewValuationsMap())
+
+	// Act
+	lhs := symbolic.Expression(NewCall(f, NewVariable(i)))
+	rhs := symbolic.Expression(NewCall(f, NewVariable(i)))
+	other := symbolic.Expression(NewCall(f, NewInteger(1)))
+	absolute := symbolic.Expression(NewCall(abs, NewVariable(i)))
+
+	// Assert
+	assert.True(t, context.NewSolver().Proven(z3.Eq(lhs, rhs)))
+	assert.False(t, context.NewSolver().Proven(z3.Eq(lhs, other)))
+	assert.True(t, context.NewSolver().Proven(z3.GE(lhs, zero)))
+	assert.True(t, context.NewSolver().Proven(z3.GE(absolute, zero)))
+	assert.False(t, context.NewSolver().Proven(z3.Eq(absolute, symbolic.Expression(NewVariable(i)))))
+	assert.NoError(t, symbolic.Err())
+}
+
+func Test_SymbolicInterpretationNestedCalls(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	i, n := symbols.Insert("i"), symbols.Insert("n")
+	variables := NewVariablesMap()
+	variables.Declare(i, IntegerSort)
+	symbol := symbols.Insert("f")
+	parameters := []Parameter{NewParameter(n, IntegerSort)}
+	// The calls are nested one deeper than the number of unrollings.
+	inner := NewFunction(symbol, IntegerSort, parameters, NewReturn(NewVariable(n)))
+	f := NewFunction(symbol, IntegerSort, parameters, NewReturn(NewCall(inner, NewVariable(n))))
+	for idx := 0; idx < DefaultUnrollings; idx++ {
+		f = NewFunction(symbol, IntegerSort, parameters, NewReturn(NewCall(f, NewVariable(n))))
+	}
+	symbolic := NewSymbolicInterpreter(context, variables, NewValuationsMap())
+
+	// Act
+	symbolic.Expression(NewCall(f, NewVariable(i)))
+
+	// Assert
+	assert.EqualError(t, symbolic.Err(), "the call is not bounded by 32 inlinings")
+}
+
+func Test_SymbolicInterpretationControlStatements(t *testing.T) {
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	x, s, k := symbols.Insert("x"), symbols.Insert("s"), symbols.Insert("k")
+
+	variables := NewVariablesMap()
+	variables.Declare(x, IntegerSort)
+	variables.Declare(s, IntegerSort)
+
+	tests := []struct {
+		name      string
+		statement Statement
+		expected  int
+	}{
+		{
+			name: "if x > 2 then {s := 1} else {s := 2}",
+			statement: NewIf(
+				NewBinary(NewVariable(x), GreaterThan, NewInteger(2)),
+				[]Statement{NewAssignment(NewVariable(s), NewInteger(1))},
+				[]Statement{NewAssignment(NewVariable(s), NewInteger(2))},
+			),
+			expected: 1,
+		},
+		{
+			name: "while x > 0 do {s := s + x; x := x - 1}",
+			statement: NewWhile(
+				NewBinary(NewVariable(x), GreaterThan, NewInteger(0)),
+				NewAssignment(NewVariable(s), NewBinary(NewVariable(s), Addition, NewVariable(x))),
+				NewAssignment(NewVariable(x), NewBinary(NewVariable(x), Subtraction, NewInteger(1))),
+			),
+			expected: 6,
+		},
+		{
+			name: "for k := 1 to 4 do {s := s + k * x}",
+			statement: NewFor(k, 1, 4, NewAssignment(
+				NewVariable(s),
+				NewBinary(NewVariable(s), Addition, NewBinary(NewVariable(k), Multiplication, NewVariable(x))),
+			)),
+			expected: 30,
+		},
+		{
+			name: "{int k := x; s := k * k}",
+			statement: NewSequence(
+				NewDeclaration(k, IntegerSort, NewVariable(x)),
+				NewAssignment(NewVariable(s), NewBinary(NewVariable(k), Multiplication, NewVariable(k))),
+			),
+			expected: 9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			symbolicValuations, concreteValuations := NewValuationsMap(), NewValuationsMap()
+			for _, valuations := range []*ValuationsMap{symbolicValuations, concreteValuations} {
+				valuations.Assign(x, NewInteger(3))
+				valuations.Assign(s, NewInteger(0))
+			}
+			symbolic := NewSymbolicInterpreter(context, variables, symbolicValuations)
+			concrete := NewConcreteInterpreter(variables, concreteValuations)
+			expected := context.NewInt(tt.expected, context.IntegerSort())
+
+			// Act
+			symbolic.Statement(tt.statement)
+			concrete.Statement(tt.statement)
+
+			// Assert
+			assert.True(t, context.NewSolver().Proven(z3.Eq(symbolic.Variable(NewVariable(s)), expected)))
+			value, _ := concreteValuations.Value(s)
+			assert.Equal(t, NewInteger(tt.expected), value)
+			_, symbolicLeak := symbolicValuations.Value(k)
+			_, concreteLeak := concreteValuations.Value(k)
+			assert.False(t, symbolicLeak)
+			assert.False(t, concreteLeak)
+		})
+	}
+}
+
+func Test_SymbolicInterpretationPathSensitiveStatements(t *testing.T) {
+	context := z3.NewContext(z3.NewConfig())
+	symbols := symbols.NewSymbolsMap[string](symbols.NewSymbolsFactory())
+	x, y, s := symbols.Insert("x"), symbols.Insert("y"), symbols.Insert("s")
+
+	// The value of y is unknown such that the conditions on it are undetermined.
+	variables := NewVariablesMap()
+	variables.Declare(x, IntegerSort)
+	variables.Declare(y, NewBoundedIntegerSort(0, 5))
+	variables.Declare(s, IntegerSort)
+
+	t.Run("if y > 2 then {s := 1} else {s := 2}", func(t *testing.T) {
+		// Arrange
+		valuations := NewValuationsMap()
+		condition := NewBinary(NewVariable(y), GreaterThan, NewInteger(2))
+		symbolic := NewSymbolicInterpreter(context, variables, valuations)
+
+		// Act
+		symbolic.Statement(NewIf(
+			condition,
+			[]Statement{NewAssignment(NewVariable(s), NewInteger(1))},
+			[]Statement{NewAssignment(NewVariable(s), NewInteger(2))},
+		))
+
+		// Assert
+		value, _ := valuations.Value(s)
+		assert.Equal(t, NewIfThenElse(condition, NewInteger(1), NewInteger(2)), value)
+	})
+
+	t.Run("x := y; while x < 3 do {x := x + 1}", func(t *testing.T) {
+		// Arrange
+		valuations := NewValuationsMap()
+		valuations.Assign(x, NewVariable(y))
+		symbolic := NewSymbolicInterpreter(context, variables, valuations)
+
+		// Act
+		symbolic.Statement(NewWhile(
+			NewBinary(NewVariable(x), LessThan, NewInteger(3)),
+			NewAssignment(NewVariable(x), NewBinary(NewVariable(x), Addition, NewInteger(1))),
+		))
+
+		// Assert
+		assert.False(t, symbolic.Satisfies(NewBinary(NewVariable(x), LessThan, NewInteger(3))))
+		assert.True(t, symbolic.Satisfies(NewBinary(NewVariable(x), GreaterThan, NewInteger(4))))
+		assert.NoError(t, symbolic.Err())
+	})
+
+	t.Run("x := s; while x > 0 do {x := x - 1}", func(t *testing.T) {
+		// Arrange
+		valuations := NewValuationsMap()
+		valuations.Assign(x, NewVariable(s))
+		symbolic := NewSymbolicInterpreter(context, variables, valuations)
+
+		// Act
+		symbolic.Statement(NewWhile(
+			NewBinary(NewVariable(x), GreaterThan, NewInteger(0)),
+			NewAssignment(NewVariable(x), NewBinary(NewVariable(x), Subtraction, NewInteger(1))),
+		))
+
+		// Assert
+		assert.EqualError(t, symbolic.Err(), "the while loop is not bounded by 32 unrollings")
+	})
+
+	t.Run("x := y; while x > 0 do {x := x - 1} with 4 unrollings", func(t *testing.T) {
+		// Arrange
+		valuations := NewValuationsMap()
+		valuations.Assign(x, NewVariable(y))
+		symbolic := NewSymbolicInterpreter(context, variables, valuations)
+		symbolic.SetUnrollings(4)
+
+		// Act
+		symbolic.Statement(NewWhile(
+			NewBinary(NewVariable(x), GreaterThan, NewInteger(0)),
+			NewAssignment(NewVariable(x), NewBinary(NewVariable(x), Subtraction, NewInteger(1))),
+		))
+
+		// Assert
+		assert.EqualError(t, symbolic.Err(), "the while loop is not bounded by 4 unrollings")
+	})
+}
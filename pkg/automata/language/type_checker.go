@@ -0,0 +1,362 @@
+package language
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// An expression or a statement whose sorts do not agree with the sorts of the declared variables and clocks.
+type TypeError struct {
+	message string
+}
+
+func (err TypeError) Error() string {
+	return err.message
+}
+
+// Checks the sorts of expressions and statements against the declared variables and clocks without interpreting
+// them. All errors are collected such that an expression with several mistakes reports each of them once.
+type TypeChecker struct {
+	symbols   symbols.Store[any]
+	variables Variables
+	clocks    Clocks
+	// The sort returned by the function whose body is checked.
+	returns *Sort
+	// The functions whose bodies have been checked.
+	checked map[symbols.Symbol]bool
+	errors  *[]TypeError
+}
+
+// Constructs a checker where the symbols are used to write the expressions of errors. The clocks may be nil if there are none.
+func NewTypeChecker(store symbols.Store[any], variables Variables, clocks Clocks) *TypeChecker {
+	return &TypeChecker{
+		symbols:   store,
+		variables: variables,
+		clocks:    clocks,
+		checked:   map[symbols.Symbol]bool{},
+		errors:    &[]TypeError{},
+	}
+}
+
+// Returns the errors found since the checker was constructed or the errors were last taken.
+func (checker *TypeChecker) Errors() []TypeError {
+	errors := *checker.errors
+	*checker.errors = []TypeError{}
+	return errors
+}
+
+func (checker *TypeChecker) fail(format string, arguments ...any) {
+	*checker.errors = append(*checker.errors, TypeError{
+		message: fmt.Sprintf(format, arguments...),
+	})
+}
+
+// Returns the expression as it is written by the pretty printer.
+func (checker *TypeChecker) print(expression Expression) string {
+	var buffer bytes.Buffer
+	expression.Accept(NewPrettyPrinter(&buffer, checker.symbols))
+	return buffer.String()
+}
+
+func (checker *TypeChecker) name(symbol symbols.Symbol) string {
+	name, _ := checker.symbols.Item(symbol)
+	return fmt.Sprint(name)
+}
+
+func (checker *TypeChecker) isClock(symbol symbols.Symbol) bool {
+	if checker.clocks == nil {
+		return false
+	}
+	_, exists := checker.clocks.Lookup(symbol)
+	return exists
+}
+
+// Returns true if values of the sorts can be compared and assigned to each other. The ranges of integers are ignored.
+func compatible(lhs, rhs Sort) bool {
+	if lhs.kind != rhs.kind {
+		return false
+	}
+	switch lhs.kind {
+	case ArrayKind:
+		return lhs.length == rhs.length && compatible(*lhs.element, *rhs.element)
+	case RecordKind:
+		if len(lhs.fields) != len(rhs.fields) {
+			return false
+		}
+		for idx := range lhs.fields {
+			if lhs.fields[idx].name != rhs.fields[idx].name || !compatible(lhs.fields[idx].sort, rhs.fields[idx].sort) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Checks that the expression is of the sort.
+func (checker *TypeChecker) expect(expression Expression, sort Sort, format string, arguments ...any) {
+	if actual, ok := checker.Expression(expression); ok && !compatible(actual, sort) {
+		checker.fail(format, arguments...)
+	}
+}
+
+// Reports the errors of the expression and an error if it is not a boolean.
+func (checker *TypeChecker) Condition(expression Expression) {
+	checker.expect(expression, BooleanSort, "the condition \"%s\" must be of the sort bool", checker.print(expression))
+}
+
+// Returns the sort of the expression if it is well-sorted. Otherwise, its errors are reported.
+func (checker *TypeChecker) Expression(expression Expression) (sort Sort, ok bool) {
+	switch cast := any(expression).(type) {
+	case Variable:
+		if sort, exists := checker.variables.Lookup(cast.symbol); exists {
+			return sort, true
+		}
+		if checker.isClock(cast.symbol) {
+			checker.fail("the clock \"%s\" can only be used in clock constraints", checker.name(cast.symbol))
+		} else {
+			checker.fail("undeclared variable \"%s\"", checker.name(cast.symbol))
+		}
+	case Integer:
+		return IntegerSort, true
+	case Boolean:
+		return BooleanSort, true
+	case Binary:
+		return checker.binary(cast)
+	case Unary:
+		operand, ok := checker.Expression(cast.operand)
+		expected := IntegerSort
+		if cast.operator == LogicalNegation {
+			expected = BooleanSort
+		}
+		if ok && !compatible(operand, expected) {
+			checker.fail("the operand of \"%s\" must be of the sort %s", checker.print(cast), expected)
+			return Sort{}, false
+		}
+		return expected, ok
+	case IfThenElse:
+		checker.Condition(cast.condition)
+		consequence, consequenceOk := checker.Expression(cast.consequence)
+		alternative, alternativeOk := checker.Expression(cast.alternative)
+		if !consequenceOk || !alternativeOk {
+			return Sort{}, false
+		}
+		if !compatible(consequence, alternative) {
+			checker.fail("the alternatives of \"%s\" must be of the same sort", checker.print(cast))
+			return Sort{}, false
+		}
+		return consequence, true
+	case BlockExpression:
+		checker.scoped(NewVariablesMap(), func() {
+			checker.statements(cast.statements)
+			sort, ok = checker.Expression(cast.expression)
+		})
+		return sort, ok
+	case ClockConstraint:
+		for _, symbol := range []symbols.Symbol{cast.lhs, cast.rhs} {
+			if !checker.isClock(symbol) {
+				checker.fail("\"%s\" of the clock constraint \"%s\" is not a clock", checker.name(symbol), checker.print(cast))
+			}
+		}
+		return BooleanSort, true
+	case Index:
+		return checker.index(cast)
+	case Member:
+		record, ok := checker.Expression(cast.record)
+		if !ok {
+			return Sort{}, false
+		}
+		if record.kind != RecordKind {
+			checker.fail("\"%s\" is not a record", checker.print(cast.record))
+			return Sort{}, false
+		}
+		if _, field, exists := record.Field(cast.field); exists {
+			return field.sort, true
+		}
+		checker.fail("\"%s\" has no field \"%s\"", checker.print(cast.record), cast.field)
+	case Array:
+		element, ok := checker.Expression(cast.elements[0])
+		for idx := 1; idx < len(cast.elements); idx++ {
+			if sort, elementOk := checker.Expression(cast.elements[idx]); elementOk && ok && !compatible(sort, element) {
+				checker.fail("the elements of \"%s\" must be of the same sort", checker.print(cast))
+				ok = false
+			}
+		}
+		if ok {
+			return NewArraySort(element, len(cast.elements)), true
+		}
+	case Record:
+		for idx, field := range cast.sort.fields {
+			checker.expect(cast.values[idx], field.sort,
+				"the field \"%s\" of \"%s\" must be of the sort %s", field.name, checker.print(cast), field.sort,
+			)
+		}
+		return cast.sort, true
+	case Call:
+		checker.function(cast.function)
+		for idx, parameter := range cast.function.Parameters() {
+			checker.expect(cast.arguments[idx], parameter.sort,
+				"the argument %d of \"%s\" must be of the sort %s", idx+1, checker.print(cast), parameter.sort,
+			)
+		}
+		return cast.function.sort, true
+	default:
+		panic("Unknown expression type")
+	}
+	return Sort{}, false
+}
+
+func (checker *TypeChecker) binary(binary Binary) (Sort, bool) {
+	lhs, lhsOk := checker.Expression(binary.lhs)
+	rhs, rhsOk := checker.Expression(binary.rhs)
+
+	operands, result := IntegerSort, IntegerSort
+	switch binary.operator {
+	case Equal, NotEqual:
+		if lhsOk && rhsOk && !compatible(lhs, rhs) {
+			checker.fail("the operands of \"%s\" must be of the same sort", checker.print(binary))
+			return Sort{}, false
+		}
+		return BooleanSort, lhsOk && rhsOk
+	case LessThan, LessThanEqual, GreaterThan, GreaterThanEqual:
+		result = BooleanSort
+	case LogicalAnd, LogicalOr, Implication:
+		operands, result = BooleanSort, BooleanSort
+	}
+
+	if (lhsOk && !compatible(lhs, operands)) || (rhsOk && !compatible(rhs, operands)) {
+		checker.fail("the operands of \"%s\" must be of the sort %s", checker.print(binary), operands)
+		return Sort{}, false
+	}
+	return result, lhsOk && rhsOk
+}
+
+func (checker *TypeChecker) index(index Index) (Sort, bool) {
+	array, ok := checker.Expression(index.array)
+	checker.expect(index.index, IntegerSort, "the index of \"%s\" must be of the sort int", checker.print(index))
+	if !ok {
+		return Sort{}, false
+	}
+	if array.kind != ArrayKind {
+		checker.fail("\"%s\" is not an array", checker.print(index.array))
+		return Sort{}, false
+	}
+	if position, constant := index.index.(Integer); constant && (position.value < 0 || position.value >= array.length) {
+		checker.fail("the index of \"%s\" is out of bounds", checker.print(index))
+	}
+	return *array.element, true
+}
+
+// Checks the body of the function once where its parameters and local variables are declared.
+func (checker *TypeChecker) function(function Function) {
+	if checker.checked[function.symbol] {
+		return
+	}
+	checker.checked[function.symbol] = true
+
+	body := &TypeChecker{
+		symbols:   checker.symbols,
+		variables: function.scope(checker.variables),
+		clocks:    checker.clocks,
+		returns:   &function.sort,
+		checked:   checker.checked,
+		errors:    checker.errors,
+	}
+	body.statements(function.body)
+}
+
+// Checks the statements in a scope of the local variables.
+func (checker *TypeChecker) scoped(locals *VariablesMap, check func()) {
+	variables := checker.variables
+	checker.variables = scopedVariables{inner: locals, outer: variables}
+	check()
+	checker.variables = variables
+}
+
+func (checker *TypeChecker) statements(statements []Statement) {
+	for idx := range statements {
+		checker.Statement(statements[idx])
+	}
+}
+
+// Reports the errors of the statement.
+func (checker *TypeChecker) Statement(statement Statement) {
+	switch cast := any(statement).(type) {
+	case Assignment:
+		checker.assignment(cast)
+	case ClockAssignment:
+		checker.clock(cast.lhs)
+		checker.clock(cast.rhs)
+	case ClockShift:
+		checker.clock(cast.clock)
+	case ClockReset:
+		checker.clock(cast.clock)
+	case Declaration:
+		checker.expect(cast.initial, cast.sort,
+			"the initial value of \"%s\" must be of the sort %s", checker.name(cast.symbol), cast.sort,
+		)
+		checker.variables.Declare(cast.symbol, cast.sort)
+	case If:
+		checker.Condition(cast.condition)
+		checker.scoped(NewVariablesMap(), func() {
+			checker.statements(cast.consequence)
+		})
+		checker.scoped(NewVariablesMap(), func() {
+			checker.statements(cast.alternative)
+		})
+	case While:
+		checker.Condition(cast.condition)
+		checker.scoped(NewVariablesMap(), func() {
+			checker.statements(cast.body)
+		})
+	case For:
+		locals := NewVariablesMap()
+		locals.Declare(cast.symbol, IntegerSort)
+		checker.scoped(locals, func() {
+			checker.statements(cast.body)
+		})
+	case Sequence:
+		checker.scoped(NewVariablesMap(), func() {
+			checker.statements(cast.statements)
+		})
+	case Return:
+		if checker.returns == nil {
+			checker.fail("cannot return outside of a function")
+			checker.Expression(cast.expression)
+			return
+		}
+		checker.expect(cast.expression, *checker.returns,
+			"the returned value \"%s\" must be of the sort %s", checker.print(cast.expression), *checker.returns,
+		)
+	default:
+		panic("Unknown statement type")
+	}
+}
+
+func (checker *TypeChecker) assignment(assignment Assignment) {
+	variable, ok := assignment.Variable()
+	if !ok {
+		checker.fail("\"%s\" is not a variable and cannot be assigned", checker.print(assignment.lhs))
+		checker.Expression(assignment.rhs)
+		return
+	}
+	if _, declared := checker.variables.Lookup(variable.symbol); !declared && checker.isClock(variable.symbol) {
+		checker.fail("the clock \"%s\" can only be reset, shifted or assigned a clock", checker.name(variable.symbol))
+		checker.Expression(assignment.rhs)
+		return
+	}
+	lhs, lhsOk := checker.Expression(assignment.lhs)
+	rhs, rhsOk := checker.Expression(assignment.rhs)
+	if lhsOk && rhsOk && !compatible(lhs, rhs) {
+		checker.fail("\"%s\" of the sort %s cannot be assigned \"%s\" of the sort %s",
+			checker.print(assignment.lhs), lhs, checker.print(assignment.rhs), rhs,
+		)
+	}
+}
+
+func (checker *TypeChecker) clock(symbol symbols.Symbol) {
+	if !checker.isClock(symbol) {
+		checker.fail("\"%s\" is not a clock", checker.name(symbol))
+	}
+}
@@ -0,0 +1,152 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TypeChecker(t *testing.T) {
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	i, b, a, r := symbolsMap.Insert("i"), symbolsMap.Insert("b"), symbolsMap.Insert("a"), symbolsMap.Insert("r")
+	x, u := symbolsMap.Insert("x"), symbolsMap.Insert("u")
+	f, n := symbolsMap.Insert("f"), symbolsMap.Insert("n")
+	point := NewRecordSort(NewField("px", IntegerSort), NewField("py", IntegerSort))
+	increment := NewFunction(f, IntegerSort, []Parameter{NewParameter(n, IntegerSort)},
+		NewReturn(NewBinary(NewVariable(n), LessThan, NewInteger(1))),
+	)
+
+	tests := []struct {
+		name       string
+		expression Expression
+		errors     []string
+	}{
+		{
+			name: "Well-sorted",
+			expression: NewBlockExpression(
+				NewBinary(NewIndex(NewVariable(a), NewVariable(i)), LessThan, NewMember(NewVariable(r), "px")),
+				NewAssignment(NewVariable(i), NewBinary(NewVariable(i), Addition, NewInteger(1))),
+				NewIf(NewVariable(b), []Statement{NewClockReset(x, 0)}, nil),
+				NewFor(n, 0, 2, NewAssignment(NewIndex(NewVariable(a), NewVariable(n)), NewInteger(0))),
+			),
+		},
+		{
+			name:       "Integer and boolean operands",
+			expression: NewBinary(NewVariable(i), Addition, NewTrue()),
+			errors:     []string{"the operands of \"i + true\" must be of the sort int"},
+		},
+		{
+			name:       "Boolean and integer operands",
+			expression: NewBinary(NewVariable(b), LogicalAnd, NewVariable(i)),
+			errors:     []string{"the operands of \"b ∧ i\" must be of the sort bool"},
+		},
+		{
+			name:       "Comparison of sorts",
+			expression: NewBinary(NewVariable(i), Equal, NewVariable(b)),
+			errors:     []string{"the operands of \"i = b\" must be of the same sort"},
+		},
+		{
+			name:       "Integer negation",
+			expression: LogicalNegate(NewVariable(i)),
+			errors:     []string{"the operand of \"¬(i)\" must be of the sort bool"},
+		},
+		{
+			name:       "Undeclared variable",
+			expression: NewBinary(NewVariable(u), LessThan, NewInteger(1)),
+			errors:     []string{"undeclared variable \"u\""},
+		},
+		{
+			name:       "Clock as variable",
+			expression: NewBinary(NewVariable(x), LessThan, NewInteger(1)),
+			errors:     []string{"the clock \"x\" can only be used in clock constraints"},
+		},
+		{
+			name:       "Variable in clock constraint",
+			expression: NewClockConstraint(i, x, zones.NewRelation(0, zones.Strict)),
+			errors:     []string{"\"i\" of the clock constraint \"i - x < 0\" is not a clock"},
+		},
+		{
+			name:       "Condition",
+			expression: NewIfThenElse(NewVariable(i), NewInteger(1), NewFalse()),
+			errors: []string{
+				"the condition \"i\" must be of the sort bool",
+				"the alternatives of \"i ? 1 : false\" must be of the same sort",
+			},
+		},
+		{
+			name:       "Index and member",
+			expression: NewBinary(NewIndex(NewVariable(r), NewInteger(0)), Addition, NewMember(NewVariable(r), "pz")),
+			errors:     []string{"\"r\" is not an array", "\"r\" has no field \"pz\""},
+		},
+		{
+			name:       "Index out of bounds",
+			expression: NewBinary(NewIndex(NewVariable(a), NewInteger(3)), LessThan, NewInteger(0)),
+			errors:     []string{"the index of \"a[3]\" is out of bounds"},
+		},
+		{
+			name:       "Argument and return",
+			expression: NewBinary(NewCall(increment, NewVariable(b)), LessThan, NewInteger(0)),
+			errors: []string{
+				"the returned value \"n < 1\" must be of the sort int",
+				"the argument 1 of \"f(b)\" must be of the sort int",
+			},
+		},
+		{
+			name: "Assignments",
+			expression: NewBlockExpression(
+				NewTrue(),
+				NewAssignment(NewVariable(i), NewTrue()),
+				NewAssignment(NewVariable(x), NewInteger(0)),
+				NewAssignment(NewInteger(1), NewInteger(0)),
+				NewClockReset(i, 0),
+			),
+			errors: []string{
+				"\"i\" of the sort int cannot be assigned \"true\" of the sort bool",
+				"the clock \"x\" can only be reset, shifted or assigned a clock",
+				"\"1\" is not a variable and cannot be assigned",
+				"\"i\" is not a clock",
+			},
+		},
+		{
+			name: "Statements",
+			expression: NewBlockExpression(
+				NewTrue(),
+				NewWhile(NewVariable(i), NewAssignment(NewVariable(b), NewFalse())),
+				NewReturn(NewVariable(i)),
+			),
+			errors: []string{
+				"the condition \"i\" must be of the sort bool",
+				"cannot return outside of a function",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			variables := NewVariablesMap()
+			variables.Declare(i, IntegerSort)
+			variables.Declare(b, BooleanSort)
+			variables.Declare(a, NewArraySort(IntegerSort, 3))
+			variables.Declare(r, point)
+			clocks := NewClocksMap(symbolsMap.Insert("0"))
+			clocks.Declare(x)
+			checker := NewTypeChecker(symbolsMap, variables, clocks)
+
+			// Act
+			checker.Expression(tt.expression)
+			errors := checker.Errors()
+
+			// Assert
+			messages := make([]string, len(errors))
+			for idx := range errors {
+				messages[idx] = errors[idx].Error()
+			}
+			assert.Equal(t, len(tt.errors), len(messages))
+			if len(tt.errors) > 0 {
+				assert.Equal(t, tt.errors, messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package language
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// Writes expressions and statements in the syntax of UPPAAL labels. Nested binary
+// expressions are parenthesised such that the precedence of the operators is kept.
+type UPPAALPrinter struct {
+	writer  io.Writer
+	symbols symbols.Store[any]
+	clocks  Clocks
+	nested  bool
+}
+
+// The clocks are used to find the reference clock of clock constraints and may be nil if there are no clocks.
+func NewUPPAALPrinter(
+	writer io.Writer,
+	symbols symbols.Store[any],
+	clocks Clocks,
+) UPPAALPrinter {
+	return UPPAALPrinter{
+		writer:  writer,
+		symbols: symbols,
+		clocks:  clocks,
+	}
+}
+
+// Returns the name as an UPPAAL identifier where all characters which
+// are not allowed in identifiers are replaced by underscores.
+func Identifier(name string) string {
+	var builder strings.Builder
+	for idx, character := range name {
+		switch {
+		case character == '_' || (character < unicode.MaxASCII && unicode.IsLetter(character)):
+			builder.WriteRune(character)
+		case character < unicode.MaxASCII && unicode.IsDigit(character):
+			if idx == 0 {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(character)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	if builder.Len() == 0 {
+		return "_"
+	}
+	return builder.String()
+}
+
+func (printer UPPAALPrinter) WriteString(text string) {
+	io.WriteString(printer.writer, text)
+}
+
+// Writes the symbol as an UPPAAL identifier.
+func (printer UPPAALPrinter) WriteSymbol(symbol symbols.Symbol) {
+	name, _ := printer.symbols.Item(symbol)
+	printer.WriteString(Identifier(fmt.Sprint(name)))
+}
+
+func (printer UPPAALPrinter) isReference(symbol symbols.Symbol) bool {
+	if printer.clocks == nil {
+		return false
+	}
+	clock, exists := printer.clocks.Lookup(symbol)
+	return exists && clock == zones.Reference
+}
+
+// Returns the printer used for operands which must be parenthesised if they are not atomic.
+func (printer UPPAALPrinter) operand() UPPAALPrinter {
+	printer.nested = true
+	return printer
+}
+
+// Returns the printer used for expressions which are delimited such that they need no parentheses.
+func (printer UPPAALPrinter) unnested() UPPAALPrinter {
+	printer.nested = false
+	return printer
+}
+
+func (printer UPPAALPrinter) open() {
+	if printer.nested {
+		printer.WriteString("(")
+	}
+}
+
+func (printer UPPAALPrinter) close() {
+	if printer.nested {
+		printer.WriteString(")")
+	}
+}
+
+// Writes the clock statements and assignments of the expression separated by commas.
+func (printer UPPAALPrinter) Update(expression Expression) {
+	for idx, statement := range collectStatements(expression, nil) {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		statement.Accept(printer)
+	}
+}
+
+func (printer UPPAALPrinter) Assignment(assignment Assignment) {
+	assignment.lhs.Accept(printer)
+	printer.WriteString(" = ")
+	assignment.rhs.Accept(printer)
+}
+
+func (printer UPPAALPrinter) ClockConstraint(constraint ClockConstraint) {
+	if constraint.relation.IsInfinity() {
+		printer.WriteString("true")
+		return
+	}
+
+	operator, limit := "<=", constraint.relation.Limit()
+	if constraint.relation.Strictness() == zones.Strict {
+		operator = "<"
+	}
+
+	printer.open()
+	switch {
+	case printer.isReference(constraint.rhs):
+		// x - 0 ~ n ≡ x ~ n
+		printer.WriteSymbol(constraint.lhs)
+		printer.WriteString(fmt.Sprintf(" %s %v", operator, limit))
+	case printer.isReference(constraint.lhs):
+		// 0 - x ~ n ≡ x ~' -n
+		printer.WriteSymbol(constraint.rhs)
+		printer.WriteString(fmt.Sprintf(" %s %v", strings.ReplaceAll(operator, "<", ">"), -limit))
+	default:
+		printer.WriteSymbol(constraint.lhs)
+		printer.WriteString(" - ")
+		printer.WriteSymbol(constraint.rhs)
+		printer.WriteString(fmt.Sprintf(" %s %v", operator, limit))
+	}
+	printer.close()
+}
+
+func (printer UPPAALPrinter) ClockAssignment(assignment ClockAssignment) {
+	printer.WriteSymbol(assignment.lhs)
+	printer.WriteString(" = ")
+	printer.WriteSymbol(assignment.rhs)
+}
+
+func (printer UPPAALPrinter) ClockShift(shift ClockShift) {
+	printer.WriteSymbol(shift.clock)
+	printer.WriteString(" = ")
+	printer.WriteSymbol(shift.clock)
+	if shift.limit >= 0 {
+		printer.WriteString(fmt.Sprintf(" + %v", shift.limit))
+	} else {
+		printer.WriteString(fmt.Sprintf(" - %v", -shift.limit))
+	}
+}
+
+func (printer UPPAALPrinter) ClockReset(reset ClockReset) {
+	printer.WriteSymbol(reset.clock)
+	printer.WriteString(fmt.Sprintf(" = %v", reset.limit))
+}
+
+func (printer UPPAALPrinter) Variable(variable Variable) {
+	printer.WriteSymbol(variable.symbol)
+}
+
+func (printer UPPAALPrinter) Binary(binary Binary) {
+	printer.open()
+	binary.lhs.Accept(printer.operand())
+	switch binary.operator {
+	case Equal:
+		printer.WriteString(" == ")
+	case NotEqual:
+		printer.WriteString(" != ")
+	case LessThan:
+		printer.WriteString(" < ")
+	case LessThanEqual:
+		printer.WriteString(" <= ")
+	case GreaterThan:
+		printer.WriteString(" > ")
+	case GreaterThanEqual:
+		printer.WriteString(" >= ")
+	case LogicalAnd:
+		printer.WriteString(" && ")
+	case LogicalOr:
+		printer.WriteString(" || ")
+	case Addition:
+		printer.WriteString(" + ")
+	case Subtraction:
+		printer.WriteString(" - ")
+	case Implication:
+		printer.WriteString(" imply ")
+	case Multiplication:
+		printer.WriteString(" * ")
+	case Division:
+		printer.WriteString(" / ")
+	case Modulus:
+		printer.WriteString(" % ")
+	case Minimum:
+		printer.WriteString(" <? ")
+	case Maximum:
+		printer.WriteString(" >? ")
+	case BitwiseAnd:
+		printer.WriteString(" & ")
+	case BitwiseOr:
+		printer.WriteString(" | ")
+	case BitwiseXor:
+		printer.WriteString(" ^ ")
+	case LeftShift:
+		printer.WriteString(" << ")
+	case RightShift:
+		printer.WriteString(" >> ")
+	default:
+		panic("Unknown binary operator")
+	}
+	binary.rhs.Accept(printer.operand())
+	printer.close()
+}
+
+func (printer UPPAALPrinter) Integer(integer Integer) {
+	if integer.value < 0 {
+		printer.open()
+		printer.WriteString(fmt.Sprintf("%v", integer.value))
+		printer.close()
+		return
+	}
+	printer.WriteString(fmt.Sprintf("%v", integer.value))
+}
+
+func (printer UPPAALPrinter) Boolean(boolean Boolean) {
+	printer.WriteString(fmt.Sprintf("%v", boolean.value))
+}
+
+func (printer UPPAALPrinter) Unary(unary Unary) {
+	switch unary.operator {
+	case LogicalNegation:
+		printer.WriteString("!")
+	case ArithmeticNegation:
+		printer.WriteString("-")
+	case BitwiseNegation:
+		printer.WriteString("~")
+	default:
+		panic("Unknown unary operator")
+	}
+	unary.operand.Accept(printer.operand())
+}
+
+// Only the expression of the block is written as the statements are written by Update.
+func (printer UPPAALPrinter) BlockExpression(block BlockExpression) {
+	block.expression.Accept(printer)
+}
+
+func (printer UPPAALPrinter) IfThenElse(ite IfThenElse) {
+	printer.open()
+	ite.condition.Accept(printer.operand())
+	printer.WriteString(" ? ")
+	ite.consequence.Accept(printer.operand())
+	printer.WriteString(" : ")
+	ite.alternative.Accept(printer.operand())
+	printer.close()
+}
+
+func (printer UPPAALPrinter) Index(index Index) {
+	index.array.Accept(printer.operand())
+	printer.WriteString("[")
+	index.index.Accept(printer.unnested())
+	printer.WriteString("]")
+}
+
+func (printer UPPAALPrinter) Member(member Member) {
+	member.record.Accept(printer.operand())
+	printer.WriteString(".")
+	printer.WriteString(Identifier(member.field))
+}
+
+// Arrays and records are written as the initialisers "{a, b}".
+func (printer UPPAALPrinter) Array(array Array) {
+	printer.initialiser(array.elements)
+}
+
+func (printer UPPAALPrinter) Record(record Record) {
+	printer.initialiser(record.values)
+}
+
+func (printer UPPAALPrinter) initialiser(values []Expression) {
+	printer.WriteString("{")
+	for idx := range values {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		values[idx].Accept(printer.unnested())
+	}
+	printer.WriteString("}")
+}
+
+func (printer UPPAALPrinter) Call(call Call) {
+	printer.WriteSymbol(call.function.symbol)
+	printer.WriteString("(")
+	for idx := range call.arguments {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		call.arguments[idx].Accept(printer.unnested())
+	}
+	printer.WriteString(")")
+}
+
+// Writes the function as the declaration "int f(int x) { return x + 1; }".
+func (printer UPPAALPrinter) Function(function Function) {
+	name, _ := printer.symbols.Item(function.symbol)
+	printer.WriteString(fmt.Sprintf("%s %s(", function.sort, Identifier(fmt.Sprint(name))))
+	for idx, parameter := range function.Parameters() {
+		if idx > 0 {
+			printer.WriteString(", ")
+		}
+		name, _ := printer.symbols.Item(parameter.symbol)
+		printer.WriteString(parameter.sort.Declaration(Identifier(fmt.Sprint(name))))
+	}
+	printer.WriteString(") ")
+	printer.block(function.body)
+}
+
+// Writes the statements in braces where assignments, declarations and returns end with semicolons.
+func (printer UPPAALPrinter) block(statements []Statement) {
+	printer.WriteString("{ ")
+	for idx := range statements {
+		statements[idx].Accept(printer.unnested())
+		switch statements[idx].(type) {
+		case If, While, For, Sequence:
+			printer.WriteString(" ")
+		default:
+			printer.WriteString("; ")
+		}
+	}
+	printer.WriteString("}")
+}
+
+func (printer UPPAALPrinter) Declaration(declaration Declaration) {
+	name, _ := printer.symbols.Item(declaration.symbol)
+	printer.WriteString(declaration.sort.Declaration(Identifier(fmt.Sprint(name))))
+	printer.WriteString(" = ")
+	declaration.initial.Accept(printer.unnested())
+}
+
+func (printer UPPAALPrinter) If(statement If) {
+	printer.WriteString("if (")
+	statement.condition.Accept(printer.unnested())
+	printer.WriteString(") ")
+	printer.block(statement.consequence)
+	if len(statement.alternative) > 0 {
+		printer.WriteString(" else ")
+		printer.block(statement.alternative)
+	}
+}
+
+func (printer UPPAALPrinter) While(loop While) {
+	printer.WriteString("while (")
+	loop.condition.Accept(printer.unnested())
+	printer.WriteString(") ")
+	printer.block(loop.body)
+}
+
+// Writes the loop as the iteration "for (i : int[0,3]) { … }" over the range of its bounds.
+func (printer UPPAALPrinter) For(loop For) {
+	name, _ := printer.symbols.Item(loop.symbol)
+	printer.WriteString(fmt.Sprintf("for (%s : int[%d,%d]) ", Identifier(fmt.Sprint(name)), loop.lower, loop.upper))
+	printer.block(loop.body)
+}
+
+func (printer UPPAALPrinter) Sequence(sequence Sequence) {
+	printer.block(sequence.statements)
+}
+
+func (printer UPPAALPrinter) Return(statement Return) {
+	printer.WriteString("return ")
+	statement.expression.Accept(printer.unnested())
+}
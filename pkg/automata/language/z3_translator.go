@@ -3,6 +3,7 @@ package language
 import (
 	"github.com/Brandhoej/gobion/internal/z3"
 	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
 )
 
 type Z3Translator struct {
@@ -21,15 +22,127 @@ func NewZ3Translator(
 }
 
 func (translator Z3Translator) sort(sort Sort) *z3.Sort {
-	if sort == BooleanSort {
-		return translator.context.BooleanSort()
-	}
-	if sort == IntegerSort {
-		return translator.context.IntegerSort()
+	return translateSort(translator.context, sort)
+}
+
+// Returns the z3 sort of the sort. Arrays are arrays from integers and records are tuples which
+// are named by their sort such that records with the same fields are of the same tuple sort.
+func translateSort(context *z3.Context, sort Sort) *z3.Sort {
+	switch sort.kind {
+	case BooleanKind:
+		return context.BooleanSort()
+	case IntegerKind:
+		return context.IntegerSort()
+	case ArrayKind:
+		return context.ArraySort(context.IntegerSort(), translateSort(context, *sort.element))
+	case RecordKind:
+		names := make([]string, len(sort.fields))
+		sorts := make([]*z3.Sort, len(sort.fields))
+		for idx, field := range sort.fields {
+			names[idx] = field.name
+			sorts[idx] = translateSort(context, field.sort)
+		}
+		return context.TupleSort(sort.String(), names, sorts)
 	}
 	panic("Unknown sort")
 }
 
+// Returns the conjunction of the range constraints of all variables.
+func (translator Z3Translator) Ranges() *z3.AST {
+	ranges := translator.context.NewTrue()
+	translator.variables.All(func(symbol symbols.Symbol, _ Sort) bool {
+		ranges = z3.And(ranges, translator.Range(symbol))
+		return true
+	})
+	return ranges
+}
+
+// Returns the range constraint "lower ≤ v ≤ upper" of the variable if its sort is a bounded integer sort.
+// The elements and fields of arrays and records of bounded integers are constrained likewise.
+func (translator Z3Translator) Range(symbol symbols.Symbol) *z3.AST {
+	sort, _ := translator.variables.Lookup(symbol)
+	return within(translator.symbolVariable(symbol), sort)
+}
+
+func within(value *z3.AST, sort Sort) *z3.AST {
+	context := value.Context()
+	switch sort.kind {
+	case ArrayKind:
+		constraint := context.NewTrue()
+		for idx := 0; idx < sort.length; idx++ {
+			element := z3.Select(value, context.NewInt(idx, context.IntegerSort()))
+			constraint = z3.And(constraint, within(element, *sort.element))
+		}
+		return constraint
+	case RecordKind:
+		constraint := context.NewTrue()
+		for idx, field := range sort.fields {
+			constraint = z3.And(constraint, within(fieldOf(value, idx), field.sort))
+		}
+		return constraint
+	}
+	lower, upper, bounded := sort.Bounds()
+	if !bounded {
+		return context.NewTrue()
+	}
+	return z3.And(
+		z3.LE(context.NewInt(lower, context.IntegerSort()), value),
+		z3.LE(value, context.NewInt(upper, context.IntegerSort())),
+	)
+}
+
+// Returns the equality of the values of the sort. Arrays are equal if their elements are equal as
+// the elements outside of the length of the arrays are irrelevant.
+func equality(lhs, rhs *z3.AST, sort Sort) *z3.AST {
+	context := lhs.Context()
+	switch sort.kind {
+	case ArrayKind:
+		constraint := context.NewTrue()
+		for idx := 0; idx < sort.length; idx++ {
+			index := context.NewInt(idx, context.IntegerSort())
+			constraint = z3.And(constraint, equality(z3.Select(lhs, index), z3.Select(rhs, index), *sort.element))
+		}
+		return constraint
+	case RecordKind:
+		constraint := context.NewTrue()
+		for idx, field := range sort.fields {
+			constraint = z3.And(constraint, equality(fieldOf(lhs, idx), fieldOf(rhs, idx), field.sort))
+		}
+		return constraint
+	}
+	return z3.Eq(lhs, rhs)
+}
+
+// Returns the field of the record at the position.
+func fieldOf(record *z3.AST, index int) *z3.AST {
+	return record.Sort().TupleField(index).Application([]*z3.AST{record})
+}
+
+// Returns the member of the record whose sort is the sort.
+func member(record *z3.AST, sort Sort, name string) *z3.AST {
+	index, _, exists := sort.Field(name)
+	if !exists {
+		panic("The record does not have the field")
+	}
+	return fieldOf(record, index)
+}
+
+// Returns the array of the elements where the elements outside of its length are the first element.
+func array(elements []*z3.AST) *z3.AST {
+	context := elements[0].Context()
+	array := context.NewConstantArray(context.IntegerSort(), elements[0])
+	for idx := range elements {
+		array = z3.Store(array, context.NewInt(idx, context.IntegerSort()), elements[idx])
+	}
+	return array
+}
+
+// Returns the record of the values of the fields of the sort.
+func record(sort Sort, values []*z3.AST) *z3.AST {
+	context := values[0].Context()
+	return translateSort(context, sort).TupleConstructor().Application(values)
+}
+
 func (translator Z3Translator) Translate(expression Expression) *z3.AST {
 	switch cast := any(expression).(type) {
 	case Variable:
@@ -44,10 +157,31 @@ func (translator Z3Translator) Translate(expression Expression) *z3.AST {
 		return translator.unary(cast)
 	case IfThenElse:
 		return translator.ifThenElse(cast)
+	case ClockConstraint:
+		return translator.clockConstraint(cast)
+	case Index:
+		return z3.Select(translator.Translate(cast.array), translator.Translate(cast.index))
+	case Member:
+		sort, _ := SortOf(translator.variables, cast.record)
+		return member(translator.Translate(cast.record), sort, cast.field)
+	case Array:
+		return array(translator.translateAll(cast.elements))
+	case Record:
+		return record(cast.sort, translator.translateAll(cast.values))
+	case Call:
+		return translator.call(cast)
 	}
 	panic("Unknown expression type")
 }
 
+func (translator Z3Translator) translateAll(expressions []Expression) []*z3.AST {
+	translations := make([]*z3.AST, len(expressions))
+	for idx := range expressions {
+		translations[idx] = translator.Translate(expressions[idx])
+	}
+	return translations
+}
+
 func (translator Z3Translator) symbolVariable(symbol symbols.Symbol) *z3.AST {
 	if sort, exists := translator.variables.Lookup(symbol); exists {
 		return translator.context.NewConstant(z3.WithInt(int(symbol)), translator.sort(sort))
@@ -63,10 +197,15 @@ func (translator Z3Translator) binary(binary Binary) *z3.AST {
 	lhs := translator.Translate(binary.lhs)
 	rhs := translator.Translate(binary.rhs)
 	switch binary.operator {
-	case Equal:
-		return z3.Eq(lhs, rhs)
-	case NotEqual:
-		return z3.Not(z3.Eq(lhs, rhs))
+	case Equal, NotEqual:
+		equal := z3.Eq(lhs, rhs)
+		if sort, exists := SortOf(translator.variables, binary.lhs); exists {
+			equal = equality(lhs, rhs, sort)
+		}
+		if binary.operator == NotEqual {
+			return z3.Not(equal)
+		}
+		return equal
 	case LessThan:
 		return z3.LT(lhs, rhs)
 	case LessThanEqual:
@@ -86,7 +225,7 @@ func (translator Z3Translator) binary(binary Binary) *z3.AST {
 	case Implication:
 		return z3.Implies(lhs, rhs)
 	}
-	panic("Unknown binary operator")
+	return arithmetic(binary.operator, lhs, rhs)
 }
 
 func (translator Z3Translator) integer(integer Integer) *z3.AST {
@@ -106,7 +245,7 @@ func (translator Z3Translator) unary(unary Unary) *z3.AST {
 	case LogicalNegation:
 		return z3.Not(operand)
 	}
-	panic("Unknown unary operator")
+	return negation(unary.operator, operand)
 }
 
 func (translator Z3Translator) ifThenElse(ifThenElse IfThenElse) *z3.AST {
@@ -116,3 +255,99 @@ func (translator Z3Translator) ifThenElse(ifThenElse IfThenElse) *z3.AST {
 		translator.Translate(ifThenElse.alternative),
 	)
 }
+
+// Clocks are translated as real valued constants where only their difference is constrained.
+func (translator Z3Translator) clockConstraint(constraint ClockConstraint) *z3.AST {
+	if constraint.relation.IsInfinity() {
+		return translator.context.NewTrue()
+	}
+
+	lhs := translator.context.NewConstant(z3.WithInt(int(constraint.lhs)), translator.context.RealSort())
+	rhs := translator.context.NewConstant(z3.WithInt(int(constraint.rhs)), translator.context.RealSort())
+	difference := z3.Subtract(lhs, rhs)
+	limit := translator.context.NewInt(constraint.relation.Limit(), translator.context.RealSort())
+	if constraint.relation.Strictness() == zones.Strict {
+		return z3.LT(difference, limit)
+	}
+	return z3.LE(difference, limit)
+}
+
+// Returns the translation of the arithmetic or bitwise operator which is shared by the translator and interpreter.
+func arithmetic(operator BinaryOperator, lhs, rhs *z3.AST) *z3.AST {
+	switch operator {
+	case Multiplication:
+		return z3.Multiply(lhs, rhs)
+	case Division:
+		quotient, _ := truncatedDivision(lhs, rhs)
+		return quotient
+	case Modulus:
+		_, remainder := truncatedDivision(lhs, rhs)
+		return remainder
+	case Minimum:
+		return z3.ITE(z3.LE(lhs, rhs), lhs, rhs)
+	case Maximum:
+		return z3.ITE(z3.GE(lhs, rhs), lhs, rhs)
+	case BitwiseAnd:
+		return bitwise(z3.BitwiseAnd, lhs, rhs)
+	case BitwiseOr:
+		return bitwise(z3.BitwiseOr, lhs, rhs)
+	case BitwiseXor:
+		return bitwise(z3.BitwiseXor, lhs, rhs)
+	case LeftShift:
+		return bitwise(z3.ShiftLeft, lhs, rhs)
+	case RightShift:
+		return bitwise(z3.ArithmeticShiftRight, lhs, rhs)
+	}
+	panic("Unknown binary operator")
+}
+
+// Returns the quotient and remainder of the division truncated towards zero. The division of z3 is Euclidean
+// such that the remainder is never negative. For negative dividends with a remainder the Euclidean quotient
+// is one less than the truncated quotient if the divisor is positive and one more if it is negative.
+func truncatedDivision(lhs, rhs *z3.AST) (quotient, remainder *z3.AST) {
+	context := lhs.Context()
+	zero := context.NewInt(0, context.IntegerSort())
+	one := context.NewInt(1, context.IntegerSort())
+	euclidean := z3.Divide(lhs, rhs)
+	quotient = z3.ITE(
+		z3.Or(z3.GE(lhs, zero), z3.Eq(z3.Modulus(lhs, rhs), zero)),
+		euclidean,
+		z3.ITE(z3.GT(rhs, zero), z3.Add(euclidean, one), z3.Subtract(euclidean, one)),
+	)
+	return quotient, z3.Subtract(lhs, z3.Multiply(rhs, quotient))
+}
+
+// Applies the bit-vector operation to the integers as 32-bit two's complement bit-vectors.
+func bitwise(operation func(lhs, rhs *z3.AST) *z3.AST, lhs, rhs *z3.AST) *z3.AST {
+	return z3.BitVectorToInteger(
+		operation(z3.IntegerToBitVector(BitWidth, lhs), z3.IntegerToBitVector(BitWidth, rhs)), true,
+	)
+}
+
+// Returns the translation of the arithmetic or bitwise negation.
+func negation(operator UnaryOperator, operand *z3.AST) *z3.AST {
+	switch operator {
+	case ArithmeticNegation:
+		return z3.Minus(operand)
+	case BitwiseNegation:
+		return z3.BitVectorToInteger(z3.BitwiseNot(z3.IntegerToBitVector(BitWidth, operand)), true)
+	}
+	panic("Unknown unary operator")
+}
+
+// Calls which read no variables are executed. Other calls must be inlined by the symbolic interpreter
+// beforehand as their values depend on the paths through the body of the function.
+func (translator Z3Translator) call(call Call) *z3.AST {
+	if isClosed(call) {
+		value := NewConcreteInterpreter(translator.variables, NewValuationsMap()).Interpret(call)
+		return translator.Translate(value)
+	}
+	panic("Calls which read variables must be inlined before they are translated")
+}
+
+// Returns true if the expression reads no variables or clocks.
+func isClosed(expression Expression) bool {
+	return NewAccesses(expression).All(func(symbols.Symbol) bool {
+		return false
+	})
+}
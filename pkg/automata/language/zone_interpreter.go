@@ -0,0 +1,340 @@
+package language
+
+import (
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// Interprets the clock constraints and clock statements of expressions on zones.
+// All other expressions are ignored as they are interpreted by a data interpreter.
+type ZoneInterpreter struct {
+	clocks Clocks
+}
+
+func NewZoneInterpreter(clocks Clocks) ZoneInterpreter {
+	return ZoneInterpreter{
+		clocks: clocks,
+	}
+}
+
+func (interpreter ZoneInterpreter) clock(symbol symbols.Symbol) zones.Clock {
+	if clock, exists := interpreter.clocks.Lookup(symbol); exists {
+		return clock
+	}
+	panic("Unknown clock")
+}
+
+// Constrains the zone by the clock constraints which must hold for the expression
+// to be satisfied. Returns false if the zone becomes empty. The clock constraints
+// must be convex as a disjunction of clock constraints cannot be represented by a zone.
+func (interpreter ZoneInterpreter) Constrain(zone zones.DBM, expression Expression) bool {
+	interpreter.constrain(zone, expression, true)
+	return zone.IsConsistent()
+}
+
+// The polarity is false when the expression is under a negation. This allows the
+// negation of a conjunction of clock constraints to be pushed down to the constraints.
+func (interpreter ZoneInterpreter) constrain(zone zones.DBM, expression Expression, polarity bool) {
+	switch cast := any(expression).(type) {
+	case ClockConstraint:
+		lhs, rhs := interpreter.clock(cast.lhs), interpreter.clock(cast.rhs)
+		if polarity {
+			if !cast.relation.IsInfinity() {
+				zone.ConstrainAndClose(lhs, rhs, cast.relation)
+			}
+		} else {
+			// ¬(lhs - rhs ~ n) ≡ rhs - lhs ~' -n
+			if cast.relation.IsInfinity() {
+				zone.Empty()
+			} else {
+				zone.ConstrainAndClose(rhs, lhs, cast.relation.Negation())
+			}
+		}
+	case Boolean:
+		if cast.value != polarity {
+			zone.Empty()
+		}
+	case Unary:
+		if cast.operator == LogicalNegation {
+			interpreter.constrain(zone, cast.operand, !polarity)
+		}
+	case Binary:
+		switch cast.operator {
+		case LogicalAnd:
+			if polarity {
+				interpreter.constrain(zone, cast.lhs, polarity)
+				interpreter.constrain(zone, cast.rhs, polarity)
+				return
+			}
+		case LogicalOr:
+			if !polarity {
+				interpreter.constrain(zone, cast.lhs, polarity)
+				interpreter.constrain(zone, cast.rhs, polarity)
+				return
+			}
+		case Implication:
+			// ¬(P → Q) ≡ P ∧ ¬Q
+			if !polarity {
+				interpreter.constrain(zone, cast.lhs, !polarity)
+				interpreter.constrain(zone, cast.rhs, polarity)
+				return
+			}
+		default:
+			return
+		}
+
+		// The expression is a disjunction which cannot be represented by a single zone.
+		if HasClockConstraints(cast) {
+			panic("Disjunctive clock constraints cannot be represented by a zone")
+		}
+	case BlockExpression:
+		interpreter.constrain(zone, cast.expression, polarity)
+	}
+}
+
+// Returns true if the clock constraints of the expression can be represented by a single zone.
+// That is, the clock constraints are not under a disjunction or a conditional expression.
+func IsConvex(expression Expression) bool {
+	return isConvex(expression, true)
+}
+
+func isConvex(expression Expression, polarity bool) bool {
+	switch cast := any(expression).(type) {
+	case Unary:
+		if cast.operator == LogicalNegation {
+			return isConvex(cast.operand, !polarity)
+		}
+	case Binary:
+		switch cast.operator {
+		case LogicalAnd:
+			if polarity {
+				return isConvex(cast.lhs, polarity) && isConvex(cast.rhs, polarity)
+			}
+		case LogicalOr:
+			if !polarity {
+				return isConvex(cast.lhs, polarity) && isConvex(cast.rhs, polarity)
+			}
+		case Implication:
+			if !polarity {
+				return isConvex(cast.lhs, !polarity) && isConvex(cast.rhs, polarity)
+			}
+		default:
+			return true
+		}
+		return !HasClockConstraints(cast)
+	case IfThenElse:
+		return !HasClockConstraints(cast)
+	case BlockExpression:
+		return isConvex(cast.expression, polarity)
+	}
+	return true
+}
+
+// Applies all clock statements of the expression on the zone from left to right.
+func (interpreter ZoneInterpreter) Apply(zone zones.DBM, expression Expression) {
+	switch cast := any(expression).(type) {
+	case Binary:
+		interpreter.Apply(zone, cast.lhs)
+		interpreter.Apply(zone, cast.rhs)
+	case Unary:
+		interpreter.Apply(zone, cast.operand)
+	case BlockExpression:
+		for idx := range cast.statements {
+			interpreter.Statement(zone, cast.statements[idx])
+		}
+		interpreter.Apply(zone, cast.expression)
+	}
+}
+
+// Computes the clock valuations before the clock statements of the expression given the zone of the
+// valuations after them. The statements are undone from right to left such that reset and assigned
+// clocks are freed. Returns false if no valuations after the statements are in the zone.
+func (interpreter ZoneInterpreter) Unapply(zone zones.DBM, expression Expression) bool {
+	statements := collectStatements(expression, nil)
+	for idx := len(statements) - 1; idx >= 0; idx-- {
+		switch cast := any(statements[idx]).(type) {
+		case ClockReset:
+			clock := interpreter.clock(cast.clock)
+			zone.ConstrainAndClose(clock, zones.Reference, zones.NewRelation(cast.limit, zones.Weak))
+			zone.ConstrainAndClose(zones.Reference, clock, zones.NewRelation(-cast.limit, zones.Weak))
+			if !zone.IsConsistent() {
+				return false
+			}
+			zone.Free(clock)
+		case ClockAssignment:
+			lhs, rhs := interpreter.clock(cast.lhs), interpreter.clock(cast.rhs)
+			if lhs == rhs {
+				continue
+			}
+			zone.ConstrainAndClose(lhs, rhs, zones.Zero)
+			zone.ConstrainAndClose(rhs, lhs, zones.Zero)
+			if !zone.IsConsistent() {
+				return false
+			}
+			zone.Free(lhs)
+		case ClockShift:
+			zone.Shift(interpreter.clock(cast.clock), -cast.limit)
+		}
+	}
+	return zone.IsConsistent()
+}
+
+// Returns the statements of the expression in the order they are applied.
+func collectStatements(expression Expression, statements []Statement) []Statement {
+	switch cast := any(expression).(type) {
+	case Binary:
+		statements = collectStatements(cast.lhs, statements)
+		statements = collectStatements(cast.rhs, statements)
+	case Unary:
+		statements = collectStatements(cast.operand, statements)
+	case BlockExpression:
+		statements = append(statements, cast.statements...)
+		statements = collectStatements(cast.expression, statements)
+	}
+	return statements
+}
+
+// Applies the statement on the zone if it is a clock statement.
+func (interpreter ZoneInterpreter) Statement(zone zones.DBM, statement Statement) {
+	switch cast := any(statement).(type) {
+	case ClockReset:
+		zone.Reset(interpreter.clock(cast.clock), cast.limit)
+	case ClockAssignment:
+		zone.Assign(interpreter.clock(cast.lhs), interpreter.clock(cast.rhs))
+	case ClockShift:
+		zone.Shift(interpreter.clock(cast.clock), cast.limit)
+	}
+}
+
+// Returns true if the expression contains any clock constraints.
+func HasClockConstraints(expression Expression) bool {
+	switch cast := any(expression).(type) {
+	case ClockConstraint:
+		return true
+	case Binary:
+		return HasClockConstraints(cast.lhs) || HasClockConstraints(cast.rhs)
+	case Unary:
+		return HasClockConstraints(cast.operand)
+	case IfThenElse:
+		return HasClockConstraints(cast.condition) ||
+			HasClockConstraints(cast.consequence) ||
+			HasClockConstraints(cast.alternative)
+	case BlockExpression:
+		return HasClockConstraints(cast.expression)
+	}
+	return false
+}
+
+// Returns true if the expression contains a difference constraint between two clocks which are not the reference.
+func (interpreter ZoneInterpreter) HasDiagonalConstraints(expression Expression) bool {
+	switch cast := any(expression).(type) {
+	case ClockConstraint:
+		return interpreter.clock(cast.lhs) != zones.Reference && interpreter.clock(cast.rhs) != zones.Reference
+	case Binary:
+		return interpreter.HasDiagonalConstraints(cast.lhs) || interpreter.HasDiagonalConstraints(cast.rhs)
+	case Unary:
+		return interpreter.HasDiagonalConstraints(cast.operand)
+	case IfThenElse:
+		return interpreter.HasDiagonalConstraints(cast.condition) ||
+			interpreter.HasDiagonalConstraints(cast.consequence) ||
+			interpreter.HasDiagonalConstraints(cast.alternative)
+	case BlockExpression:
+		return interpreter.HasDiagonalConstraints(cast.expression)
+	}
+	return false
+}
+
+// Raises the lower and upper bounds of the clocks by the constants the clocks are compared to in
+// the expression. A lower bound is a constant the clock is compared to from below "x > c" and an
+// upper bound from above "x < c". Constants of difference constraints raise both bounds of both
+// clocks. The bounds are indexed by the clocks excluding the reference clock.
+func (interpreter ZoneInterpreter) Bounds(expression Expression, lowers, uppers []int) {
+	interpreter.bounds(expression, lowers, uppers, true)
+}
+
+func (interpreter ZoneInterpreter) bounds(expression Expression, lowers, uppers []int, polarity bool) {
+	switch cast := any(expression).(type) {
+	case ClockConstraint:
+		if cast.relation.IsInfinity() {
+			return
+		}
+		lhs, rhs, relation := interpreter.clock(cast.lhs), interpreter.clock(cast.rhs), cast.relation
+		if !polarity {
+			// ¬(lhs - rhs ~ n) ≡ rhs - lhs ~' -n
+			lhs, rhs, relation = rhs, lhs, relation.Negation()
+		}
+		raise := func(bounds []int, clock zones.Clock, limit int) {
+			if clock != zones.Reference {
+				bounds[clock-1] = max(bounds[clock-1], limit)
+			}
+		}
+		switch {
+		case rhs == zones.Reference:
+			raise(uppers, lhs, relation.Limit())
+		case lhs == zones.Reference:
+			raise(lowers, rhs, -relation.Limit())
+		default:
+			limit := max(relation.Limit(), -relation.Limit())
+			for _, clock := range []zones.Clock{lhs, rhs} {
+				raise(lowers, clock, limit)
+				raise(uppers, clock, limit)
+			}
+		}
+	case Unary:
+		if cast.operator == LogicalNegation {
+			polarity = !polarity
+		}
+		interpreter.bounds(cast.operand, lowers, uppers, polarity)
+	case Binary:
+		if cast.operator == Implication {
+			interpreter.bounds(cast.lhs, lowers, uppers, !polarity)
+		} else {
+			interpreter.bounds(cast.lhs, lowers, uppers, polarity)
+		}
+		interpreter.bounds(cast.rhs, lowers, uppers, polarity)
+	case IfThenElse:
+		interpreter.bounds(cast.condition, lowers, uppers, true)
+		interpreter.bounds(cast.condition, lowers, uppers, false)
+		interpreter.bounds(cast.consequence, lowers, uppers, polarity)
+		interpreter.bounds(cast.alternative, lowers, uppers, polarity)
+	case BlockExpression:
+		interpreter.bounds(cast.expression, lowers, uppers, polarity)
+	}
+}
+
+// Returns the bounds of the clocks before the clock statements of the expression given the bounds
+// after them. Reset clocks do not depend on their previous value and are therefore not bounded,
+// assigned clocks pass their bounds on to the clocks they are assigned and shifted clocks keep
+// their bounds such that the bounds cannot grow indefinitely on cycles.
+func (interpreter ZoneInterpreter) BoundsBefore(
+	expression Expression, lowers, uppers []int,
+) (lowersBefore []int, uppersBefore []int) {
+	lowersBefore, uppersBefore = make([]int, len(lowers)), make([]int, len(uppers))
+	copy(lowersBefore, lowers)
+	copy(uppersBefore, uppers)
+
+	// The statements are applied in reverse to get the bounds before them.
+	statements := collectStatements(expression, nil)
+	for idx := len(statements) - 1; idx >= 0; idx-- {
+		for _, bounds := range [][]int{lowersBefore, uppersBefore} {
+			switch cast := any(statements[idx]).(type) {
+			case ClockReset:
+				if clock := interpreter.clock(cast.clock); clock != zones.Reference {
+					bounds[clock-1] = 0
+				}
+			case ClockAssignment:
+				lhs, rhs := interpreter.clock(cast.lhs), interpreter.clock(cast.rhs)
+				if lhs == zones.Reference || lhs == rhs {
+					continue
+				}
+				limit := bounds[lhs-1]
+				bounds[lhs-1] = 0
+				if rhs != zones.Reference {
+					bounds[rhs-1] = max(bounds[rhs-1], limit)
+				}
+			}
+		}
+	}
+
+	return lowersBefore, uppersBefore
+}
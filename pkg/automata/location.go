@@ -2,9 +2,21 @@ package automata
 
 import "github.com/Brandhoej/gobion/pkg/automata/language"
 
+type Urgency uint8
+
+const (
+	// Time can pass in the location.
+	Delayable = Urgency(iota)
+	// Time cannot pass in the location.
+	Urgent
+	// Time cannot pass in the location and the next edge of a network must leave a committed location.
+	Committed
+)
+
 type Location struct {
 	name      string
 	invariant Invariant
+	urgency   Urgency
 }
 
 func NewLocation(name string, invariant Invariant) Location {
@@ -14,6 +26,23 @@ func NewLocation(name string, invariant Invariant) Location {
 	}
 }
 
+func (location Location) Name() string {
+	return location.name
+}
+
+func (location Location) Invariant() Invariant {
+	return location.invariant
+}
+
+func (location Location) Urgency() Urgency {
+	return location.urgency
+}
+
+// Returns true if time cannot pass in the location. That is when it is urgent or committed.
+func (location Location) IsUrgent() bool {
+	return location.urgency != Delayable
+}
+
 func (location Location) IsEnabled(valuations language.Valuations, solver *Interpreter) bool {
 	return location.invariant.IsSatisfiable(valuations, solver)
 }
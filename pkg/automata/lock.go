@@ -0,0 +1,204 @@
+package automata
+
+import (
+	"slices"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// Returns a trace for each reachable deadlock. A deadlock is a set of valuations of a state from which no
+// edge can be traversed and time cannot pass. The last state of each trace is restricted to the deadlock.
+func (system *SymbolicTransitionSystem) Deadlocks(valuations language.Valuations) (traces []Trace) {
+	graph := system.explore(valuations)
+	for index, state := range graph.states {
+		for _, zone := range system.Deadlocked(state) {
+			trace := graph.trace(index)
+			trace[len(trace)-1] = NewState(state.location, state.valuations, state.constraint, zone)
+			traces = append(traces, trace)
+		}
+	}
+	return traces
+}
+
+// Returns the disjoint zones of the valuations of the state which are deadlocked. Time cannot pass from the
+// valuations at the non-strict upper bounds of the invariant and an edge can be traversed by the valuations
+// satisfying its guard which also satisfy the invariant of its destination after the update.
+func (system *SymbolicTransitionSystem) Deadlocked(state State) []zones.DBM {
+	location, _ := system.automaton.Location(state.location)
+	if !location.IsUrgent() && state.zone.CanDelayIndefinitely() {
+		return nil
+	}
+
+	// The valuations where time cannot pass which are all valuations in urgent locations.
+	stuck := []zones.DBM{}
+	if location.IsUrgent() {
+		stuck = append(stuck, state.zone.Copy())
+	}
+	for clock := zones.Clock(1); clock < state.zone.Clocks() && !location.IsUrgent(); clock++ {
+		upper := state.zone.Upper(clock)
+		if upper.IsInfinity() || upper.Strictness() == zones.Strict {
+			continue
+		}
+		// The previous clocks are not at their bound such that the zones are disjoint.
+		zone := state.zone.Copy()
+		for previous := zones.Clock(1); previous < clock; previous++ {
+			if bound := state.zone.Upper(previous); !bound.IsInfinity() && bound.Strictness() == zones.Weak {
+				zone.ConstrainAndClose(previous, zones.Reference, zones.NewRelation(bound.Limit(), zones.Strict))
+			}
+		}
+		zone.ConstrainAndClose(zones.Reference, clock, zones.NewRelation(-upper.Limit(), zones.Weak))
+		if zone.IsConsistent() {
+			stuck = append(stuck, zone)
+		}
+	}
+
+	if !location.IsEnabled(state.valuations, system.interpreter) {
+		return stuck
+	}
+	for _, edge := range system.automaton.Outgoing(state.location) {
+		enabled, ok := system.enabled(state, edge)
+		if !ok {
+			continue
+		}
+		remaining := []zones.DBM{}
+		for _, zone := range stuck {
+			remaining = append(remaining, zone.Subtraction(enabled)...)
+		}
+		stuck = remaining
+	}
+	return stuck
+}
+
+// Returns the zone of the valuations of the state from which the edge can be traversed.
+func (system *SymbolicTransitionSystem) enabled(state State, edge Edge) (zone zones.DBM, enabled bool) {
+	if !edge.IsEnabled(state.valuations, system.interpreter) {
+		return zone, false
+	}
+
+	zone = state.zone.Copy()
+	if !system.zones.Constrain(zone, edge.guard.condition) {
+		return zone, false
+	}
+
+	// The valuations which satisfy the invariant of the destination after the update.
+	destination, _ := system.automaton.Location(edge.destination)
+	allowed := zones.NewDBM(state.zone.Clocks(), zones.NewInfinity())
+	if !system.zones.Constrain(allowed, destination.invariant.condition) ||
+		!system.zones.Unapply(allowed, edge.update.expression) {
+		return zone, false
+	}
+	zone.Intersection(allowed, zones.Reference, zone.Clocks())
+	zone.Close()
+	return zone, zone.IsConsistent()
+}
+
+// The clocks of a system and an additional progress clock which is only reset by progress transitions.
+type progressClocks struct {
+	language.Clocks
+}
+
+func (clocks progressClocks) Dimensions() zones.Clock {
+	return clocks.Clocks.Dimensions() + 1
+}
+
+// Returns a trace for each reachable state from which time cannot diverge. Time diverges from a state if a
+// path from it reaches a cycle which lets at least 1 time unit pass. Such cycles are found by a progress clock
+// which is reset by a progress transition once it has reached 1 such that time diverges exactly when a cycle
+// of the graph has a progress transition. The states of the graph are only merged if they are equal and the
+// traces are projected onto the clocks of the system where states included in a reported state are omitted.
+func (system *SymbolicTransitionSystem) Timelocks(valuations language.Valuations) (traces []Trace) {
+	clock := system.clocks.Dimensions()
+	progress := NewTimedTransitionSystem(system.automaton, progressClocks{system.clocks}, system.interpreter)
+	for location := range progress.bounds.lowers {
+		progress.bounds.lowers[location][clock-1] = 1
+		progress.bounds.uppers[location][clock-1] = 1
+	}
+
+	graph := progress.exploreBy(progress.Initial(valuations), func(state, explored State) bool {
+		return state.SubsetOf(explored, system.interpreter) && explored.SubsetOf(state, system.interpreter)
+	}, func(state State) (State, bool) {
+		zone := state.zone.Copy()
+		zone.ConstrainAndClose(zones.Reference, clock, zones.NewRelation(-1, zones.Weak))
+		if !zone.IsConsistent() {
+			return state, false
+		}
+		zone.Reset(clock, 0)
+		location, _ := progress.automaton.Location(state.location)
+		progress.delay(zone, state.location, location)
+		return NewState(state.location, state.valuations, state.constraint, zone), true
+	})
+
+	divergent := make([]bool, len(graph.states))
+	for _, component := range graph.components() {
+		if graph.progresses(component) {
+			for _, index := range component {
+				divergent[index] = true
+			}
+		}
+	}
+
+	// Time can diverge from all states which can reach a state from which time can diverge.
+	for changed := true; changed; {
+		changed = false
+		for index := range graph.states {
+			if divergent[index] {
+				continue
+			}
+			for _, transition := range graph.transitions[index] {
+				if divergent[transition.destination] {
+					divergent[index], changed = true, true
+					break
+				}
+			}
+		}
+	}
+
+	reported := []State{}
+	for index := range graph.states {
+		if divergent[index] {
+			continue
+		}
+		trace := graph.trace(index)
+		for idx := range trace {
+			trace[idx].zone = project(trace[idx].zone)
+		}
+		state := trace[len(trace)-1]
+		if slices.ContainsFunc(reported, func(other State) bool {
+			return state.SubsetOf(other, system.interpreter)
+		}) {
+			continue
+		}
+		reported = append(reported, state)
+		traces = append(traces, trace)
+	}
+	return traces
+}
+
+// Returns true if the component has a progress transition between two of its states.
+func (graph *stateGraph) progresses(component []int) bool {
+	members := map[int]bool{}
+	for _, index := range component {
+		members[index] = true
+	}
+
+	for _, index := range component {
+		for _, transition := range graph.transitions[index] {
+			if transition.progress && members[transition.destination] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the zone without its last clock. The zone is canonical such that its other constraints are unaffected.
+func project(zone zones.DBM) zones.DBM {
+	projection := zones.NewDBM(zone.Clocks()-1, zones.NewInfinity())
+	for row := zones.Reference; row < projection.Clocks(); row++ {
+		for column := zones.Reference; column < projection.Clocks(); column++ {
+			projection.Constrain(row, column, zone.Constraint(row, column))
+		}
+	}
+	return projection
+}
@@ -0,0 +1,116 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Deadlocks(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	clocks := language.NewClocksMap(reference)
+	clock := clocks.Declare(x)
+	builder := NewAutomatonBuilder()
+	// Time can pass until "x ≤ 5" but the edge can only be traversed when "x ≤ 3".
+	idle := builder.AddInitial("idle", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(5, zones.Weak))),
+	))
+	done := builder.AddLocation("done")
+	builder.AddEdge(idle, done, WithGuard(NewGuard(
+		language.NewClockConstraint(x, reference, zones.NewRelation(3, zones.Weak)),
+	)))
+	automaton := builder.Build()
+	system := NewTimedTransitionSystem(&automaton, clocks, interpreter)
+
+	// Act
+	traces := system.Deadlocks(language.NewValuationsMap())
+
+	// Assert
+	assert.Len(t, traces, 1)
+	assert.Len(t, traces[0], 1)
+	assert.Equal(t, idle, traces[0][0].Location())
+	assert.Equal(t, zones.NewRelation(5, zones.Weak), traces[0][0].Zone().Upper(clock))
+	assert.Equal(t, zones.NewRelation(-5, zones.Weak), traces[0][0].Zone().Lower(clock))
+}
+
+func Test_Timelocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		guard     int
+		reset     bool
+		timelocks int
+	}{
+		{
+			name:      "Zeno loop",
+			guard:     0,
+			reset:     false,
+			timelocks: 1,
+		},
+		{
+			name:      "Loop without reset",
+			guard:     1,
+			reset:     false,
+			timelocks: 1,
+		},
+		{
+			name:      "Non-Zeno loop",
+			guard:     1,
+			reset:     true,
+			timelocks: 0,
+		},
+		{
+			name:      "Non-Zeno loop without a lower bound",
+			guard:     0,
+			reset:     true,
+			timelocks: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			context := z3.NewContext(z3.NewConfig())
+			symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+			reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+			interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+			clocks := language.NewClocksMap(reference)
+			clocks.Declare(x)
+			builder := NewAutomatonBuilder()
+			loop := builder.AddInitial("loop", WithInvariant(
+				NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(2, zones.Weak))),
+			))
+			update := NewEmptyUpdate()
+			if tt.reset {
+				update = NewUpdate(language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)))
+			}
+			builder.AddLoop(loop,
+				WithGuard(NewGuard(
+					language.NewClockConstraint(reference, x, zones.NewRelation(-tt.guard, zones.Weak)),
+				)),
+				WithUpdate(update),
+			)
+			automaton := builder.Build()
+			system := NewTimedTransitionSystem(&automaton, clocks, interpreter)
+
+			// Act
+			timelocks := system.Timelocks(language.NewValuationsMap())
+			deadlocks := system.Deadlocks(language.NewValuationsMap())
+
+			// Assert
+			assert.Len(t, timelocks, tt.timelocks)
+			for _, trace := range timelocks {
+				assert.Equal(t, clocks.Dimensions(), trace[len(trace)-1].Zone().Clocks())
+			}
+			assert.Empty(t, deadlocks)
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package automata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+type ChannelKind uint8
+
+const (
+	// An output synchronises with exactly one input of another process.
+	Handshake = ChannelKind(iota)
+	// An output synchronises with the inputs of all other processes which can receive it.
+	Broadcast
+)
+
+// A network of processes running in parallel. The processes communicate by synchronising on channels and
+// share the global variables which are the variables of the interpreter that more processes refer to.
+// Actions which are not channels are internal to their process and interleave with all other edges.
+type Network struct {
+	names     []string
+	processes []*TIOAutomaton
+	templates []*Template
+	channels  map[Action]ChannelKind
+}
+
+func NewNetwork() *Network {
+	return &Network{
+		names:     make([]string, 0),
+		processes: make([]*TIOAutomaton, 0),
+		templates: make([]*Template, 0),
+		channels:  map[Action]ChannelKind{},
+	}
+}
+
+// Adds the automaton as a process of the network and returns its index. An error is returned
+// if the process receives a broadcast channel on an edge whose guard has clock constraints.
+func (network *Network) AddProcess(name string, automaton *TIOAutomaton) (int, error) {
+	for action, kind := range network.channels {
+		if kind == Broadcast {
+			if err := checkBroadcast(name, automaton, action); err != nil {
+				return -1, err
+			}
+		}
+	}
+	network.names = append(network.names, name)
+	network.processes = append(network.processes, automaton)
+	network.templates = append(network.templates, nil)
+	return len(network.processes) - 1, nil
+}
+
+// Instantiates the template with the arguments and adds the instance as a process named by the instance.
+func (network *Network) AddInstance(
+	template *Template, store symbols.Store[any], variables language.Variables, arguments ...language.Expression,
+) (int, error) {
+	automaton := template.Instantiate(store, variables, arguments...)
+	process, err := network.AddProcess(template.InstanceName(store, arguments...), automaton)
+	if err != nil {
+		return -1, err
+	}
+	network.templates[process] = template
+	return process, nil
+}
+
+// Declares the action as a channel of the kind. An error is returned if the channel is a broadcast
+// channel and a process receives it on an edge whose guard has clock constraints.
+func (network *Network) AddChannel(action Action, kind ChannelKind) error {
+	if kind == Broadcast {
+		for idx, process := range network.processes {
+			if err := checkBroadcast(network.names[idx], process, action); err != nil {
+				return err
+			}
+		}
+	}
+	network.channels[action] = kind
+	return nil
+}
+
+// The negation of a clock constraint is not a zone such that broadcast inputs may not have clock constraints.
+func checkBroadcast(name string, automaton *TIOAutomaton, action Action) (err error) {
+	if !automaton.IsInput(action) {
+		return nil
+	}
+	automaton.Edges(func(edge IOEdge) bool {
+		if edge.action == action && language.HasClockConstraints(edge.guard.condition) {
+			err = fmt.Errorf("the broadcast input of the process \"%s\" has clock constraints in its guard", name)
+			return false
+		}
+		return true
+	})
+	return err
+}
+
+func (network *Network) Processes() []*TIOAutomaton {
+	return network.processes
+}
+
+func (network *Network) Process(index int) (name string, automaton *TIOAutomaton) {
+	return network.names[index], network.processes[index]
+}
+
+func (network *Network) Channel(action Action) (kind ChannelKind, exists bool) {
+	kind, exists = network.channels[action]
+	return kind, exists
+}
+
+// Returns the clocks of all processes.
+func (network *Network) Clocks() language.Clocks {
+	if len(network.processes) == 0 {
+		panic("Network has no processes")
+	}
+	clocks := network.processes[0].clocks
+	for _, process := range network.processes[1:] {
+		clocks = unionClocks(clocks, process.clocks)
+	}
+	return clocks
+}
+
+// Returns the product of the processes where each location is a vector of the locations of the processes.
+func (network *Network) Automaton() *NetworkAutomaton {
+	return newNetworkProduct(network).explore()
+}
+
+// Returns a transition system exploring the product of the processes by zones.
+func (network *Network) TransitionSystem(interpreter *Interpreter) *SymbolicTransitionSystem {
+	return network.Automaton().TransitionSystem(interpreter)
+}
+
+// The product of a network. Its locations are vectors with a location of each process.
+type NetworkAutomaton struct {
+	network     *Network
+	automaton   SymbolicAutomaton
+	clocks      language.Clocks
+	keys        map[string]symbols.Symbol
+	vectors     map[symbols.Symbol][]symbols.Symbol
+	transitions map[symbols.Symbol][]networkTransition
+}
+
+// An edge of the product and the processes which move by it.
+type networkTransition struct {
+	edge      Edge
+	processes []int
+}
+
+func (automaton *NetworkAutomaton) Symbolic() *SymbolicAutomaton {
+	return &automaton.automaton
+}
+
+// Returns the clocks of all processes. The clocks are numbered once such that zones of the product agree on them.
+func (automaton *NetworkAutomaton) Clocks() language.Clocks {
+	return automaton.clocks
+}
+
+func (automaton *NetworkAutomaton) TransitionSystem(interpreter *Interpreter) *SymbolicTransitionSystem {
+	return NewTimedTransitionSystem(&automaton.automaton, automaton.clocks, interpreter)
+}
+
+// Returns the key of the product location of the vector if it is reachable.
+func (automaton *NetworkAutomaton) Location(vector []symbols.Symbol) (location symbols.Symbol, exists bool) {
+	location, exists = automaton.keys[fmt.Sprint(vector)]
+	return location, exists
+}
+
+// Returns the locations of the processes in the product location.
+func (automaton *NetworkAutomaton) Components(location symbols.Symbol) (vector []symbols.Symbol, exists bool) {
+	vector, exists = automaton.vectors[location]
+	return vector, exists
+}
+
+// A synchronisation of some processes of the network. It moves each participating process along its edge
+// when the guard is satisfied together with the guards of the edges.
+type networkMove struct {
+	processes []int
+	edges     []Edge
+	guard     Guard
+}
+
+func newNetworkMove() networkMove {
+	return networkMove{guard: NewTrueGuard()}
+}
+
+func (move networkMove) with(process int, edge Edge) networkMove {
+	return networkMove{
+		processes: append(append([]int{}, move.processes...), process),
+		edges:     append(append([]Edge{}, move.edges...), edge),
+		guard:     move.guard,
+	}
+}
+
+func (move networkMove) constrain(guard Guard) networkMove {
+	move.guard = move.guard.Conjunction(guard)
+	return move
+}
+
+// Incrementally constructs the reachable location vectors of a network.
+type networkProduct struct {
+	network     *Network
+	builder     *SymbolicAutomatonBuilder
+	keys        map[string]symbols.Symbol
+	vectors     map[symbols.Symbol][]symbols.Symbol
+	transitions map[symbols.Symbol][]networkTransition
+	waiting     [][]symbols.Symbol
+}
+
+func newNetworkProduct(network *Network) *networkProduct {
+	return &networkProduct{
+		network:     network,
+		builder:     NewAutomatonBuilder(),
+		keys:        map[string]symbols.Symbol{},
+		vectors:     map[symbols.Symbol][]symbols.Symbol{},
+		transitions: map[symbols.Symbol][]networkTransition{},
+		waiting:     make([][]symbols.Symbol, 0),
+	}
+}
+
+// Returns the key of the product location for the vector. If the vector has not
+// been seen before then it is added to the builder and is later explored.
+func (product *networkProduct) location(vector []symbols.Symbol) symbols.Symbol {
+	identifier := fmt.Sprint(vector)
+	if key, exists := product.keys[identifier]; exists {
+		return key
+	}
+
+	names := make([]string, len(vector))
+	invariant := NewTrueInvariant()
+	urgency := Delayable
+	for process, component := range vector {
+		location, _ := product.network.processes[process].Location(component)
+		names[process] = location.name
+		invariant = invariant.Conjunction(location.invariant)
+		urgency = max(urgency, location.urgency)
+	}
+
+	name := fmt.Sprintf("(%s)", strings.Join(names, ", "))
+	key := product.builder.AddLocation(name, WithInvariant(invariant), WithUrgency(urgency))
+	product.keys[identifier] = key
+	product.vectors[key] = vector
+	product.waiting = append(product.waiting, vector)
+	return key
+}
+
+func (product *networkProduct) explore() *NetworkAutomaton {
+	initial := make([]symbols.Symbol, len(product.network.processes))
+	for process, automaton := range product.network.processes {
+		initial[process] = automaton.initial
+	}
+	product.builder.initial = product.location(initial)
+
+	for len(product.waiting) > 0 {
+		vector := product.waiting[0]
+		product.waiting = product.waiting[1:]
+		source := product.keys[fmt.Sprint(vector)]
+		for _, move := range product.moves(vector) {
+			destination := append([]symbols.Symbol{}, vector...)
+			guard, update := move.guard, NewEmptyUpdate()
+			for idx, process := range move.processes {
+				destination[process] = move.edges[idx].destination
+				guard = guard.Conjunction(move.edges[idx].guard)
+				update = update.Conjunction(move.edges[idx].update)
+			}
+			key := product.location(destination)
+			product.builder.AddEdge(source, key, WithGuard(guard), WithUpdate(update))
+			product.transitions[source] = append(product.transitions[source], networkTransition{
+				edge:      NewEdge(source, guard, update, key),
+				processes: move.processes,
+			})
+		}
+	}
+
+	return &NetworkAutomaton{
+		network:     product.network,
+		automaton:   product.builder.Build(),
+		clocks:      product.network.Clocks(),
+		keys:        product.keys,
+		vectors:     product.vectors,
+		transitions: product.transitions,
+	}
+}
+
+// Returns the moves from the vector. If a process is in a committed location
+// then only the moves where a process leaves a committed location are possible.
+func (product *networkProduct) moves(vector []symbols.Symbol) []networkMove {
+	moves := make([]networkMove, 0)
+	for sender, automaton := range product.network.processes {
+		for _, edge := range automaton.Automaton.Outgoing(vector[sender]) {
+			kind, isChannel := product.network.channels[edge.action]
+			switch {
+			case !isChannel:
+				moves = append(moves, newNetworkMove().with(sender, edge.Edge))
+			case !automaton.IsOutput(edge.action):
+				// Inputs only move together with an output.
+			case kind == Handshake:
+				moves = append(moves, product.handshakes(vector, sender, edge)...)
+			case kind == Broadcast:
+				moves = append(moves, product.broadcasts(vector, sender, edge)...)
+			}
+		}
+	}
+
+	committed := func(process int) bool {
+		location, _ := product.network.processes[process].Location(vector[process])
+		return location.urgency == Committed
+	}
+	isCommitted := false
+	for process := range vector {
+		isCommitted = isCommitted || committed(process)
+	}
+	if !isCommitted {
+		return moves
+	}
+
+	filtered := make([]networkMove, 0, len(moves))
+	for _, move := range moves {
+		for _, process := range move.processes {
+			if committed(process) {
+				filtered = append(filtered, move)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// Returns the moves where the output synchronises with an input of one other process.
+func (product *networkProduct) handshakes(vector []symbols.Symbol, sender int, output IOEdge) (moves []networkMove) {
+	for receiver, automaton := range product.network.processes {
+		if receiver == sender || !automaton.IsInput(output.action) {
+			continue
+		}
+		for _, input := range automaton.Outgoing(vector[receiver], output.action) {
+			moves = append(moves, newNetworkMove().with(sender, output.Edge).with(receiver, input.Edge))
+		}
+	}
+	return moves
+}
+
+// Returns the moves where the output synchronises with an input of all other processes which can receive it.
+// A process which cannot receive it stays in its location which is when none of the guards of its inputs are
+// satisfied. The inputs have no clock constraints as they are rejected when the network is built.
+func (product *networkProduct) broadcasts(vector []symbols.Symbol, sender int, output IOEdge) []networkMove {
+	moves := []networkMove{newNetworkMove().with(sender, output.Edge)}
+	for receiver, automaton := range product.network.processes {
+		if receiver == sender || !automaton.IsInput(output.action) {
+			continue
+		}
+		inputs := automaton.Outgoing(vector[receiver], output.action)
+		if len(inputs) == 0 {
+			continue
+		}
+
+		guards := make([]Guard, len(inputs))
+		for idx, input := range inputs {
+			guards[idx] = input.guard
+		}
+		// The receiver does not participate when none of its inputs can be traversed.
+		skip := NewFalseGuard().Disjunction(guards...).Negation()
+
+		extended := make([]networkMove, 0, len(moves)*(len(inputs)+1))
+		for _, move := range moves {
+			for _, input := range inputs {
+				extended = append(extended, move.with(receiver, input.Edge))
+			}
+			extended = append(extended, move.constrain(skip))
+		}
+		moves = extended
+	}
+	return moves
+}
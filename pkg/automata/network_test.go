@@ -0,0 +1,179 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NetworkHandshake(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	request := Action(symbolsMap.Insert("request"))
+	work := Action(symbolsMap.Insert("work"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	client := NewIOAutomatonBuilder()
+	client.AddOutputs(request)
+	idle := client.AddInitial("idle")
+	waiting := client.AddLocation("waiting")
+	client.AddEdge(idle, request, waiting)
+
+	server := NewIOAutomatonBuilder()
+	server.AddInputs(request)
+	server.AddOutputs(work)
+	ready := server.AddInitial("ready")
+	busy := server.AddLocation("busy")
+	server.AddEdge(ready, request, busy)
+	server.AddLoop(ready, work)
+
+	network := NewNetwork()
+	network.AddProcess("client", NewTIOAutomaton(client.Build(), language.NewClocksMap(reference)))
+	network.AddProcess("server", NewTIOAutomaton(server.Build(), language.NewClocksMap(reference)))
+	network.AddChannel(request, Handshake)
+	product := network.Automaton()
+	system := NewTimedTransitionSystem(product.Symbolic(), network.Clocks(), interpreter)
+
+	// Act
+	initial := system.Initial(language.NewValuationsMap())
+	successors := system.Outgoing(initial)
+
+	// Assert
+	assert.Len(t, successors, 2)
+	vectors := [][]symbols.Symbol{}
+	for _, successor := range successors {
+		vector, exists := product.Components(successor.Location())
+		assert.True(t, exists)
+		vectors = append(vectors, vector)
+	}
+	assert.ElementsMatch(t, [][]symbols.Symbol{{waiting, busy}, {idle, ready}}, vectors)
+}
+
+func Test_NetworkBroadcast(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, i := symbolsMap.Insert("0"), symbolsMap.Insert("i")
+	alarm := Action(symbolsMap.Insert("alarm"))
+	variables := language.NewVariablesMap()
+	variables.Declare(i, language.IntegerSort)
+	interpreter := NewInterpreter(context, variables)
+
+	sender := NewIOAutomatonBuilder()
+	sender.AddOutputs(alarm)
+	quiet := sender.AddInitial("quiet")
+	ringing := sender.AddLocation("ringing")
+	sender.AddEdge(quiet, alarm, ringing)
+
+	// The listener only receives the broadcast when "i > 0".
+	listener := NewIOAutomatonBuilder()
+	listener.AddInputs(alarm)
+	sleeping := listener.AddInitial("sleeping")
+	awake := listener.AddLocation("awake")
+	listener.AddEdge(sleeping, alarm, awake, WithGuard(NewGuard(
+		language.NewBinary(language.NewVariable(i), language.GreaterThan, language.NewInteger(0)),
+	)))
+
+	// The deaf process cannot receive the broadcast and does not block it.
+	deaf := NewIOAutomatonBuilder()
+	deaf.AddInputs(alarm)
+	deaf.AddInitial("deaf")
+
+	network := NewNetwork()
+	network.AddProcess("sender", NewTIOAutomaton(sender.Build(), language.NewClocksMap(reference)))
+	network.AddProcess("listener", NewTIOAutomaton(listener.Build(), language.NewClocksMap(reference)))
+	network.AddProcess("deaf", NewTIOAutomaton(deaf.Build(), language.NewClocksMap(reference)))
+	network.AddChannel(alarm, Broadcast)
+	product := network.Automaton()
+	system := NewTimedTransitionSystem(product.Symbolic(), network.Clocks(), interpreter)
+
+	valuations := language.NewValuationsMap()
+	valuations.Assign(i, language.NewInteger(0))
+
+	// Act
+	initial := system.Initial(valuations)
+	successors := system.Outgoing(initial)
+
+	// Assert
+	assert.Len(t, successors, 1)
+	vector, _ := product.Components(successors[0].Location())
+	assert.Equal(t, ringing, vector[0])
+	assert.Equal(t, sleeping, vector[1])
+}
+
+func Test_NetworkBroadcastClockGuard(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	alarm := Action(symbolsMap.Insert("alarm"))
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+
+	listener := NewIOAutomatonBuilder()
+	listener.AddInputs(alarm)
+	sleeping := listener.AddInitial("sleeping")
+	listener.AddLoop(sleeping, alarm, WithGuard(NewGuard(
+		language.NewClockConstraint(reference, x, zones.NewRelation(-1, zones.Weak)),
+	)))
+	automaton := NewTIOAutomaton(listener.Build(), clocks)
+
+	// Act
+	before := NewNetwork()
+	_, processErr := before.AddProcess("listener", automaton)
+	channelErr := before.AddChannel(alarm, Broadcast)
+	after := NewNetwork()
+	after.AddChannel(alarm, Broadcast)
+	_, lateErr := after.AddProcess("listener", automaton)
+
+	// Assert
+	assert.NoError(t, processErr)
+	assert.EqualError(t, channelErr, "the broadcast input of the process \"listener\" has clock constraints in its guard")
+	assert.EqualError(t, lateErr, "the broadcast input of the process \"listener\" has clock constraints in its guard")
+	assert.Len(t, after.Processes(), 0)
+}
+
+func Test_NetworkCommitted(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	step := Action(symbolsMap.Insert("step"))
+	tick := Action(symbolsMap.Insert("tick"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	atomic := NewIOAutomatonBuilder()
+	atomic.AddOutputs(step)
+	start := atomic.AddInitial("start", WithUrgency(Committed))
+	end := atomic.AddLocation("end")
+	atomic.AddEdge(start, step, end)
+
+	clocks := language.NewClocksMap(reference)
+	clock := clocks.Declare(x)
+	ticker := NewIOAutomatonBuilder()
+	ticker.AddOutputs(tick)
+	ticking := ticker.AddInitial("ticking")
+	ticker.AddLoop(ticking, tick)
+
+	network := NewNetwork()
+	network.AddProcess("atomic", NewTIOAutomaton(atomic.Build(), language.NewClocksMap(reference)))
+	network.AddProcess("ticker", NewTIOAutomaton(ticker.Build(), clocks))
+	product := network.Automaton()
+	system := NewTimedTransitionSystem(product.Symbolic(), network.Clocks(), interpreter)
+
+	// Act
+	initial := system.Initial(language.NewValuationsMap())
+	successors := system.Outgoing(initial)
+
+	// Assert
+	assert.Equal(t, zones.NewRelation(0, zones.Weak), initial.Zone().Upper(clock))
+	assert.Len(t, successors, 1)
+	vector, _ := product.Components(successors[0].Location())
+	assert.Equal(t, []symbols.Symbol{end, ticking}, vector)
+	assert.True(t, successors[0].Zone().CanDelayIndefinitely())
+}
@@ -0,0 +1,140 @@
+package automata
+
+import (
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// Reduces the interleavings of the internal edges of independent processes of a network by ample sets. The ample
+// set of a state is the enabled edges of a single process if all edges from its location are internal, invisible
+// and independent of all edges of the other processes. Edges are independent if neither writes a variable which
+// the other reads or writes. An edge must also commute with the passing of time which is the case when it neither
+// constrains nor updates clocks and its locations are delayable with invariants without clock constraints.
+//
+// Reachability is preserved for properties which only observe the locations of the observed processes and the
+// values of the observed variables. An edge is visible if it moves an observed process or writes an observed variable.
+type PartialOrderReduction struct {
+	automaton *NetworkAutomaton
+	system    *SymbolicTransitionSystem
+	observed  map[int]bool
+	variables map[symbols.Symbol]bool
+	// The accesses of all guards, updates and invariants of each process.
+	accesses []language.Accesses
+	// Whether the edges of a process from a location can be an ample set.
+	candidates []map[symbols.Symbol]bool
+}
+
+func NewPartialOrderReduction(automaton *NetworkAutomaton, system *SymbolicTransitionSystem) *PartialOrderReduction {
+	processes := automaton.network.processes
+	reduction := &PartialOrderReduction{
+		automaton:  automaton,
+		system:     system,
+		observed:   map[int]bool{},
+		variables:  map[symbols.Symbol]bool{},
+		accesses:   make([]language.Accesses, len(processes)),
+		candidates: make([]map[symbols.Symbol]bool, len(processes)),
+	}
+	for idx, process := range processes {
+		expressions := []language.Expression{}
+		process.Locations(func(_ symbols.Symbol, location Location) bool {
+			expressions = append(expressions, location.invariant.condition)
+			return true
+		})
+		process.Edges(func(edge IOEdge) bool {
+			expressions = append(expressions, edge.guard.condition, edge.update.expression)
+			return true
+		})
+		reduction.accesses[idx] = language.NewAccesses(expressions...)
+		reduction.candidates[idx] = map[symbols.Symbol]bool{}
+	}
+	return reduction
+}
+
+// Makes the locations of the process visible such that all its edges are visible.
+func (reduction *PartialOrderReduction) ObserveProcess(process int) {
+	reduction.observed[process] = true
+	clear(reduction.candidates[process])
+}
+
+// Makes the variable visible such that all edges writing it are visible.
+func (reduction *PartialOrderReduction) ObserveVariable(symbol symbols.Symbol) {
+	reduction.variables[symbol] = true
+	for idx := range reduction.candidates {
+		clear(reduction.candidates[idx])
+	}
+}
+
+// Returns the successors of the state by the first ample set. If one of its successors has been visited then all
+// successors are returned as the edges of the other processes could otherwise be ignored forever on a cycle.
+func (reduction *PartialOrderReduction) Outgoing(state State, visited func(state State) bool) []State {
+	vector, exists := reduction.automaton.Components(state.location)
+	if !exists {
+		panic("State is in an unkown location")
+	}
+	location, _ := reduction.system.automaton.Location(state.location)
+	if !location.IsEnabled(state.valuations, reduction.system.interpreter) {
+		return nil
+	}
+
+	for process := range vector {
+		if !reduction.isCandidate(process, vector[process]) {
+			continue
+		}
+		ample := []State{}
+		for _, transition := range reduction.automaton.transitions[state.location] {
+			if len(transition.processes) != 1 || transition.processes[0] != process {
+				continue
+			}
+			if successor, enabled := reduction.system.Successor(state, transition.edge); enabled {
+				ample = append(ample, successor)
+			}
+		}
+		if len(ample) == 0 {
+			continue
+		}
+		for _, successor := range ample {
+			if visited(successor) {
+				return reduction.system.Outgoing(state)
+			}
+		}
+		return ample
+	}
+	return reduction.system.Outgoing(state)
+}
+
+// Returns true if the edges of the process from the location are internal, invisible and independent.
+func (reduction *PartialOrderReduction) isCandidate(process int, location symbols.Symbol) bool {
+	if candidate, exists := reduction.candidates[process][location]; exists {
+		return candidate
+	}
+	candidate := !reduction.observed[process]
+	automaton := reduction.automaton.network.processes[process]
+	for _, edge := range automaton.Automaton.Outgoing(location) {
+		if !candidate {
+			break
+		}
+		if _, isChannel := reduction.automaton.network.channels[edge.action]; isChannel {
+			candidate = false
+			break
+		}
+
+		source, _ := automaton.Location(edge.source)
+		destination, _ := automaton.Location(edge.destination)
+		accesses := language.NewAccesses(
+			edge.guard.condition, edge.update.expression,
+			source.invariant.condition, destination.invariant.condition,
+		)
+		candidate = !source.IsUrgent() && !destination.IsUrgent() &&
+			accesses.All(func(symbol symbols.Symbol) bool {
+				_, isClock := reduction.automaton.clocks.Lookup(symbol)
+				return !isClock && !(reduction.variables[symbol] && accesses.Writes(symbol))
+			})
+		for other := range reduction.accesses {
+			if other != process && accesses.Conflicts(reduction.accesses[other]) {
+				candidate = false
+			}
+		}
+	}
+	reduction.candidates[process][location] = candidate
+	return candidate
+}
@@ -0,0 +1,100 @@
+package automata
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PartialOrderReduction(t *testing.T) {
+	tests := []struct {
+		name    string
+		shared  bool
+		observe bool
+		reduced bool
+	}{
+		{
+			name:    "Independent processes",
+			reduced: true,
+		},
+		{
+			name:    "Shared variable",
+			shared:  true,
+			reduced: false,
+		},
+		{
+			name:    "Observed process",
+			observe: true,
+			reduced: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			context := z3.NewContext(z3.NewConfig())
+			symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+			reference := symbolsMap.Insert("0")
+			step := Action(symbolsMap.Insert("step"))
+			variables := language.NewVariablesMap()
+
+			// Each process takes three internal steps and writes either its own or a shared variable.
+			network := NewNetwork()
+			ends := make([]symbols.Symbol, 2)
+			for process := range ends {
+				variable := symbolsMap.Insert("shared")
+				if !tt.shared {
+					variable = symbolsMap.Insert(fmt.Sprintf("v%d", process))
+				}
+				variables.Declare(variable, language.IntegerSort)
+
+				builder := NewIOAutomatonBuilder()
+				builder.AddOutputs(step)
+				source := builder.AddInitial("0")
+				for idx := 1; idx <= 3; idx++ {
+					destination := builder.AddLocation(fmt.Sprint(idx))
+					builder.AddEdge(source, step, destination, WithUpdate(NewUpdate(language.NewBlockExpression(
+						language.NewTrue(), language.NewAssignment(language.NewVariable(variable), language.NewInteger(idx)),
+					))))
+					source = destination
+				}
+				ends[process] = source
+				network.AddProcess("P", NewTIOAutomaton(builder.Build(), language.NewClocksMap(reference)))
+			}
+			product := network.Automaton()
+			interpreter := NewInterpreter(context, variables)
+			system := product.TransitionSystem(interpreter)
+			reduction := NewPartialOrderReduction(product, system)
+			if tt.observe {
+				reduction.ObserveProcess(0)
+			}
+			initial := system.Initial(language.NewValuationsMap())
+			explore := func(search SearchStrategy) (explored int, found bool) {
+				search.For(func(state State) bool {
+					explored++
+					vector, _ := product.Components(state.Location())
+					found = found || (vector[0] == ends[0] && vector[1] == ends[1])
+					return false
+				}, initial)
+				return explored, found
+			}
+
+			// Act
+			plain, plainFound := explore(NewBreadthFirstSearch(system, interpreter))
+			reduced, reducedFound := explore(NewBreadthFirstSearch(system, interpreter).WithReduction(reduction))
+
+			// Assert
+			assert.Equal(t, 16, plain)
+			assert.True(t, plainFound)
+			assert.True(t, reducedFound)
+			if tt.reduced {
+				assert.Less(t, reduced, plain)
+			} else {
+				assert.Equal(t, plain, reduced)
+			}
+		})
+	}
+}
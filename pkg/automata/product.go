@@ -0,0 +1,85 @@
+package automata
+
+import (
+	"fmt"
+
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// A location of a product automaton which is a pair of the component locations.
+type locationPair struct {
+	lhs, rhs symbols.Symbol
+}
+
+// Incrementally constructs the locations of a product of two automata. Only the
+// pairs of locations which are structurally reachable from the initial pair are added.
+type product struct {
+	builder  *IOAutomatonBuilder
+	lhs, rhs IOAutomaton
+	keys     map[locationPair]symbols.Symbol
+	waiting  []locationPair
+	// If consistent is set then pairs with an inconsistent invariant are replaced by the error location.
+	consistent func(invariant Invariant) bool
+	// If invariant is set then it replaces the conjunction of the component invariants.
+	invariant func(lhs, rhs Location) Invariant
+	err       *symbols.Symbol
+}
+
+func newProduct(builder *IOAutomatonBuilder, lhs, rhs IOAutomaton) *product {
+	return &product{
+		builder: builder,
+		lhs:     lhs,
+		rhs:     rhs,
+		keys:    map[locationPair]symbols.Symbol{},
+		waiting: make([]locationPair, 0),
+	}
+}
+
+// Returns the key of the product location for the pair. If the pair has not
+// been seen before then it is added to the builder and is later explored.
+func (product *product) Location(pair locationPair) symbols.Symbol {
+	if key, exists := product.keys[pair]; exists {
+		return key
+	}
+
+	lhs, _ := product.lhs.Location(pair.lhs)
+	rhs, _ := product.rhs.Location(pair.rhs)
+	invariant := lhs.invariant.Conjunction(rhs.invariant)
+	if product.invariant != nil {
+		invariant = product.invariant(lhs, rhs)
+	}
+	if product.consistent != nil && !product.consistent(invariant) {
+		key := product.Error()
+		product.keys[pair] = key
+		return key
+	}
+
+	name := fmt.Sprintf("(%s, %s)", lhs.name, rhs.name)
+	key := product.builder.AddLocation(name, WithInvariant(invariant))
+	product.keys[pair] = key
+	product.waiting = append(product.waiting, pair)
+	return key
+}
+
+// Returns the key of the error location which has a false invariant.
+// The location is only added to the product when it is first used.
+func (product *product) Error() symbols.Symbol {
+	if product.err == nil {
+		key := product.builder.AddLocation("Error", WithInvariant(NewFalseInvariant()))
+		product.err = &key
+	}
+	return *product.err
+}
+
+// Starts from the initial pair and calls explore with each product location until there are
+// no more unexplored pairs. It is expected that explore adds the edges by calling Location.
+func (product *product) Explore(explore func(source symbols.Symbol, pair locationPair)) {
+	initial := locationPair{product.lhs.initial, product.rhs.initial}
+	product.builder.initial = product.Location(initial)
+
+	for len(product.waiting) > 0 {
+		pair := product.waiting[0]
+		product.waiting = product.waiting[1:]
+		explore(product.keys[pair], pair)
+	}
+}
@@ -0,0 +1,265 @@
+package query
+
+import (
+	"github.com/Brandhoej/gobion/pkg/algorithms"
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// The verdict of a query and the path showing it.
+type Result struct {
+	satisfied bool
+	lasso     automata.Lasso
+}
+
+func (result Result) IsSatisfied() bool {
+	return result.satisfied
+}
+
+// Returns the witness of a satisfied E<> or E[] query or the counterexample of an unsatisfied A[], A<> or
+// leads to query. It is the prefix followed by the cycle of the lasso. If there is no such path it is empty.
+func (result Result) Trace() automata.Trace {
+	return result.lasso.Trace()
+}
+
+// Returns the path of the result as a lasso. The cycle is only non-empty for E[], A<> and leads to queries
+// where it is repeated forever. A cycle of a single state without an edge to itself is a path ending in it.
+func (result Result) Lasso() automata.Lasso {
+	return result.lasso
+}
+
+// Checks queries on the states reachable from the initial state of the system.
+type Checker struct {
+	system *automata.SymbolicTransitionSystem
+	search automata.SearchStrategy
+	zones  language.ZoneInterpreter
+}
+
+func NewChecker(system *automata.SymbolicTransitionSystem, search automata.SearchStrategy) *Checker {
+	return &Checker{
+		system: system,
+		search: search,
+		zones:  language.NewZoneInterpreter(system.Clocks()),
+	}
+}
+
+// Checks the query from the initial state of the valuations. An error is returned if the data of the
+// system could not be interpreted, such as a while loop which is not bounded, as the result is then unreliable.
+func (checker *Checker) Check(query Query, valuations language.Valuations) (Result, error) {
+	result := checker.check(query, valuations)
+	if err := checker.system.Interpreter().Err(); err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+func (checker *Checker) check(query Query, valuations language.Valuations) Result {
+	initial := checker.system.Initial(valuations)
+	switch query.quantifier {
+	case PossiblyEventually:
+		trace := checker.reach(query, query.formula, initial)
+		return Result{satisfied: trace != nil, lasso: automata.NewLasso(trace, nil)}
+	case InvariantlyAlways:
+		trace := checker.reach(query, language.LogicalNegate(query.formula), initial)
+		return Result{satisfied: trace == nil, lasso: automata.NewLasso(trace, nil)}
+	case PossiblyAlways:
+		lasso, found := checker.always(query, query.formula, initial)
+		return Result{satisfied: found, lasso: lasso}
+	case InevitablyEventually:
+		lasso, found := checker.always(query, language.LogicalNegate(query.formula), initial)
+		return Result{satisfied: !found, lasso: lasso}
+	case LeadsTo:
+		lasso, found := checker.leadsTo(query, initial)
+		return Result{satisfied: !found, lasso: lasso}
+	}
+	panic("Unknown quantifier")
+}
+
+// Returns the trace to a reachable state where some valuation satisfies the formula.
+func (checker *Checker) reach(query Query, formula language.Expression, root automata.State) automata.Trace {
+	return checker.search.For(func(state automata.State) bool {
+		return len(checker.restrict(query, formula, state)) > 0
+	}, root)
+}
+
+// Returns a path to a reachable state satisfying the premise followed by a maximal path never satisfying the formula.
+func (checker *Checker) leadsTo(query Query, root automata.State) (lasso automata.Lasso, found bool) {
+	prefix := checker.search.For(func(state automata.State) bool {
+		for _, premise := range checker.restrict(query, query.premise, state) {
+			if lasso, found = checker.always(query, language.LogicalNegate(query.formula), premise); found {
+				return true
+			}
+		}
+		return false
+	}, root)
+	if !found {
+		return lasso, false
+	}
+	prefix = append(prefix[:len(prefix)-1], lasso.Prefix()...)
+	return automata.NewLasso(prefix, lasso.Cycle()), true
+}
+
+// A state of a path and the part of it satisfying the formula of the path.
+type pathState struct {
+	state, restricted automata.State
+}
+
+// Returns a maximal path from the state where all states satisfy the formula. The path is either infinite, where
+// it is a lasso found by a nested depth-first search, or ends in a state where time can pass forever or where no
+// edge can be traversed. States are
+// only the same if they include each other since a state included in another is not necessarily on its cycles.
+func (checker *Checker) always(query Query, formula language.Expression, root automata.State) (automata.Lasso, bool) {
+	interpreter := checker.system.Interpreter()
+	equal := func(lhs, rhs pathState) bool {
+		return lhs.restricted.SubsetOf(rhs.restricted, interpreter) &&
+			rhs.restricted.SubsetOf(lhs.restricted, interpreter)
+	}
+	successors := func(node pathState) (nodes []pathState) {
+		outgoing := checker.system.Outgoing(node.restricted)
+		if checker.isMaximal(node.state, node.restricted, len(outgoing) == 0) {
+			// The path ends in the state which is represented by the state being its own successor.
+			nodes = append(nodes, node)
+		}
+		for _, successor := range outgoing {
+			for _, restricted := range checker.restrict(query, formula, successor) {
+				nodes = append(nodes, pathState{successor, restricted})
+			}
+		}
+		return nodes
+	}
+	accepting := func(pathState) bool {
+		return true
+	}
+
+	roots := []pathState{}
+	for _, restricted := range checker.restrict(query, formula, root) {
+		roots = append(roots, pathState{root, restricted})
+	}
+	prefix, cycle, found := algorithms.NestedDFS(successors, accepting, equal, roots...)
+	restricted := func(nodes []pathState) (trace automata.Trace) {
+		for _, node := range nodes {
+			trace = append(trace, node.restricted)
+		}
+		return trace
+	}
+	return automata.NewLasso(restricted(prefix), restricted(cycle)), found
+}
+
+// Returns true if a path can end in the restricted state. This is the case if time can pass forever
+// or, if the state has no edges, if some valuation is at a non-strict upper bound of the unrestricted state.
+func (checker *Checker) isMaximal(state, restricted automata.State, deadlocked bool) bool {
+	location, _ := checker.system.Automaton().Location(state.Location())
+	if !location.IsUrgent() && restricted.Zone().CanDelayIndefinitely() {
+		return true
+	}
+	if !deadlocked {
+		return false
+	}
+	for clock := zones.Clock(1); clock < state.Zone().Clocks(); clock++ {
+		upper := state.Zone().Upper(clock)
+		if upper.IsInfinity() || upper.Strictness() == zones.Strict {
+			continue
+		}
+		zone := restricted.Zone().Copy()
+		zone.ConstrainAndClose(zones.Reference, clock, zones.NewRelation(-upper.Limit(), zones.Weak))
+		if zone.IsConsistent() {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the states whose zones are the valuations of the state satisfying the formula.
+// There is a state for each conjunction of the disjunctive normal form of the formula.
+func (checker *Checker) restrict(query Query, formula language.Expression, state automata.State) (states []automata.State) {
+	for _, conjunction := range checker.disjuncts(query, formula, state, true) {
+		zone := state.Zone().Copy()
+		data := make([]language.Expression, 0, len(conjunction))
+		for _, literal := range conjunction {
+			if language.HasClockConstraints(literal) {
+				checker.zones.Constrain(zone, literal)
+			} else {
+				data = append(data, literal)
+			}
+		}
+		if !zone.IsConsistent() {
+			continue
+		}
+		if len(data) > 0 && !checker.system.Interpreter().IsSatisfied(
+			state.Valuations(), language.Conjunction(data[0], data[1:]...),
+		) {
+			continue
+		}
+		states = append(states, automata.NewState(state.Location(), state.Valuations(), state.Constraint(), zone))
+	}
+	return states
+}
+
+// Returns the disjunctive normal form of the formula where the negations have been pushed down to the clock
+// constraints and data expressions, and the propositions have been replaced by their truth value in the state.
+func (checker *Checker) disjuncts(
+	query Query, formula language.Expression, state automata.State, polarity bool,
+) [][]language.Expression {
+	switch cast := any(formula).(type) {
+	case language.Boolean:
+		if cast.Value() == polarity {
+			return [][]language.Expression{{}}
+		}
+		return nil
+	case language.Variable:
+		if proposition, exists := query.Proposition(cast.Symbol()); exists {
+			holds := checker.holds(proposition, state)
+			return checker.disjuncts(query, language.NewBoolean(holds), state, polarity)
+		}
+	case language.Unary:
+		if cast.Operator() == language.LogicalNegation {
+			return checker.disjuncts(query, cast.Operand(), state, !polarity)
+		}
+	case language.Binary:
+		lhs, rhs := cast.LHS(), cast.RHS()
+		switch cast.Operator() {
+		case language.LogicalAnd:
+			if polarity {
+				return product(checker.disjuncts(query, lhs, state, true), checker.disjuncts(query, rhs, state, true))
+			}
+			return append(checker.disjuncts(query, lhs, state, false), checker.disjuncts(query, rhs, state, false)...)
+		case language.LogicalOr:
+			if polarity {
+				return append(checker.disjuncts(query, lhs, state, true), checker.disjuncts(query, rhs, state, true)...)
+			}
+			return product(checker.disjuncts(query, lhs, state, false), checker.disjuncts(query, rhs, state, false))
+		case language.Implication:
+			// P → Q ≡ ¬P ∨ Q
+			if polarity {
+				return append(checker.disjuncts(query, lhs, state, false), checker.disjuncts(query, rhs, state, true)...)
+			}
+			return product(checker.disjuncts(query, lhs, state, true), checker.disjuncts(query, rhs, state, false))
+		}
+	}
+
+	if polarity {
+		return [][]language.Expression{{formula}}
+	}
+	return [][]language.Expression{{language.LogicalNegate(formula)}}
+}
+
+// Returns true if the proposition holds in the state. A state is deadlocked if no edges can be traversed from it.
+func (checker *Checker) holds(proposition Proposition, state automata.State) bool {
+	if proposition.IsDeadlock() {
+		return len(checker.system.Outgoing(state)) == 0
+	}
+	location, _ := proposition.Location()
+	return state.Location() == location
+}
+
+// Returns the conjunctions of all pairs of conjunctions.
+func product(lhs, rhs [][]language.Expression) (conjunctions [][]language.Expression) {
+	for _, left := range lhs {
+		for _, right := range rhs {
+			conjunction := append(append([]language.Expression{}, left...), right...)
+			conjunctions = append(conjunctions, conjunction)
+		}
+	}
+	return conjunctions
+}
@@ -0,0 +1,269 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/dsl"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+// A worker which must start working within 5 time units and finishes within 2 time units.
+// The error location is unreachable as the worker cannot be busy for more than 2 time units.
+const worker = `
+clock x;
+initial location Idle invariant x <= 5;
+location Busy invariant x <= 2;
+location Error;
+location Done;
+edge Idle -> Busy when x >= 1 do x := 0;
+edge Busy -> Idle do x := 0;
+edge Busy -> Error when x > 2;
+edge Busy -> Done when x == 2;
+`
+
+func system(t *testing.T, text string) (*automata.SymbolicTransitionSystem, symbols.Store[any]) {
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	model, err := dsl.Parse(text, symbolsMap)
+	assert.NoError(t, err)
+	interpreter := automata.NewInterpreter(z3.NewContext(z3.NewConfig()), model.Variables())
+	automaton := model.Automaton()
+	return automata.NewTimedTransitionSystem(&automaton, model.Clocks(), interpreter), symbolsMap
+}
+
+func Test_Check(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		satisfied bool
+		trace     []string
+	}{
+		{
+			name:      "Reachable location",
+			query:     "E<> loc == Busy",
+			satisfied: true,
+			trace:     []string{"Idle", "Busy"},
+		},
+		{
+			name:      "Unreachable location",
+			query:     "E<> Error",
+			satisfied: false,
+		},
+		{
+			name:      "Unreachable clock valuation",
+			query:     "E<> Busy and x > 2",
+			satisfied: false,
+		},
+		{
+			name:      "Invariantly bounded clock",
+			query:     "A[] Idle imply x <= 5",
+			satisfied: true,
+		},
+		{
+			name:      "Violated clock bound",
+			query:     "A[] x < 5",
+			satisfied: false,
+			trace:     []string{"Idle"},
+		},
+		{
+			name:      "Deadlock",
+			query:     "A[] not deadlock",
+			satisfied: false,
+			trace:     []string{"Idle", "Busy", "Done"},
+		},
+		{
+			name:      "Possibly always",
+			query:     "E[] not Done",
+			satisfied: true,
+			trace:     []string{"Idle", "Busy"},
+		},
+		{
+			name:      "Inevitable location",
+			query:     "A<> Busy",
+			satisfied: true,
+		},
+		{
+			name:      "Avoidable location",
+			query:     "A<> Done",
+			satisfied: false,
+			trace:     []string{"Idle", "Busy"},
+		},
+		{
+			name:      "Divergent time",
+			query:     "Done --> x > 5",
+			satisfied: true,
+		},
+		{
+			name:      "Leads to",
+			query:     "Idle --> Busy",
+			satisfied: true,
+		},
+		{
+			name:      "Not leads to",
+			query:     "Busy --> Done",
+			satisfied: false,
+			trace:     []string{"Idle", "Busy", "Idle"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			system, symbolsMap := system(t, worker)
+			checker := NewChecker(system, automata.NewBreadthFirstSearch(system, system.Interpreter()))
+			query, err := Parse(tt.query, symbolsMap, system)
+			assert.NoError(t, err)
+
+			// Act
+			result, err := checker.Check(query, language.NewValuationsMap())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.satisfied, result.IsSatisfied())
+			locations := make([]string, len(result.Trace()))
+			for idx, state := range result.Trace() {
+				location, _ := system.Automaton().Location(state.Location())
+				locations[idx] = location.Name()
+			}
+			if tt.trace == nil {
+				assert.Empty(t, locations)
+			} else {
+				assert.Equal(t, tt.trace, locations)
+			}
+		})
+	}
+}
+
+func Test_CheckDepthFirst(t *testing.T) {
+	// Arrange
+	system, symbolsMap := system(t, worker)
+	checker := NewChecker(system, automata.NewDepthFirstSearch(system, system.Interpreter()))
+	query, _ := Parse("E<> Done", symbolsMap, system)
+
+	// Act
+	result, err := checker.Check(query, language.NewValuationsMap())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, result.IsSatisfied())
+	assert.Len(t, result.Trace(), 3)
+}
+
+func Test_CheckLasso(t *testing.T) {
+	tests := []struct {
+		name   string
+		model  string
+		query  string
+		prefix []string
+		cycle  []string
+	}{
+		{
+			name:   "Cycle",
+			model:  worker,
+			query:  "Busy --> Done",
+			prefix: []string{"Idle"},
+			cycle:  []string{"Busy", "Idle"},
+		},
+		{
+			name:   "Time divergence",
+			model:  strings.Replace(worker, "edge Busy -> Idle do x := 0;", "", 1),
+			query:  "A<> Error",
+			prefix: []string{"Idle", "Busy"},
+			cycle:  []string{"Done"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			system, symbolsMap := system(t, tt.model)
+			checker := NewChecker(system, automata.NewBreadthFirstSearch(system, system.Interpreter()))
+			query, _ := Parse(tt.query, symbolsMap, system)
+			names := func(trace automata.Trace) (locations []string) {
+				for _, state := range trace {
+					location, _ := system.Automaton().Location(state.Location())
+					locations = append(locations, location.Name())
+				}
+				return locations
+			}
+
+			// Act
+			result, err := checker.Check(query, language.NewValuationsMap())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.False(t, result.IsSatisfied())
+			assert.Equal(t, tt.prefix, names(result.Lasso().Prefix()))
+			assert.Equal(t, tt.cycle, names(result.Lasso().Cycle()))
+		})
+	}
+}
+
+func Test_CheckDivergence(t *testing.T) {
+	// Time can pass forever in Init such that Goal is not inevitable.
+	const model = `
+initial location Init;
+location Goal;
+edge Init -> Goal;
+edge Goal -> Goal;
+`
+	tests := []struct {
+		name      string
+		query     string
+		satisfied bool
+	}{
+		{
+			name:      "Inevitably eventually",
+			query:     "A<> Goal",
+			satisfied: false,
+		},
+		{
+			name:      "Leads to",
+			query:     "Init --> Goal",
+			satisfied: false,
+		},
+		{
+			name:      "Possibly always",
+			query:     "E[] not Goal",
+			satisfied: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			system, symbolsMap := system(t, model)
+			checker := NewChecker(system, automata.NewBreadthFirstSearch(system, system.Interpreter()))
+			query, err := Parse(tt.query, symbolsMap, system)
+			assert.NoError(t, err)
+
+			// Act
+			result, err := checker.Check(query, language.NewValuationsMap())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.satisfied, result.IsSatisfied())
+		})
+	}
+}
+
+func Test_CheckUnboundedLoop(t *testing.T) {
+	// Arrange
+	const model = `
+int n;
+initial location Idle;
+location Done;
+edge Idle -> Done do while n > 0 do { n := n - 1 };
+edge Done -> Idle;
+`
+	system, symbolsMap := system(t, model)
+	checker := NewChecker(system, automata.NewBreadthFirstSearch(system, system.Interpreter()))
+	query, _ := Parse("A[] not deadlock", symbolsMap, system)
+
+	// Act
+	_, err := checker.Check(query, language.NewValuationsMap())
+
+	// Assert
+	assert.EqualError(t, err, "the while loop is not bounded by 32 unrollings")
+}
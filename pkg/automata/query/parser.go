@@ -0,0 +1,102 @@
+package query
+
+import (
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// Parses the text as a query of the system in the UPPAAL syntax:
+//
+//	query := ("E<>" | "A[]" | "E[]" | "A<>") formula | formula "-->" formula
+//
+// The state formulae are expressions over the variables and clocks of the system with the propositions
+// "deadlock", the names of the locations and "loc == name" which holds in the location with the name.
+// For example, "E<> loc == Error", "A[] not deadlock", "A<> x > 5" and "Request --> Grant".
+func Parse(text string, store symbols.Store[any], system *automata.SymbolicTransitionSystem) (Query, error) {
+	parser, err := language.NewParser(text, store, system.Clocks())
+	if err != nil {
+		return Query{}, err
+	}
+
+	locations := map[string]symbols.Symbol{}
+	system.Automaton().Locations(func(key symbols.Symbol, location automata.Location) bool {
+		locations[location.Name()] = key
+		return true
+	})
+	propositions := map[symbols.Symbol]Proposition{}
+	propose := func(proposition Proposition) symbols.Symbol {
+		symbol := store.Insert(proposition)
+		propositions[symbol] = proposition
+		return symbol
+	}
+	location := func(token language.Token) symbols.Symbol {
+		key, exists := locations[token.Text]
+		if !exists {
+			parser.Fail(token, "undeclared location \"%s\"", token.Text)
+		}
+		return propose(NewLocationProposition(key, token.Text))
+	}
+	parser.SetResolver(func(token language.Token) symbols.Symbol {
+		switch token.Text {
+		case "deadlock":
+			return propose(NewDeadlockProposition())
+		case "loc":
+			parser.Expect("==")
+			name := parser.Next()
+			if name.Kind != language.IdentifierToken && name.Kind != language.NameToken {
+				parser.Fail(name, "expected a location but found %s", name)
+			}
+			return location(name)
+		}
+
+		// Variables and clocks shadow the locations with the same name.
+		if symbol, exists := store.Lookup(token.Text); exists {
+			if _, isVariable := system.Interpreter().Variables().Lookup(symbol); isVariable {
+				return symbol
+			}
+			if _, isClock := system.Clocks().Lookup(symbol); isClock {
+				return symbol
+			}
+		}
+		if _, exists := locations[token.Text]; exists {
+			return location(token)
+		}
+		parser.Fail(token, "undeclared identifier \"%s\"", token.Text)
+		return 0
+	})
+
+	var query Query
+	err = parser.Parse(func(parser *language.Parser) {
+		if quantifier, ok := parseQuantifier(parser); ok {
+			query = NewQuery(quantifier, parser.Expression(), propositions)
+			return
+		}
+		premise := parser.Expression()
+		parser.Expect("-->")
+		query = NewLeadsTo(premise, parser.Expression(), propositions)
+	})
+	return query, err
+}
+
+// Accepts the path quantifier and temporal operator if they are next.
+func parseQuantifier(parser *language.Parser) (Quantifier, bool) {
+	quantifiers := map[[3]string]Quantifier{
+		{"E", "<", ">"}: PossiblyEventually,
+		{"A", "[", "]"}: InvariantlyAlways,
+		{"E", "[", "]"}: PossiblyAlways,
+		{"A", "<", ">"}: InevitablyEventually,
+	}
+	var texts [3]string
+	for idx := range texts {
+		texts[idx] = parser.Lookahead(idx).Text
+	}
+	quantifier, exists := quantifiers[texts]
+	if !exists || parser.Peek().Kind != language.IdentifierToken {
+		return quantifier, false
+	}
+	for range texts {
+		parser.Next()
+	}
+	return quantifier, true
+}
@@ -0,0 +1,122 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		quantifier Quantifier
+		expected   string
+	}{
+		{
+			name:       "Possibly eventually",
+			text:       "E<> loc == Error",
+			quantifier: PossiblyEventually,
+			expected:   "E<> Error",
+		},
+		{
+			name:       "Invariantly always",
+			text:       "A[] not deadlock",
+			quantifier: InvariantlyAlways,
+			expected:   "A[] ¬(deadlock)",
+		},
+		{
+			name:       "Possibly always",
+			text:       "E[] Idle or Busy and x <= 2",
+			quantifier: PossiblyAlways,
+			expected:   "E[] Idle ∨ Busy ∧ x - 0 ≤ 2",
+		},
+		{
+			name:       "Inevitably eventually",
+			text:       "A<> x > 5",
+			quantifier: InevitablyEventually,
+			expected:   "A<> 0 - x < -5",
+		},
+		{
+			name:       "Leads to",
+			text:       "Busy imply x >= 1 --> Idle",
+			quantifier: LeadsTo,
+			expected:   "Busy → 0 - x ≤ -1 --> Idle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			system, symbolsMap := system(t, worker)
+
+			// Act
+			query, err := Parse(tt.text, symbolsMap, system)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.quantifier, query.Quantifier())
+			assert.Equal(t, tt.expected, query.String(symbolsMap))
+		})
+	}
+}
+
+func Test_ParseProposition(t *testing.T) {
+	// Arrange
+	system, symbolsMap := system(t, worker)
+
+	// Act
+	query, err := Parse("E<> loc == Busy", symbolsMap, system)
+
+	// Assert
+	assert.NoError(t, err)
+	variable, isVariable := query.Formula().(language.Variable)
+	assert.True(t, isVariable)
+	proposition, exists := query.Proposition(variable.Symbol())
+	assert.True(t, exists)
+	key, isLocation := proposition.Location()
+	assert.True(t, isLocation)
+	location, _ := system.Automaton().Location(key)
+	assert.Equal(t, "Busy", location.Name())
+}
+
+func Test_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "Undeclared identifier",
+			text:     "E<> y > 2",
+			expected: "1:5: undeclared identifier \"y\"",
+		},
+		{
+			name:     "Undeclared location",
+			text:     "E<> loc == Waiting",
+			expected: "1:12: undeclared location \"Waiting\"",
+		},
+		{
+			name:     "Missing leads to",
+			text:     "Idle Busy",
+			expected: "1:6: expected \"-->\" but found \"Busy\"",
+		},
+		{
+			name:     "Missing formula",
+			text:     "A[]",
+			expected: "1:4: expected an expression but found end of input",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			system, symbolsMap := system(t, worker)
+
+			// Act
+			_, err := Parse(tt.text, symbolsMap, system)
+
+			// Assert
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
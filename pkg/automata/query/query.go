@@ -0,0 +1,142 @@
+package query
+
+import (
+	"bytes"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+type Quantifier uint8
+
+const (
+	// E<> φ: Some reachable state satisfies φ.
+	PossiblyEventually = Quantifier(iota)
+	// A[] φ: All reachable states satisfy φ.
+	InvariantlyAlways
+	// E[] φ: All states of some maximal path satisfy φ.
+	PossiblyAlways
+	// A<> φ: All maximal paths reach a state satisfying φ.
+	InevitablyEventually
+	// φ --> ψ: All maximal paths from a state satisfying φ reach a state satisfying ψ.
+	LeadsTo
+)
+
+func (quantifier Quantifier) String() string {
+	switch quantifier {
+	case PossiblyEventually:
+		return "E<>"
+	case InvariantlyAlways:
+		return "A[]"
+	case PossiblyAlways:
+		return "E[]"
+	case InevitablyEventually:
+		return "A<>"
+	case LeadsTo:
+		return "-->"
+	}
+	panic("Unknown quantifier")
+}
+
+// An atomic proposition about the states which is not an expression over the variables and clocks.
+// It is either the test of whether the state is in a location or whether the state is deadlocked.
+type Proposition struct {
+	location symbols.Symbol
+	name     string
+	deadlock bool
+}
+
+func NewLocationProposition(location symbols.Symbol, name string) Proposition {
+	return Proposition{
+		location: location,
+		name:     name,
+	}
+}
+
+func NewDeadlockProposition() Proposition {
+	return Proposition{
+		deadlock: true,
+	}
+}
+
+// Returns the key of the location if the proposition is a location test.
+func (proposition Proposition) Location() (symbols.Symbol, bool) {
+	return proposition.location, !proposition.deadlock
+}
+
+func (proposition Proposition) IsDeadlock() bool {
+	return proposition.deadlock
+}
+
+func (proposition Proposition) String() string {
+	if proposition.deadlock {
+		return "deadlock"
+	}
+	return proposition.name
+}
+
+// A TCTL query. Its state formulae are expressions over the variables and clocks where
+// the variables of the propositions are true in the states where the propositions hold.
+type Query struct {
+	quantifier   Quantifier
+	premise      language.Expression
+	formula      language.Expression
+	propositions map[symbols.Symbol]Proposition
+}
+
+func NewQuery(
+	quantifier Quantifier, formula language.Expression, propositions map[symbols.Symbol]Proposition,
+) Query {
+	if quantifier == LeadsTo {
+		panic("Leads to queries must have a premise")
+	}
+	return Query{
+		quantifier:   quantifier,
+		premise:      language.NewTrue(),
+		formula:      formula,
+		propositions: propositions,
+	}
+}
+
+func NewLeadsTo(
+	premise, formula language.Expression, propositions map[symbols.Symbol]Proposition,
+) Query {
+	return Query{
+		quantifier:   LeadsTo,
+		premise:      premise,
+		formula:      formula,
+		propositions: propositions,
+	}
+}
+
+func (query Query) Quantifier() Quantifier {
+	return query.quantifier
+}
+
+// Returns the state formula which must hold before the formula for leads to queries and otherwise true.
+func (query Query) Premise() language.Expression {
+	return query.premise
+}
+
+func (query Query) Formula() language.Expression {
+	return query.formula
+}
+
+func (query Query) Proposition(symbol symbols.Symbol) (Proposition, bool) {
+	proposition, exists := query.propositions[symbol]
+	return proposition, exists
+}
+
+func (query Query) String(symbols symbols.Store[any]) string {
+	var buffer bytes.Buffer
+	printer := language.NewPrettyPrinter(&buffer, symbols)
+	if query.quantifier == LeadsTo {
+		query.premise.Accept(printer)
+		buffer.WriteString(" --> ")
+	} else {
+		buffer.WriteString(query.quantifier.String())
+		buffer.WriteString(" ")
+	}
+	query.formula.Accept(printer)
+	return buffer.String()
+}
@@ -0,0 +1,154 @@
+package automata
+
+import (
+	"errors"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// Returns true if the quotient "lhs \ rhs" is defined. That is, the actions of the rhs
+// are actions of the lhs and the outputs of the rhs are outputs of the lhs.
+func (lhs IOAutomaton) IsQuotientable(rhs IOAutomaton) bool {
+	for _, input := range rhs.inputs {
+		if !lhs.InAlphabet(input) {
+			return false
+		}
+	}
+	for _, output := range rhs.outputs {
+		if !lhs.IsOutput(output) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the quotient "lhs \ rhs" which is the weakest specification Q such that "Q ∥ rhs"
+// refines the lhs. The inputs of the quotient are the inputs of the lhs and the outputs of
+// the rhs. The outputs of the quotient are the outputs of the lhs which are not outputs of
+// the rhs. The clock and violation action must be fresh, the clock is used to prevent delays in the
+// inconsistent location and the violation is an input used to observe violated invariants.
+//
+// Besides the pairs of locations the quotient has two additional locations:
+//   - A universal location which allows all actions. It is entered when the rhs cannot
+//     perform an output or when the rhs invariant is violated.
+//   - An inconsistent location where time cannot pass. It is entered when the rhs
+//     performs an output which the lhs does not allow or when only the lhs invariant is violated.
+func (lhs TIOAutomaton) Quotient(
+	rhs TIOAutomaton, clock symbols.Symbol, violation Action, interpreter *Interpreter,
+) (*TIOAutomaton, error) {
+	if !lhs.IsQuotientable(rhs.IOAutomaton) {
+		return nil, errors.New("the actions and outputs of the rhs of the quotient must be actions and outputs of the lhs")
+	}
+
+	clocks := unionClocks(lhs.clocks, rhs.clocks)
+	clocks.Declare(clock)
+	reference := referenceClock(clocks)
+
+	builder := NewIOAutomatonBuilder()
+	builder.AddInputs(violation)
+	for _, input := range lhs.inputs {
+		builder.AddInputs(input)
+	}
+	for _, output := range rhs.outputs {
+		builder.AddInputs(output)
+	}
+	for _, output := range lhs.outputs {
+		if !rhs.IsOutput(output) {
+			builder.AddOutputs(output)
+		}
+	}
+
+	// The universal location allows everything and time can pass indefinitely.
+	universal := builder.AddLocation("Universal")
+	// The inconsistent location only accepts inputs and cannot delay.
+	inconsistent := builder.AddLocation("Inconsistent", WithInvariant(
+		NewInvariant(
+			language.NewClockConstraint(clock, reference, zones.NewRelation(0, zones.Weak)),
+		),
+	))
+	reset := NewUpdate(
+		language.NewBlockExpression(language.NewTrue(), language.NewClockReset(clock, 0)),
+	)
+
+	// Only edges with a satisfiable guard are added to the quotient.
+	addEdge := func(source symbols.Symbol, action Action, destination symbols.Symbol, guard Guard, update Update) {
+		if guard.IsSatisfiable(interpreter) {
+			builder.AddEdge(source, action, destination, WithGuard(guard), WithUpdate(update))
+		}
+	}
+
+	product := newProduct(builder, lhs.IOAutomaton, rhs.IOAutomaton)
+	product.invariant = func(lhs, rhs Location) Invariant {
+		return NewTrueInvariant()
+	}
+	product.Explore(func(source symbols.Symbol, pair locationPair) {
+		lhsLocation, _ := lhs.Location(pair.lhs)
+		rhsLocation, _ := rhs.Location(pair.rhs)
+		lhsInvariant := NewGuard(lhsLocation.invariant.condition)
+		rhsInvariant := NewGuard(rhsLocation.invariant.condition)
+
+		// The rhs invariant is violated so the pair cannot be reached in the composition.
+		addEdge(source, violation, universal, rhsInvariant.Negation(), NewEmptyUpdate())
+		// The lhs invariant is violated whilst the rhs allows it.
+		addEdge(source, violation, inconsistent, lhsInvariant.Negation().Conjunction(rhsInvariant), reset)
+
+		for _, action := range lhs.Actions() {
+			lhsEdges := lhs.Outgoing(pair.lhs, action)
+
+			// Only the lhs moves on actions which are not in the rhs alphabet.
+			if !rhs.InAlphabet(action) {
+				for _, edge := range lhsEdges {
+					destination := product.Location(locationPair{edge.destination, pair.rhs})
+					addEdge(source, action, destination, edge.guard, edge.update)
+				}
+				continue
+			}
+
+			// Both move on shared actions.
+			rhsEdges := rhs.Outgoing(pair.rhs, action)
+			for _, lhsEdge := range lhsEdges {
+				for _, rhsEdge := range rhsEdges {
+					destination := product.Location(locationPair{lhsEdge.destination, rhsEdge.destination})
+					addEdge(
+						source, action, destination,
+						lhsEdge.guard.Conjunction(rhsEdge.guard),
+						lhsEdge.update.Conjunction(rhsEdge.update),
+					)
+				}
+			}
+
+			if !rhs.IsOutput(action) {
+				continue
+			}
+
+			// The rhs cannot perform the output so anything is allowed.
+			rhsGuards := make([]Guard, len(rhsEdges))
+			for idx := range rhsEdges {
+				rhsGuards[idx] = rhsEdges[idx].guard
+			}
+			addEdge(source, action, universal, missingGuard(rhsGuards...), NewEmptyUpdate())
+
+			// The rhs performs an output which the lhs does not allow.
+			lhsGuards := make([]Guard, len(lhsEdges))
+			for idx := range lhsEdges {
+				lhsGuards[idx] = lhsEdges[idx].guard
+			}
+			lhsMissing := missingGuard(lhsGuards...)
+			for _, rhsEdge := range rhsEdges {
+				addEdge(source, action, inconsistent, rhsEdge.guard.Conjunction(lhsMissing), reset)
+			}
+		}
+	})
+
+	for _, input := range builder.inputs {
+		builder.AddLoop(inconsistent, input)
+		builder.AddLoop(universal, input)
+	}
+	for _, output := range builder.outputs {
+		builder.AddLoop(universal, output)
+	}
+
+	return NewTIOAutomaton(builder.Build(), clocks), nil
+}
@@ -0,0 +1,97 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Quotient(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	x, fresh := symbolsMap.Insert("x"), symbolsMap.Insert("fresh")
+	coin := Action(symbolsMap.Insert("coin"))
+	coffee := Action(symbolsMap.Insert("coffee"))
+	publication := Action(symbolsMap.Insert("publication"))
+	violation := Action(symbolsMap.Insert("violation"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+	specification := NewIOAutomatonBuilder()
+	specification.AddInputs(coin)
+	specification.AddOutputs(coffee, publication)
+	idle := specification.AddInitial("idle", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(5, zones.Weak))),
+	))
+	brewing := specification.AddLocation("brewing")
+	specification.AddEdge(idle, coin, brewing)
+	specification.AddEdge(brewing, coffee, idle)
+	specification.AddLoop(idle, publication)
+
+	machine := NewIOAutomatonBuilder()
+	machine.AddInputs(coin)
+	machine.AddOutputs(coffee)
+	waiting := machine.AddInitial("waiting")
+	machine.AddLoop(waiting, coin)
+	machine.AddLoop(waiting, coffee)
+
+	// Act
+	quotient, err := NewTIOAutomaton(specification.Build(), clocks).Quotient(
+		*NewTIOAutomaton(machine.Build(), language.NewClocksMap(reference)),
+		fresh, violation, interpreter,
+	)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []Action{violation, coin, coffee}, quotient.Inputs())
+	assert.ElementsMatch(t, []Action{publication}, quotient.Outputs())
+	assert.Equal(t, zones.Clock(3), quotient.Clocks().Dimensions())
+
+	destinations := func(action Action) (names []string) {
+		for _, edge := range quotient.Outgoing(quotient.Initial(), action) {
+			location, _ := quotient.Location(edge.Destination())
+			names = append(names, location.name)
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"(brewing, waiting)"}, destinations(coin))
+	assert.ElementsMatch(t, []string{"Inconsistent"}, destinations(coffee))
+	assert.ElementsMatch(t, []string{"(idle, waiting)"}, destinations(publication))
+	assert.ElementsMatch(t, []string{"Inconsistent"}, destinations(violation))
+}
+
+func Test_QuotientNotQuotientable(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	coin := Action(symbolsMap.Insert("coin"))
+	coffee := Action(symbolsMap.Insert("coffee"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	specification := NewIOAutomatonBuilder()
+	specification.AddInputs(coin)
+	specification.AddInitial("idle")
+
+	machine := NewIOAutomatonBuilder()
+	machine.AddInputs(coin)
+	machine.AddOutputs(coffee)
+	machine.AddInitial("waiting")
+
+	// Act
+	quotient, err := NewTIOAutomaton(specification.Build(), language.NewClocksMap(reference)).Quotient(
+		*NewTIOAutomaton(machine.Build(), language.NewClocksMap(reference)),
+		symbolsMap.Insert("fresh"), Action(symbolsMap.Insert("violation")), interpreter,
+	)
+
+	// Assert
+	assert.Nil(t, quotient)
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package automata
+
+import (
+	"fmt"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// An assignment of an update which can assign a value outside the range of the bounded integer sort of a variable.
+type RangeError struct {
+	trace    Trace
+	edge     Edge
+	variable symbols.Symbol
+	sort     language.Sort
+}
+
+// Returns the trace to the state from which the edge can be traversed.
+func (err RangeError) Trace() Trace {
+	return err.trace
+}
+
+func (err RangeError) Edge() Edge {
+	return err.edge
+}
+
+func (err RangeError) Variable() symbols.Symbol {
+	return err.variable
+}
+
+func (err RangeError) Error() string {
+	return fmt.Sprintf("the update can assign a value outside of %s to the variable %d", err.sort, err.variable)
+}
+
+// Returns an error for each variable which an update of an edge from a reachable state can assign a value outside
+// of its range. The assignments are checked on each path through the update where the guard of the edge holds,
+// including the assignments in the bodies of conditionals and loops.
+func (system *SymbolicTransitionSystem) RangeErrors(valuations language.Valuations) (errors []RangeError) {
+	graph := system.explore(valuations)
+	for index, state := range graph.states {
+		location, _ := system.automaton.Location(state.location)
+		if !location.IsEnabled(state.valuations, system.interpreter) {
+			continue
+		}
+		for _, edge := range system.automaton.Outgoing(state.location) {
+			if _, enabled := system.enabled(state, edge); !enabled {
+				continue
+			}
+			// The update is executed in a branch of the guard such that the guard constrains the paths.
+			interpreter := system.interpreter.symbolic(state.valuations.Copy())
+			interpreter.SetRangeChecks(true)
+			interpreter.Statement(language.NewIf(
+				edge.guard.condition, language.Statements(edge.update.expression), nil,
+			))
+			system.interpreter.collect(interpreter)
+			for _, violation := range interpreter.RangeViolations() {
+				errors = append(errors, RangeError{
+					trace:    graph.trace(index),
+					edge:     edge,
+					variable: violation.Variable(),
+					sort:     violation.Sort(),
+				})
+			}
+		}
+	}
+	return errors
+}
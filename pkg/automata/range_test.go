@@ -0,0 +1,158 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RangeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		guard  language.Expression
+		errors int
+	}{
+		{
+			name:   "Unguarded increment",
+			guard:  language.NewTrue(),
+			errors: 1,
+		},
+		{
+			name:   "Guarded increment",
+			errors: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			context := z3.NewContext(z3.NewConfig())
+			symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+			i := symbolsMap.Insert("i")
+			variables := language.NewVariablesMap()
+			variables.Declare(i, language.NewBoundedIntegerSort(0, 3))
+			interpreter := NewInterpreter(context, variables)
+
+			guard := tt.guard
+			if guard == nil {
+				guard = language.NewBinary(language.NewVariable(i), language.LessThan, language.NewInteger(3))
+			}
+			builder := NewAutomatonBuilder()
+			counting := builder.AddInitial("counting")
+			builder.AddLoop(counting,
+				WithGuard(NewGuard(guard)),
+				WithUpdate(NewUpdate(language.NewBlockExpression(
+					language.NewTrue(),
+					language.NewAssignment(
+						language.NewVariable(i),
+						language.NewBinary(language.NewVariable(i), language.Addition, language.NewInteger(1)),
+					),
+				))),
+			)
+			automaton := builder.Build()
+			system := NewTransitionSystem(&automaton, interpreter)
+			valuations := language.NewValuationsMap()
+			valuations.Assign(i, language.NewInteger(3))
+
+			// Act
+			errors := system.RangeErrors(valuations)
+
+			// Assert
+			assert.Len(t, errors, tt.errors)
+			for _, err := range errors {
+				assert.Equal(t, i, err.Variable())
+				assert.Equal(t, counting, err.Trace().Last().Location())
+				assert.EqualError(t, err, "the update can assign a value outside of int[0,3] to the variable 0")
+			}
+		})
+	}
+}
+
+func Test_RangeErrorsNested(t *testing.T) {
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x, i, y := symbolsMap.Insert("x"), symbolsMap.Insert("i"), symbolsMap.Insert("y")
+	increment := language.NewAssignment(
+		language.NewVariable(x),
+		language.NewBinary(language.NewVariable(x), language.Addition, language.NewInteger(10)),
+	)
+
+	tests := []struct {
+		name      string
+		statement language.Statement
+		errors    int
+	}{
+		{
+			name:      "x := x + 10",
+			statement: increment,
+			errors:    1,
+		},
+		{
+			name:      "if true then { x := x + 10 }",
+			statement: language.NewIf(language.NewTrue(), []language.Statement{increment}, nil),
+			errors:    1,
+		},
+		{
+			name:      "if false then { x := x + 10 }",
+			statement: language.NewIf(language.NewFalse(), []language.Statement{increment}, nil),
+			errors:    0,
+		},
+		{
+			name: "while x < 2 do { x := x + 10 }",
+			statement: language.NewWhile(
+				language.NewBinary(language.NewVariable(x), language.LessThan, language.NewInteger(2)),
+				increment,
+			),
+			errors: 1,
+		},
+		{
+			name:      "for i := 0 to 5 do { x := i }",
+			statement: language.NewFor(i, 0, 5, language.NewAssignment(language.NewVariable(x), language.NewVariable(i))),
+			errors:    1,
+		},
+		{
+			name:      "for i := 0 to 3 do { x := i }",
+			statement: language.NewFor(i, 0, 3, language.NewAssignment(language.NewVariable(x), language.NewVariable(i))),
+			errors:    0,
+		},
+		{
+			name: "if true then { int y := 5; x := y }",
+			statement: language.NewIf(language.NewTrue(), []language.Statement{
+				language.NewDeclaration(y, language.IntegerSort, language.NewInteger(5)),
+				language.NewAssignment(language.NewVariable(x), language.NewVariable(y)),
+			}, nil),
+			errors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			context := z3.NewContext(z3.NewConfig())
+			variables := language.NewVariablesMap()
+			variables.Declare(x, language.NewBoundedIntegerSort(0, 3))
+			interpreter := NewInterpreter(context, variables)
+
+			builder := NewAutomatonBuilder()
+			initial := builder.AddInitial("initial")
+			final := builder.AddLocation("final")
+			builder.AddEdge(initial, final, WithUpdate(NewUpdate(
+				language.NewBlockExpression(language.NewTrue(), tt.statement),
+			)))
+			automaton := builder.Build()
+			system := NewTransitionSystem(&automaton, interpreter)
+			valuations := language.NewValuationsMap()
+			valuations.Assign(x, language.NewInteger(0))
+
+			// Act
+			errors := system.RangeErrors(valuations)
+
+			// Assert
+			assert.Len(t, errors, tt.errors)
+			for _, err := range errors {
+				assert.Equal(t, x, err.Variable())
+				assert.Equal(t, initial, err.Trace().Last().Location())
+			}
+		})
+	}
+}
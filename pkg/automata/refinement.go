@@ -0,0 +1,256 @@
+package automata
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/structures"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// A pair of implementation and specification states which share a zone over the clocks of both.
+type refinementState struct {
+	implementation, specification State
+	zone                          zones.DBM
+}
+
+type refinement struct {
+	implementation, specification TIOAutomaton
+	interpreter                   *Interpreter
+	clocks                        language.Clocks
+	zones                         language.ZoneInterpreter
+	passed                        map[locationPair][]refinementState
+}
+
+// Returns true if the implementation refines the specification by a timed alternating simulation.
+// Inputs of the specification must be accepted by the implementation whilst outputs and delays of
+// the implementation must be allowed by the specification. If the refinement does not hold then
+// a trace of the implementation states leading to the violating state is returned.
+//
+// The clocks are explored symbolically by zones over the clocks of both automata whilst the data is
+// explored by the interpreter. Both automata start with the same valuations. An error is returned if
+// the automata do not have the same inputs and outputs or if their clock constraints are not convex.
+func (implementation TIOAutomaton) Refines(
+	specification TIOAutomaton, interpreter *Interpreter, valuations language.Valuations,
+) (refines bool, counterexample Trace, err error) {
+	if !implementation.HasSameAlphabet(specification.IOAutomaton) {
+		return false, nil, errors.New("the automata of the refinement do not have the same inputs and outputs")
+	}
+	for _, automaton := range []TIOAutomaton{implementation, specification} {
+		if err := automaton.Symbolic().convex(); err != nil {
+			return false, nil, err
+		}
+	}
+
+	clocks := unionClocks(implementation.clocks, specification.clocks)
+	refinement := refinement{
+		implementation: implementation,
+		specification:  specification,
+		interpreter:    interpreter,
+		clocks:         clocks,
+		zones:          language.NewZoneInterpreter(clocks),
+		passed:         map[locationPair][]refinementState{},
+	}
+
+	initial, valid := refinement.initial(valuations)
+	if !valid {
+		return false, Trace{initial.implementation}, nil
+	}
+	if !initial.zone.IsConsistent() {
+		// The implementation has no initial state and therefore no behaviour.
+		return true, nil, nil
+	}
+	refinement.visit(initial)
+
+	stack := structures.Stack[structures.LinkedNode[refinementState]]{}
+	stack.Push(structures.NewLinkedNode(nil, initial))
+	for !stack.IsEmpty() {
+		node := stack.Pop()
+		successors, valid := refinement.successors(node.Data)
+		if !valid {
+			return false, refinement.trace(node), nil
+		}
+
+		for _, successor := range successors {
+			if refinement.visited(successor) {
+				continue
+			}
+			refinement.visit(successor)
+			stack.Push(structures.NewLinkedNode(&node, successor))
+		}
+	}
+
+	return true, nil, nil
+}
+
+// Returns the implementation states from the initial state to the node.
+func (refinement *refinement) trace(node structures.LinkedNode[refinementState]) Trace {
+	pairs := node.Array()
+	trace := make(Trace, len(pairs))
+	for idx := range pairs {
+		// The states of the trace carry the shared zone of the pair.
+		trace[idx] = pairs[idx].implementation
+		trace[idx].zone = pairs[idx].zone
+	}
+	slices.Reverse(trace)
+	return trace
+}
+
+// Returns true if the pair is included in a passed pair by both its zone and the states of both automata.
+func (refinement *refinement) visited(pair refinementState) bool {
+	key := locationPair{pair.implementation.location, pair.specification.location}
+	for _, passed := range refinement.passed[key] {
+		if subset, _ := pair.zone.Relation(passed.zone, zones.Reference, refinement.clocks.Dimensions()); !subset {
+			continue
+		}
+		if pair.implementation.SubsetOf(passed.implementation, refinement.interpreter) &&
+			pair.specification.SubsetOf(passed.specification, refinement.interpreter) {
+			return true
+		}
+	}
+	return false
+}
+
+func (refinement *refinement) visit(pair refinementState) {
+	key := locationPair{pair.implementation.location, pair.specification.location}
+	refinement.passed[key] = append(refinement.passed[key], pair)
+}
+
+func (refinement *refinement) initial(valuations language.Valuations) (refinementState, bool) {
+	implementation := refinement.initialState(refinement.implementation, valuations.Copy())
+	specification := refinement.initialState(refinement.specification, valuations.Copy())
+	zone := zones.NewDBM(refinement.clocks.Dimensions(), zones.Zero)
+	pair := refinementState{implementation, specification, zone}
+	return pair, refinement.delay(pair)
+}
+
+func (refinement *refinement) initialState(automaton TIOAutomaton, valuations language.Valuations) State {
+	location, _ := automaton.Location(automaton.initial)
+	zone := zones.NewDBM(refinement.clocks.Dimensions(), zones.Zero)
+	return NewState(automaton.initial, valuations, location.invariant.condition, zone)
+}
+
+// Lets time pass in the pair and returns true if the delays of the implementation are allowed by the
+// specification. Afterwards the zone of the pair is constrained by the invariants of both automata.
+func (refinement *refinement) delay(pair refinementState) bool {
+	implementation, _ := refinement.implementation.Location(pair.implementation.location)
+	specification, _ := refinement.specification.Location(pair.specification.location)
+
+	pair.zone.Up()
+	if !refinement.zones.Constrain(pair.zone, implementation.invariant.condition) {
+		return true
+	}
+
+	delays := pair.zone.Copy()
+	refinement.zones.Constrain(pair.zone, specification.invariant.condition)
+	subset, _ := delays.Relation(pair.zone, zones.Reference, refinement.clocks.Dimensions())
+	return subset
+}
+
+// Returns the edges from the state with the action which are enabled by the data of the state.
+func (refinement *refinement) enabled(automaton TIOAutomaton, state State, action Action) []IOEdge {
+	outgoing := automaton.Outgoing(state.location, action)
+	edges := make([]IOEdge, 0, len(outgoing))
+	for _, edge := range outgoing {
+		if edge.IsEnabled(state.valuations, refinement.interpreter) {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// Returns the zone of the pair constrained by the guard of the edge.
+func (refinement *refinement) guard(pair refinementState, edge IOEdge) (zones.DBM, bool) {
+	zone := pair.zone.Copy()
+	return zone, refinement.zones.Constrain(zone, edge.guard.condition)
+}
+
+func (refinement *refinement) successors(pair refinementState) (successors []refinementState, valid bool) {
+	for _, output := range refinement.implementation.outputs {
+		implementation := refinement.enabled(refinement.implementation, pair.implementation, output)
+		specification := refinement.enabled(refinement.specification, pair.specification, output)
+		matched, valid := refinement.simulate(pair, implementation, specification, true)
+		if !valid {
+			return nil, false
+		}
+		successors = append(successors, matched...)
+	}
+
+	for _, input := range refinement.specification.inputs {
+		implementation := refinement.enabled(refinement.implementation, pair.implementation, input)
+		specification := refinement.enabled(refinement.specification, pair.specification, input)
+		matched, valid := refinement.simulate(pair, specification, implementation, false)
+		if !valid {
+			return nil, false
+		}
+		successors = append(successors, matched...)
+	}
+
+	return successors, true
+}
+
+// Checks that whenever a leading edge can be taken then one of the following edges can be taken as well.
+// For outputs the implementation leads whilst for inputs the specification leads.
+func (refinement *refinement) simulate(
+	pair refinementState, leaders, followers []IOEdge, output bool,
+) (successors []refinementState, valid bool) {
+	for _, leader := range leaders {
+		leading, enabled := refinement.guard(pair, leader)
+		if !enabled {
+			continue
+		}
+
+		// The clock valuations where the leader is enabled must be covered by the followers.
+		uncovered := zones.NewFederation(refinement.clocks.Dimensions(), leading)
+		for _, follower := range followers {
+			following, enabled := refinement.guard(pair, follower)
+			if !enabled {
+				continue
+			}
+			uncovered = uncovered.Subtraction(
+				zones.NewFederation(refinement.clocks.Dimensions(), following),
+			)
+
+			// The leader and follower are taken together.
+			implementation, specification := leader, follower
+			if !output {
+				implementation, specification = follower, leader
+			}
+			successor, valid := refinement.traverse(pair, leading, implementation, specification)
+			if !valid {
+				return nil, false
+			}
+			if successor.zone.IsConsistent() {
+				successors = append(successors, successor)
+			}
+		}
+
+		if !uncovered.IsEmpty() {
+			return nil, false
+		}
+	}
+
+	return successors, true
+}
+
+// Takes both edges from the zone where the leader is enabled and lets time pass in the destinations.
+func (refinement *refinement) traverse(
+	pair refinementState, leading zones.DBM, implementation, specification IOEdge,
+) (refinementState, bool) {
+	zone := leading.Copy()
+	refinement.zones.Constrain(zone, implementation.guard.condition)
+	refinement.zones.Constrain(zone, specification.guard.condition)
+	refinement.zones.Apply(zone, implementation.update.expression)
+	refinement.zones.Apply(zone, specification.update.expression)
+
+	successor := refinementState{
+		implementation: implementation.Traverse(pair.implementation, refinement.interpreter),
+		specification:  specification.Traverse(pair.specification, refinement.interpreter),
+		zone:           zone,
+	}
+	if !zone.IsConsistent() {
+		return successor, true
+	}
+	return successor, refinement.delay(successor)
+}
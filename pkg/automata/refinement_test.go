@@ -0,0 +1,175 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Refines(t *testing.T) {
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	x, y := symbolsMap.Insert("x"), symbolsMap.Insert("y")
+	coin := Action(symbolsMap.Insert("coin"))
+	coffee := Action(symbolsMap.Insert("coffee"))
+	interpreter := NewInterpreter(z3.NewContext(z3.NewConfig()), language.NewVariablesMap())
+
+	atLeast := func(clock symbols.Symbol, limit int) Guard {
+		return NewGuard(language.NewClockConstraint(reference, clock, zones.NewRelation(-limit, zones.Weak)))
+	}
+	atMost := func(clock symbols.Symbol, limit int) Invariant {
+		return NewInvariant(language.NewClockConstraint(clock, reference, zones.NewRelation(limit, zones.Weak)))
+	}
+	reset := func(clock symbols.Symbol) Update {
+		return NewUpdate(language.NewBlockExpression(language.NewTrue(), language.NewClockReset(clock, 0)))
+	}
+
+	// A machine which accepts a coin and serves coffee within a window of time.
+	machine := func(clock symbols.Symbol, guard Guard, invariant Invariant, coins Guard) TIOAutomaton {
+		clocks := language.NewClocksMap(reference)
+		clocks.Declare(clock)
+		builder := NewIOAutomatonBuilder()
+		builder.AddInputs(coin)
+		builder.AddOutputs(coffee)
+		idle := builder.AddInitial("idle")
+		brewing := builder.AddLocation("brewing", WithInvariant(invariant))
+		builder.AddEdge(idle, coin, brewing, WithGuard(coins), WithUpdate(reset(clock)))
+		builder.AddEdge(brewing, coffee, idle, WithGuard(guard))
+		builder.AddLoop(brewing, coin)
+		return *NewTIOAutomaton(builder.Build(), clocks)
+	}
+
+	specification := machine(y, atLeast(y, 1), atMost(y, 5), NewTrueGuard())
+	tests := []struct {
+		name           string
+		implementation TIOAutomaton
+		refines        bool
+		trace          int
+	}{
+		{
+			name:           "Stricter output guard and invariant",
+			implementation: machine(x, atLeast(x, 2), atMost(x, 4), NewTrueGuard()),
+			refines:        true,
+		},
+		{
+			name:           "Output before the specification allows it",
+			implementation: machine(x, atLeast(x, 0), atMost(x, 4), NewTrueGuard()),
+			refines:        false,
+			trace:          2,
+		},
+		{
+			name:           "Delays longer than the specification allows",
+			implementation: machine(x, atLeast(x, 2), atMost(x, 6), NewTrueGuard()),
+			refines:        false,
+			trace:          1,
+		},
+		{
+			name:           "Input not accepted when the specification requires it",
+			implementation: machine(x, atLeast(x, 2), atMost(x, 4), NewFalseGuard()),
+			refines:        false,
+			trace:          1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			refines, trace, err := tt.implementation.Refines(specification, interpreter, language.NewValuationsMap())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.refines, refines)
+			assert.Len(t, trace, tt.trace)
+		})
+	}
+}
+
+func Test_RefinesErrors(t *testing.T) {
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference := symbolsMap.Insert("0")
+	x := symbolsMap.Insert("x")
+	coin := Action(symbolsMap.Insert("coin"))
+	coffee := Action(symbolsMap.Insert("coffee"))
+	interpreter := NewInterpreter(z3.NewContext(z3.NewConfig()), language.NewVariablesMap())
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+
+	machine := func(outputs []Action, guard language.Expression) TIOAutomaton {
+		builder := NewIOAutomatonBuilder()
+		builder.AddInputs(coin)
+		builder.AddOutputs(outputs...)
+		idle := builder.AddInitial("idle")
+		builder.AddLoop(idle, coin, WithGuard(NewGuard(guard)))
+		return *NewTIOAutomaton(builder.Build(), clocks)
+	}
+	disjunction := language.NewBinary(
+		language.NewClockConstraint(x, reference, zones.NewRelation(1, zones.Strict)),
+		language.LogicalOr,
+		language.NewClockConstraint(reference, x, zones.NewRelation(-2, zones.Strict)),
+	)
+
+	tests := []struct {
+		name                          string
+		implementation, specification TIOAutomaton
+		err                           string
+	}{
+		{
+			name:           "Different alphabets",
+			implementation: machine([]Action{coffee}, language.NewTrue()),
+			specification:  machine(nil, language.NewTrue()),
+			err:            "the automata of the refinement do not have the same inputs and outputs",
+		},
+		{
+			name:           "Disjunctive clock constraints",
+			implementation: machine(nil, language.NewTrue()),
+			specification:  machine(nil, disjunction),
+			err:            "the guard of the edge from \"idle\" to \"idle\" has disjunctive clock constraints",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			refines, trace, err := tt.implementation.Refines(tt.specification, interpreter, language.NewValuationsMap())
+
+			// Assert
+			assert.False(t, refines)
+			assert.Nil(t, trace)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func Test_RefinementVisited(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	v := symbolsMap.Insert("v")
+	variables := language.NewVariablesMap()
+	variables.Declare(v, language.IntegerSort)
+	clocks := language.NewClocksMap(symbolsMap.Insert("0"))
+	refinement := refinement{
+		interpreter: NewInterpreter(z3.NewContext(z3.NewConfig()), variables),
+		clocks:      clocks,
+		passed:      map[locationPair][]refinementState{},
+	}
+	pair := func(constraint language.Expression) refinementState {
+		zone := zones.NewDBM(clocks.Dimensions(), zones.Zero)
+		state := NewState(0, language.NewValuationsMap(), constraint, zone)
+		return refinementState{state, state, zone.Copy()}
+	}
+	positive := language.NewBinary(language.NewVariable(v), language.GreaterThan, language.NewInteger(0))
+	negative := language.NewBinary(language.NewVariable(v), language.LessThan, language.NewInteger(0))
+	refinement.visit(pair(positive))
+
+	// Act
+	same := refinement.visited(pair(positive))
+	stricter := refinement.visited(pair(language.NewBinary(language.NewVariable(v), language.GreaterThan, language.NewInteger(1))))
+	different := refinement.visited(pair(negative))
+
+	// Assert
+	assert.True(t, same)
+	assert.True(t, stricter)
+	assert.False(t, different)
+}
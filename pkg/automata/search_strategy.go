@@ -5,13 +5,17 @@ import (
 	"github.com/Brandhoej/gobion/pkg/structures"
 )
 
+// Explores the states reachable from the roots until yield returns true for a state.
+// The trace from a root to that state is returned or nil if no such state is reached.
 type SearchStrategy interface {
 	For(yield func(state State) bool, roots ...State) Trace
 }
 
 type BreadthFirstSearch struct {
-	system *SymbolicTransitionSystem
-	solver *Interpreter
+	system    *SymbolicTransitionSystem
+	solver    *Interpreter
+	symmetry  *Symmetry
+	reduction *PartialOrderReduction
 }
 
 func NewBreadthFirstSearch(system *SymbolicTransitionSystem, solver *Interpreter) BreadthFirstSearch {
@@ -21,31 +25,45 @@ func NewBreadthFirstSearch(system *SymbolicTransitionSystem, solver *Interpreter
 	}
 }
 
+// Returns the search where the explored states are merged by the symmetry.
+func (search BreadthFirstSearch) WithSymmetry(symmetry *Symmetry) BreadthFirstSearch {
+	search.symmetry = symmetry
+	return search
+}
+
+// Returns the search where the successors of the explored states are reduced by the partial order reduction.
+func (search BreadthFirstSearch) WithReduction(reduction *PartialOrderReduction) BreadthFirstSearch {
+	search.reduction = reduction
+	return search
+}
+
 func (search BreadthFirstSearch) For(yield func(state State) bool, roots ...State) Trace {
-	var terminal structures.LinkedNode[State]
-	states := NewStateSet()
+	var trace Trace
+	states := NewSymmetricStateSet(search.symmetry)
 	states.Insert(search.solver, roots...)
 	algorithms.BFS(
-		search.system.Outgoing,
+		successors(search.system, search.reduction, search.solver, states),
 		func(state State) bool {
 			return states.Contains(state, search.solver)
 		},
 		func(node structures.LinkedNode[State]) bool {
 			states.Insert(search.solver, node.Data)
-			stop := yield(node.Data)
-			if stop {
-				terminal = node
+			if yield(node.Data) {
+				trace = NewTrace(node)
+				return false
 			}
-			return stop
+			return true
 		},
 		roots...,
 	)
-	return terminal.Array()
+	return trace
 }
 
 type DepthFirstSearch struct {
-	system *SymbolicTransitionSystem
-	solver *Interpreter
+	system    *SymbolicTransitionSystem
+	solver    *Interpreter
+	symmetry  *Symmetry
+	reduction *PartialOrderReduction
 }
 
 func NewDepthFirstSearch(system *SymbolicTransitionSystem, solver *Interpreter) DepthFirstSearch {
@@ -55,24 +73,50 @@ func NewDepthFirstSearch(system *SymbolicTransitionSystem, solver *Interpreter)
 	}
 }
 
+// Returns the search where the explored states are merged by the symmetry.
+func (search DepthFirstSearch) WithSymmetry(symmetry *Symmetry) DepthFirstSearch {
+	search.symmetry = symmetry
+	return search
+}
+
+// Returns the search where the successors of the explored states are reduced by the partial order reduction.
+func (search DepthFirstSearch) WithReduction(reduction *PartialOrderReduction) DepthFirstSearch {
+	search.reduction = reduction
+	return search
+}
+
 func (search DepthFirstSearch) For(yield func(state State) bool, roots ...State) Trace {
-	var terminal structures.LinkedNode[State]
-	states := NewStateSet()
+	var trace Trace
+	states := NewSymmetricStateSet(search.symmetry)
 	states.Insert(search.solver, roots...)
 	algorithms.DFS(
-		search.system.Outgoing,
+		successors(search.system, search.reduction, search.solver, states),
 		func(state State) bool {
 			return states.Contains(state, search.solver)
 		},
 		func(node structures.LinkedNode[State]) bool {
 			states.Insert(search.solver, node.Data)
-			stop := yield(node.Data)
-			if stop {
-				terminal = node
+			if yield(node.Data) {
+				trace = NewTrace(node)
+				return false
 			}
-			return stop
+			return true
 		},
 		roots...,
 	)
-	return terminal.Array()
+	return trace
+}
+
+// Returns the successors of states in the system which are reduced if the reduction is set.
+func successors(
+	system *SymbolicTransitionSystem, reduction *PartialOrderReduction, solver *Interpreter, states StateSet,
+) func(state State) []State {
+	if reduction == nil {
+		return system.Outgoing
+	}
+	return func(state State) []State {
+		return reduction.Outgoing(state, func(successor State) bool {
+			return states.Contains(successor, solver)
+		})
+	}
 }
@@ -3,36 +3,57 @@ package automata
 import (
 	"github.com/Brandhoej/gobion/pkg/automata/language"
 	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
 )
 
 type State struct {
 	location   symbols.Symbol
 	valuations language.Valuations
 	constraint language.Expression
+	zone       zones.DBM
 }
 
 func NewState(
 	location symbols.Symbol,
 	valuations language.Valuations,
 	constraint language.Expression,
+	zone zones.DBM,
 ) State {
 	return State{
 		location:   location,
 		valuations: valuations,
 		constraint: constraint,
+		zone:       zone,
 	}
 }
 
+func (state State) Location() symbols.Symbol {
+	return state.location
+}
+
+func (state State) Valuations() language.Valuations {
+	return state.valuations
+}
+
+func (state State) Constraint() language.Expression {
+	return state.constraint
+}
+
+func (state State) Zone() zones.DBM {
+	return state.zone
+}
+
+// Returns true if the state is included in the other state. States whose zones are over different clocks are never included.
 func (state State) SubsetOf(other State, interpreter *Interpreter) bool {
-	if state.location != other.location {
+	if state.location != other.location || state.zone.Clocks() != other.zone.Clocks() {
+		return false
+	}
+	if subset, _ := state.zone.Relation(other.zone, zones.Reference, state.zone.Clocks()); !subset {
 		return false
 	}
-	return interpreter.IsSatisfied(
+	// The constraint is included if no valuation satisfies it without satisfying the other constraint.
+	return !interpreter.IsSatisfied(
 		state.valuations,
-		language.NewBinary(
-			language.Conjunction(state.constraint, other.constraint),
-			language.Implication,
-			state.constraint,
-		),
+		language.Conjunction(state.constraint, language.LogicalNegate(other.constraint)),
 	)
 }
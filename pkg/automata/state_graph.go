@@ -0,0 +1,134 @@
+package automata
+
+import "github.com/Brandhoej/gobion/pkg/automata/language"
+
+// A transition of the state graph by the edge to the state at the index. Progress transitions have no edge.
+type stateTransition struct {
+	edge        Edge
+	destination int
+	progress    bool
+}
+
+// The graph of the reachable states of a transition system where the states which are included
+// in an explored state are merged into it. States are referred to by their index in the graph.
+type stateGraph struct {
+	states      []State
+	parents     []int
+	transitions [][]stateTransition
+}
+
+// Explores all states reachable from the initial state breadth first.
+func (system *SymbolicTransitionSystem) explore(valuations language.Valuations) *stateGraph {
+	return system.exploreBy(system.Initial(valuations), func(state, explored State) bool {
+		return state.SubsetOf(explored, system.interpreter)
+	}, nil)
+}
+
+// Explores all states reachable from the initial state breadth first where a state is merged into the first
+// explored state it can be merged with. If the progress function is not nil then its successors are followed
+// by progress transitions besides the successors of the edges.
+func (system *SymbolicTransitionSystem) exploreBy(
+	initial State, merge func(state, explored State) bool, progress func(state State) (State, bool),
+) *stateGraph {
+	graph := &stateGraph{}
+	graph.add(initial, -1)
+	follow := func(index int, successor State, transition stateTransition) {
+		transition.destination = graph.find(successor, merge)
+		if transition.destination < 0 {
+			transition.destination = graph.add(successor, index)
+		}
+		graph.transitions[index] = append(graph.transitions[index], transition)
+	}
+
+	for index := 0; index < len(graph.states); index++ {
+		state := graph.states[index]
+		location, _ := system.automaton.Location(state.location)
+		if !location.IsEnabled(state.valuations, system.interpreter) {
+			continue
+		}
+
+		for _, edge := range system.automaton.Outgoing(state.location) {
+			if successor, enabled := system.Successor(state, edge); enabled {
+				follow(index, successor, stateTransition{edge: edge})
+			}
+		}
+		if progress == nil {
+			continue
+		}
+		if successor, enabled := progress(state); enabled {
+			follow(index, successor, stateTransition{progress: true})
+		}
+	}
+	return graph
+}
+
+func (graph *stateGraph) add(state State, parent int) int {
+	graph.states = append(graph.states, state)
+	graph.parents = append(graph.parents, parent)
+	graph.transitions = append(graph.transitions, nil)
+	return len(graph.states) - 1
+}
+
+// Returns the index of an explored state the state can be merged with or -1 if there is none.
+func (graph *stateGraph) find(state State, merge func(state, explored State) bool) int {
+	for index, explored := range graph.states {
+		if merge(state, explored) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Returns the trace from the initial state to the state at the index.
+func (graph *stateGraph) trace(index int) (trace Trace) {
+	for current := index; current >= 0; current = graph.parents[current] {
+		trace = append(Trace{graph.states[current]}, trace...)
+	}
+	return trace
+}
+
+// Returns the strongly connected components of the graph by Tarjan's algorithm.
+func (graph *stateGraph) components() (components [][]int) {
+	indices, lowlinks := make([]int, len(graph.states)), make([]int, len(graph.states))
+	onStack := make([]bool, len(graph.states))
+	stack, counter := []int{}, 1
+
+	var connect func(vertex int)
+	connect = func(vertex int) {
+		indices[vertex], lowlinks[vertex] = counter, counter
+		counter++
+		stack = append(stack, vertex)
+		onStack[vertex] = true
+
+		for _, transition := range graph.transitions[vertex] {
+			successor := transition.destination
+			if indices[successor] == 0 {
+				connect(successor)
+				lowlinks[vertex] = min(lowlinks[vertex], lowlinks[successor])
+			} else if onStack[successor] {
+				lowlinks[vertex] = min(lowlinks[vertex], indices[successor])
+			}
+		}
+
+		if lowlinks[vertex] == indices[vertex] {
+			component := []int{}
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == vertex {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for vertex := range graph.states {
+		if indices[vertex] == 0 {
+			connect(vertex)
+		}
+	}
+	return components
+}
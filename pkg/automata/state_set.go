@@ -2,8 +2,11 @@ package automata
 
 import "github.com/Brandhoej/gobion/pkg/symbols"
 
+// A set of states where states included in an inserted state are contained by it. The subsumption is sound for
+// reachability but not for liveness as an included state is not necessarily on the cycles of the including state.
 type StateSet struct {
-	states map[symbols.Symbol][]State
+	states   map[symbols.Symbol][]State
+	symmetry *Symmetry
 }
 
 func NewStateSet() StateSet {
@@ -12,8 +15,24 @@ func NewStateSet() StateSet {
 	}
 }
 
+// Returns a set where states are stored by their canonical state such that permutations of the
+// symmetric processes are merged. If the symmetry is nil then the states are stored as they are.
+func NewSymmetricStateSet(symmetry *Symmetry) StateSet {
+	set := NewStateSet()
+	set.symmetry = symmetry
+	return set
+}
+
+func (set StateSet) canonical(state State) State {
+	if set.symmetry == nil {
+		return state
+	}
+	return set.symmetry.Canonical(state)
+}
+
 func (set StateSet) Insert(solver *Interpreter, states ...State) (counter int) {
 	for _, state := range states {
+		state = set.canonical(state)
 		if states, exists := set.states[state.location]; exists {
 			if set.Contains(state, solver) {
 				continue
@@ -31,6 +50,7 @@ func (set StateSet) Insert(solver *Interpreter, states ...State) (counter int) {
 }
 
 func (set StateSet) Contains(target State, solver *Interpreter) bool {
+	target = set.canonical(target)
 	if states, exists := set.states[target.location]; exists {
 		for _, state := range states {
 			if target.SubsetOf(state, solver) {
@@ -6,6 +6,7 @@ import (
 	"github.com/Brandhoej/gobion/internal/z3"
 	"github.com/Brandhoej/gobion/pkg/automata/language"
 	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,6 +18,10 @@ func Test_SubsetOf(t *testing.T) {
 	variables := language.NewVariablesMap()
 	variables.Declare(symbolsMap.Insert("x"), language.IntegerSort)
 	solver := NewInterpreter(context, variables)
+	zone := zones.NewDBM(1, zones.Zero)
+	delayed := zones.NewDBM(2, zones.Zero)
+	delayed.Up()
+	origin := zones.NewDBM(2, zones.Zero)
 	tests := []struct {
 		name     string
 		lhs, rhs State
@@ -24,20 +29,20 @@ func Test_SubsetOf(t *testing.T) {
 	}{
 		{
 			name:     "(0, True) ⊆ (0, True)",
-			lhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue()),
-			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue()),
+			lhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
 			expected: true,
 		},
 		{
 			name:     "(1, True) ⊈ (0, True)",
-			lhs:      NewState(symbols.Symbol(1), valuations, language.NewTrue()),
-			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue()),
+			lhs:      NewState(symbols.Symbol(1), valuations, language.NewTrue(), zone),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
 			expected: false,
 		},
 		{
 			name:     "(0, False) ⊈ (0, True)",
-			lhs:      NewState(symbols.Symbol(0), valuations, language.NewFalse()),
-			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue()),
+			lhs:      NewState(symbols.Symbol(0), valuations, language.NewFalse(), zone),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
 			expected: true,
 		},
 		{
@@ -48,8 +53,9 @@ func Test_SubsetOf(t *testing.T) {
 				language.NewBinary(
 					x, language.Equal, language.NewInteger(0),
 				),
+				zone,
 			),
-			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue()),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
 			expected: true,
 		},
 		{
@@ -60,10 +66,45 @@ func Test_SubsetOf(t *testing.T) {
 				language.NewBinary(
 					x, language.Equal, language.NewInteger(0),
 				),
+				zone,
 			),
-			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue()),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
 			expected: true,
 		},
+		{
+			name: "(0, {x>0}) ⊈ (0, {x<0})",
+			lhs: NewState(
+				symbols.Symbol(0), valuations, language.NewBinary(x, language.GreaterThan, language.NewInteger(0)), zone,
+			),
+			rhs: NewState(
+				symbols.Symbol(0), valuations, language.NewBinary(x, language.LessThan, language.NewInteger(0)), zone,
+			),
+			expected: false,
+		},
+		{
+			name:     "(0, True, x=0) ⊆ (0, True, x≥0)",
+			lhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), origin),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), delayed),
+			expected: true,
+		},
+		{
+			name:     "(0, True, x≥0) ⊈ (0, True, x=0)",
+			lhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), delayed),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), origin),
+			expected: false,
+		},
+		{
+			name:     "(0, True, x=0) ⊈ (0, True)",
+			lhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), origin),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
+			expected: false,
+		},
+		{
+			name:     "(0, True) ⊈ (0, True, x=0)",
+			lhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), zone),
+			rhs:      NewState(symbols.Symbol(0), valuations, language.NewTrue(), origin),
+			expected: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -20,27 +20,13 @@ func NewSymbolicAutomaton(automaton Automaton[Location, Edge]) *SymbolicAutomato
 
 func (automaton SymbolicAutomaton) Complete(interpreter *Interpreter, complete func(symbols.Symbol, Guard) symbols.Symbol) {
 	automaton.Locations(func(source symbols.Symbol, location Location) bool {
-		// The disjunction is the disjunction of all guard conditions.
-		// If there are not outgoing edges then we assume a false edge condition.
-		// This means that if there are no edge a true condition is the missing.
-		var disjunction Guard
-		if outgoings := automaton.Outgoing(source); len(outgoings) > 0 {
-			guards := make([]Guard, len(outgoings))
-			for idx := range outgoings {
-				guards[idx] = outgoings[idx].guard
-			}
-
-			disjunction = guards[0]
-			if len(guards) > 1 {
-				// We do "guards[0]." and not "disjunction." to not have an additional "depth".
-				disjunction = guards[0].Disjunction(guards[1:]...)
-			}
-		} else {
-			disjunction = NewGuard(language.NewFalse())
-		}
-
 		// This is the missing guard condition.
-		negation := disjunction.Negation()
+		outgoings := automaton.Outgoing(source)
+		guards := make([]Guard, len(outgoings))
+		for idx := range outgoings {
+			guards[idx] = outgoings[idx].guard
+		}
+		negation := missingGuard(guards...)
 
 		// Constrain by the location's invariant.
 		invariant := NewGuard(location.invariant.condition)
@@ -72,3 +58,25 @@ func (automaton SymbolicAutomaton) DOT(writer io.Writer, symbols symbols.Store[a
 		},
 	)
 }
+
+// Returns an error if the clock constraints of an invariant or guard cannot be represented by a zone.
+func (automaton SymbolicAutomaton) convex() (err error) {
+	automaton.Locations(func(key symbols.Symbol, location Location) bool {
+		if !language.IsConvex(location.invariant.condition) {
+			err = fmt.Errorf("the invariant of \"%s\" has disjunctive clock constraints", location.name)
+			return false
+		}
+		for _, edge := range automaton.Outgoing(key) {
+			if !language.IsConvex(edge.guard.condition) {
+				destination, _ := automaton.Location(edge.destination)
+				err = fmt.Errorf(
+					"the guard of the edge from \"%s\" to \"%s\" has disjunctive clock constraints",
+					location.name, destination.name,
+				)
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
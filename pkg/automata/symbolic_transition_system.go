@@ -1,25 +1,70 @@
 package automata
 
-import "github.com/Brandhoej/gobion/pkg/automata/language"
+import (
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
 
 type SymbolicTransitionSystem struct {
 	automaton   *SymbolicAutomaton
 	interpreter *Interpreter
+	clocks      language.Clocks
+	zones       language.ZoneInterpreter
+	bounds      Bounds
 }
 
 func NewTransitionSystem(
 	automaton *SymbolicAutomaton, interpreter *Interpreter,
+) *SymbolicTransitionSystem {
+	// Without clocks the zones of the states only consist of the reference clock.
+	return NewTimedTransitionSystem(automaton, language.NewClocksMap(), interpreter)
+}
+
+func NewTimedTransitionSystem(
+	automaton *SymbolicAutomaton, clocks language.Clocks, interpreter *Interpreter,
 ) *SymbolicTransitionSystem {
 	return &SymbolicTransitionSystem{
-		automaton: automaton,
+		automaton:   automaton,
 		interpreter: interpreter,
+		clocks:      clocks,
+		zones:       language.NewZoneInterpreter(clocks),
+		bounds:      NewBounds(automaton, clocks),
 	}
 }
 
+func (system *SymbolicTransitionSystem) Automaton() *SymbolicAutomaton {
+	return system.automaton
+}
+
+func (system *SymbolicTransitionSystem) Interpreter() *Interpreter {
+	return system.interpreter
+}
+
+func (system *SymbolicTransitionSystem) Clocks() language.Clocks {
+	return system.clocks
+}
+
+// Returns the initial state where time has passed from the origin zone as long as the initial invariant allows.
+// The zones of all states are extrapolated by the bounds of their locations such that the exploration terminates.
 func (system *SymbolicTransitionSystem) Initial(valuations language.Valuations) State {
 	initial := system.automaton.initial
 	location, _ := system.automaton.Location(initial)
-	return NewState(initial, valuations, location.invariant.condition)
+	zone := zones.NewDBM(system.clocks.Dimensions(), zones.Zero)
+	system.delay(zone, initial, location)
+	return NewState(initial, valuations, location.invariant.condition, zone)
+}
+
+// Lets time pass in the zone, unless the location is urgent, constrains it by the invariant of the location and extrapolates it.
+func (system *SymbolicTransitionSystem) delay(zone zones.DBM, key symbols.Symbol, location Location) {
+	if !location.IsUrgent() {
+		zone.Up()
+	}
+	if system.zones.Constrain(zone, location.invariant.condition) {
+		// The extrapolation may relax the invariant so it is applied again.
+		system.bounds.Extrapolate(key, zone)
+		system.zones.Constrain(zone, location.invariant.condition)
+	}
 }
 
 // Returns all states from the state.
@@ -36,18 +81,41 @@ func (system *SymbolicTransitionSystem) Outgoing(state State) (successors []Stat
 
 	edges := system.automaton.Outgoing(state.location)
 	for _, edge := range edges {
-		// Check if we can even traverse the edge.
-		if !edge.IsEnabled(state.valuations, system.interpreter) {
-			continue
+		if successor, enabled := system.Successor(state, edge); enabled {
+			successors = append(successors, successor)
 		}
-
-		// We can traverse the edge so we create a new and updated state.
-		state := edge.Traverse(state, system.interpreter)
-		successors = append(successors, state)
 	}
 	return successors
 }
 
+// Returns the state after traversing the edge from the state and then letting time pass.
+// If the edge cannot be traversed by any of the valuations of the state then false is returned.
+func (system *SymbolicTransitionSystem) Successor(state State, edge Edge) (State, bool) {
+	// Check if we can even traverse the edge.
+	if !edge.IsEnabled(state.valuations, system.interpreter) {
+		return state, false
+	}
+
+	// The edge can only be taken by the clock valuations satisfying the guard.
+	zone := state.zone.Copy()
+	if !system.zones.Constrain(zone, edge.guard.condition) {
+		return state, false
+	}
+
+	// After the resets the clock valuations must satisfy the invariant of the destination.
+	destination, _ := system.automaton.Location(edge.destination)
+	system.zones.Apply(zone, edge.update.expression)
+	if !system.zones.Constrain(zone, destination.invariant.condition) {
+		return state, false
+	}
+	system.delay(zone, edge.destination, destination)
+
+	// We can traverse the edge so we create a new and updated state.
+	successor := edge.Traverse(state, system.interpreter)
+	successor.zone = zone
+	return successor, true
+}
+
 func (system *SymbolicTransitionSystem) Reachability(
 	valuations language.Valuations, search SearchStrategy, goals ...State,
 ) Trace {
@@ -0,0 +1,166 @@
+package automata
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+// The interchangeable processes of a network which are the instances of the same template. States where the
+// processes are permuted are equivalent if the instances only differ by their local clocks and variables.
+type Symmetry struct {
+	automaton *NetworkAutomaton
+	processes []int
+	// The local clocks and variables of each process in the same order such that they correspond.
+	clocks    [][]zones.Clock
+	variables [][]symbols.Symbol
+}
+
+// Returns the symmetry of the instances of the template in the network of the automaton. An error is returned if
+// a parameter of the template occurs in a guard, invariant or update. Then the instances may be given different
+// arguments, such as their ids or the indices of global arrays, such that permuted states are not equivalent.
+func (automaton *NetworkAutomaton) Symmetry(template *Template, store symbols.Store[any]) (*Symmetry, error) {
+	if err := template.isSymmetric(store); err != nil {
+		return nil, err
+	}
+
+	symmetry := &Symmetry{
+		automaton: automaton,
+	}
+	variables, clocks := template.locals()
+	for process, instance := range automaton.network.templates {
+		if instance != template {
+			continue
+		}
+		name := automaton.network.names[process]
+		symmetry.processes = append(symmetry.processes, process)
+
+		locals := make([]symbols.Symbol, len(variables))
+		for idx := range variables {
+			locals[idx] = localSymbol(store, name, variables[idx])
+		}
+		symmetry.variables = append(symmetry.variables, locals)
+
+		dimensions := make([]zones.Clock, len(clocks))
+		for idx := range clocks {
+			clock, exists := automaton.clocks.Lookup(localSymbol(store, name, clocks[idx]))
+			if !exists {
+				panic("The local clock of the instance is not a clock of the network")
+			}
+			dimensions[idx] = clock
+		}
+		symmetry.clocks = append(symmetry.clocks, dimensions)
+	}
+	return symmetry, nil
+}
+
+// Returns an error if a parameter of the template occurs in its guards, invariants or updates.
+func (template *Template) isSymmetric(store symbols.Store[any]) error {
+	expressions := []language.Expression{}
+	template.automaton.Locations(func(_ symbols.Symbol, location Location) bool {
+		expressions = append(expressions, location.invariant.condition)
+		return true
+	})
+	template.automaton.Edges(func(edge IOEdge) bool {
+		expressions = append(expressions, edge.guard.condition, edge.update.expression)
+		return true
+	})
+	accesses := language.NewAccesses(expressions...)
+	for _, parameter := range template.parameters {
+		if accesses.Reads(parameter.symbol) || accesses.Writes(parameter.symbol) {
+			name, _ := store.Item(parameter.symbol)
+			return fmt.Errorf(
+				"the instances of %s are not symmetric as its parameter \"%v\" is used", template.name, name,
+			)
+		}
+	}
+	return nil
+}
+
+// Returns the equivalent state where the symmetric processes are sorted by their locations, the values of their
+// local variables and the bounds of their local clocks. States which only differ by a permutation of the processes
+// mostly have the same canonical state. Ties are kept in the order of the processes such that some may differ.
+func (symmetry *Symmetry) Canonical(state State) State {
+	vector, exists := symmetry.automaton.Components(state.location)
+	if !exists {
+		return state
+	}
+
+	keys := make([][]int, len(symmetry.processes))
+	for idx := range symmetry.processes {
+		key, valued := symmetry.key(state, vector, idx)
+		if !valued {
+			return state
+		}
+		keys[idx] = key
+	}
+	order := make([]int, len(symmetry.processes))
+	for idx := range order {
+		order[idx] = idx
+	}
+	slices.SortStableFunc(order, func(lhs, rhs int) int {
+		return slices.Compare(keys[lhs], keys[rhs])
+	})
+
+	// The process at "from" takes the place of the process at "to".
+	permuted := slices.Clone(vector)
+	for to, from := range order {
+		permuted[symmetry.processes[to]] = vector[symmetry.processes[from]]
+	}
+	location, exists := symmetry.automaton.Location(permuted)
+	if !exists {
+		return state
+	}
+
+	permutation := make([]zones.Clock, state.zone.Clocks())
+	for clock := range permutation {
+		permutation[clock] = zones.Clock(clock)
+	}
+	substitution := language.NewSubstitution()
+	valuations := state.valuations.Copy()
+	for to, from := range order {
+		for idx, clock := range symmetry.clocks[from] {
+			permutation[clock] = symmetry.clocks[to][idx]
+		}
+		for idx, variable := range symmetry.variables[from] {
+			renamed := symmetry.variables[to][idx]
+			substitution.Rename(variable, renamed)
+			value, _ := state.valuations.Value(variable)
+			valuations.Assign(renamed, value)
+		}
+	}
+
+	return NewState(
+		location, valuations, substitution.Substitute(state.constraint), state.zone.Permute(permutation),
+	)
+}
+
+// Returns the key the process is sorted by or false if one of its local variables has no value.
+func (symmetry *Symmetry) key(state State, vector []symbols.Symbol, idx int) (key []int, valued bool) {
+	key = append(key, int(vector[symmetry.processes[idx]]))
+	for _, variable := range symmetry.variables[idx] {
+		value, exists := state.valuations.Value(variable)
+		if !exists {
+			return nil, false
+		}
+		switch cast := value.(type) {
+		case language.Integer:
+			key = append(key, cast.Value())
+		case language.Boolean:
+			if cast.Value() {
+				key = append(key, 1)
+			} else {
+				key = append(key, 0)
+			}
+		default:
+			return nil, false
+		}
+	}
+	for _, clock := range symmetry.clocks[idx] {
+		key = append(key, int(state.zone.Upper(clock)), int(state.zone.Lower(clock)))
+	}
+	return key, true
+}
@@ -0,0 +1,126 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Symmetry(t *testing.T) {
+	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	jobs := symbolsMap.Insert("jobs")
+	start := Action(symbolsMap.Insert("start"))
+	stop := Action(symbolsMap.Insert("stop"))
+
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+	locals := language.NewVariablesMap()
+	locals.Declare(jobs, language.IntegerSort)
+
+	// A worker is busy for 1 to 2 time units and counts its jobs up to 2.
+	worker := NewIOAutomatonBuilder()
+	worker.AddOutputs(start, stop)
+	idle := worker.AddInitial("idle")
+	busy := worker.AddLocation("busy", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(2, zones.Weak))),
+	))
+	worker.AddEdge(idle, start, busy,
+		WithGuard(NewGuard(
+			language.NewBinary(language.NewVariable(jobs), language.LessThan, language.NewInteger(2)),
+		)),
+		WithUpdate(NewUpdate(language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)))),
+	)
+	worker.AddEdge(busy, stop, idle, WithGuard(NewGuard(
+		language.NewClockConstraint(reference, x, zones.NewRelation(-1, zones.Weak)),
+	)))
+	template := NewTemplate("Worker", NewTIOAutomaton(worker.Build(), clocks), locals)
+
+	variables := language.NewVariablesMap()
+	network := NewNetwork()
+	for range [3]struct{}{} {
+		network.AddInstance(template, symbolsMap, variables)
+	}
+	product := network.Automaton()
+	interpreter := NewInterpreter(context, variables)
+	system := product.TransitionSystem(interpreter)
+	symmetry, err := product.Symmetry(template, symbolsMap)
+
+	valuations := language.NewValuationsMap()
+	variables.All(func(symbol symbols.Symbol, _ language.Sort) bool {
+		valuations.Assign(symbol, language.NewInteger(0))
+		return true
+	})
+	initial := system.Initial(valuations)
+	explore := func(search SearchStrategy) (explored int, found bool) {
+		trace := search.For(func(state State) bool {
+			explored++
+			vector, _ := product.Components(state.Location())
+			return vector[0] == busy && vector[1] == busy && vector[2] == busy
+		}, initial)
+		return explored, trace != nil
+	}
+
+	// Act
+	plain, plainFound := explore(NewBreadthFirstSearch(system, interpreter))
+	reduced, reducedFound := explore(NewBreadthFirstSearch(system, interpreter).WithSymmetry(symmetry))
+	successors := system.Outgoing(initial)
+	canonical := make([]State, len(successors))
+	for idx := range successors {
+		canonical[idx] = symmetry.Canonical(successors[idx])
+	}
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, plainFound)
+	assert.True(t, reducedFound)
+	assert.Less(t, reduced, plain)
+	assert.Len(t, successors, 3)
+	for idx := range canonical {
+		vector, _ := product.Components(canonical[idx].Location())
+		assert.Equal(t, []symbols.Symbol{idle, idle, busy}, vector)
+		assert.True(t, canonical[idx].SubsetOf(canonical[0], interpreter))
+	}
+}
+
+func Test_SymmetryParameterised(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, id, turn := symbolsMap.Insert("0"), symbolsMap.Insert("id"), symbolsMap.Insert("turn")
+	work := Action(symbolsMap.Insert("work"))
+	locals := language.NewVariablesMap()
+	locals.Declare(id, language.IntegerSort)
+
+	// A worker may only work when it is its turn.
+	worker := NewIOAutomatonBuilder()
+	worker.AddOutputs(work)
+	idle := worker.AddInitial("idle")
+	worker.AddLoop(idle, work, WithGuard(NewGuard(
+		language.NewBinary(language.NewVariable(turn), language.Equal, language.NewVariable(id)),
+	)))
+	template := NewTemplate(
+		"Worker", NewTIOAutomaton(worker.Build(), language.NewClocksMap(reference)), locals,
+		NewBoundedParameter(id, 0, 1),
+	)
+
+	variables := language.NewVariablesMap()
+	variables.Declare(turn, language.IntegerSort)
+	network := NewNetwork()
+	for id := 0; id < 2; id++ {
+		network.AddInstance(template, symbolsMap, variables, language.NewInteger(id))
+	}
+	product := network.Automaton()
+
+	// Act
+	symmetry, err := product.Symmetry(template, symbolsMap)
+
+	// Assert
+	assert.Nil(t, symmetry)
+	assert.EqualError(t, err, "the instances of Worker are not symmetric as its parameter \"id\" is used")
+}
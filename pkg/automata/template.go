@@ -0,0 +1,246 @@
+package automata
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/graph"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+type ParameterKind uint8
+
+const (
+	IntegerParameter = ParameterKind(iota)
+	BooleanParameter
+	ChannelParameter
+	ClockParameter
+)
+
+// A formal parameter of a template. Integers and booleans are passed by value whereas
+// channels and clocks are passed by reference and are renamed to their arguments.
+type Parameter struct {
+	symbol       symbols.Symbol
+	kind         ParameterKind
+	bounded      bool
+	lower, upper int
+}
+
+func NewParameter(symbol symbols.Symbol, kind ParameterKind) Parameter {
+	return Parameter{
+		symbol: symbol,
+		kind:   kind,
+	}
+}
+
+// Returns the integer parameter whose arguments must be within the inclusive bounds "int[lower,upper]".
+func NewBoundedParameter(symbol symbols.Symbol, lower, upper int) Parameter {
+	if lower > upper {
+		panic("The lower bound of the parameter is greater than its upper bound")
+	}
+	return Parameter{
+		symbol:  symbol,
+		kind:    IntegerParameter,
+		bounded: true,
+		lower:   lower,
+		upper:   upper,
+	}
+}
+
+func (parameter Parameter) Symbol() symbols.Symbol {
+	return parameter.symbol
+}
+
+func (parameter Parameter) Kind() ParameterKind {
+	return parameter.kind
+}
+
+// Returns the inclusive bounds of the parameter if it is a bounded integer.
+func (parameter Parameter) Bounds() (lower, upper int, bounded bool) {
+	return parameter.lower, parameter.upper, parameter.bounded
+}
+
+// An automaton which is instantiated with arguments for its parameters. The clocks and variables of the template
+// which are not parameters are local such that each instance has its own symbols for them. Actions which are not
+// parameters are shared by all instances.
+type Template struct {
+	name       string
+	automaton  *TIOAutomaton
+	variables  language.Variables
+	parameters []Parameter
+}
+
+// Returns a template of the automaton where the variables are the local variables and the clock parameters are
+// clocks of the automaton. Variables of the automaton which are not declared in the variables are global.
+func NewTemplate(name string, automaton *TIOAutomaton, variables language.Variables, parameters ...Parameter) *Template {
+	return &Template{
+		name:       name,
+		automaton:  automaton,
+		variables:  variables,
+		parameters: parameters,
+	}
+}
+
+func (template *Template) Name() string {
+	return template.name
+}
+
+func (template *Template) Parameters() []Parameter {
+	return template.parameters
+}
+
+// Returns the name of the instance with the arguments, e.g. "Train(1)".
+func (template *Template) InstanceName(store symbols.Store[any], arguments ...language.Expression) string {
+	names := make([]string, len(arguments))
+	for idx := range arguments {
+		var buffer bytes.Buffer
+		arguments[idx].Accept(language.NewPrettyPrinter(&buffer, store))
+		names[idx] = buffer.String()
+	}
+	return fmt.Sprintf("%s(%s)", template.name, strings.Join(names, ", "))
+}
+
+// Returns a copy of the automaton where the parameters are substituted by the arguments. Integer and boolean
+// arguments are expressions and channel and clock arguments are variables of the actions and clocks. The local
+// clocks and variables are renamed to new symbols prefixed by the name of the instance, e.g. "Train(1).x",
+// and the local variables are declared in the variables.
+func (template *Template) Instantiate(
+	store symbols.Store[any], variables language.Variables, arguments ...language.Expression,
+) *TIOAutomaton {
+	if len(arguments) != len(template.parameters) {
+		panic(fmt.Sprintf(
+			"The template %s expects %d arguments but got %d",
+			template.name, len(template.parameters), len(arguments),
+		))
+	}
+
+	substitution := language.NewSubstitution()
+	parameters := map[symbols.Symbol]bool{}
+	for idx, parameter := range template.parameters {
+		parameters[parameter.symbol] = true
+		argument := arguments[idx]
+		switch parameter.kind {
+		case IntegerParameter, BooleanParameter:
+			if integer, ok := argument.(language.Integer); ok && parameter.bounded &&
+				(integer.Value() < parameter.lower || integer.Value() > parameter.upper) {
+				panic(fmt.Sprintf(
+					"The argument %d is not within the bounds [%d,%d] of the parameter",
+					integer.Value(), parameter.lower, parameter.upper,
+				))
+			}
+			substitution.Replace(parameter.symbol, argument)
+		case ChannelParameter, ClockParameter:
+			variable, ok := argument.(language.Variable)
+			if !ok {
+				panic("Channel and clock arguments must be variables")
+			}
+			substitution.Rename(parameter.symbol, variable.Symbol())
+		}
+	}
+
+	// All local clocks and variables are given symbols of their own.
+	instance := template.InstanceName(store, arguments...)
+	local := func(symbol symbols.Symbol) symbols.Symbol {
+		renamed := localSymbol(store, instance, symbol)
+		substitution.Rename(symbol, renamed)
+		return renamed
+	}
+	locals, _ := template.locals()
+	for _, symbol := range locals {
+		sort, _ := template.variables.Lookup(symbol)
+		variables.Declare(local(symbol), sort)
+	}
+	references := []symbols.Symbol{}
+	template.automaton.clocks.All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock == zones.Reference {
+			references = append(references, symbol)
+		}
+		return true
+	})
+	clocks := language.NewClocksMap(references...)
+	for clock := zones.Reference + 1; clock < template.automaton.clocks.Dimensions(); clock++ {
+		// The clocks are declared in the order of the template to keep the same dimensions.
+		template.automaton.clocks.All(func(symbol symbols.Symbol, other zones.Clock) bool {
+			if other != clock {
+				return true
+			}
+			if parameters[symbol] {
+				clocks.Declare(substitution.Symbol(symbol))
+			} else {
+				clocks.Declare(local(symbol))
+			}
+			return false
+		})
+	}
+
+	return NewTIOAutomaton(template.substitute(substitution), clocks)
+}
+
+// Returns the symbols of the local variables and clocks of the template in ascending order.
+func (template *Template) locals() (variables, clocks []symbols.Symbol) {
+	parameters := map[symbols.Symbol]bool{}
+	for _, parameter := range template.parameters {
+		parameters[parameter.symbol] = true
+	}
+	template.variables.All(func(symbol symbols.Symbol, _ language.Sort) bool {
+		if !parameters[symbol] {
+			variables = append(variables, symbol)
+		}
+		return true
+	})
+	template.automaton.clocks.All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock != zones.Reference && !parameters[symbol] {
+			clocks = append(clocks, symbol)
+		}
+		return true
+	})
+	slices.Sort(variables)
+	slices.Sort(clocks)
+	return variables, clocks
+}
+
+// Returns the symbol of the local variable or clock in the instance.
+func localSymbol(store symbols.Store[any], instance string, symbol symbols.Symbol) symbols.Symbol {
+	name, _ := store.Item(symbol)
+	return store.Insert(fmt.Sprintf("%s.%v", instance, name))
+}
+
+// Returns a copy of the automaton where the substitution is applied to the locations, edges and actions.
+func (template *Template) substitute(substitution *language.Substitution) IOAutomaton {
+	locations := graph.NewVertexMap[symbols.Symbol, Location]()
+	template.automaton.Locations(func(key symbols.Symbol, location Location) bool {
+		location.invariant = NewInvariant(substitution.Substitute(location.invariant.condition))
+		locations.Add(location, key)
+		return true
+	})
+
+	action := func(action Action) Action {
+		return Action(substitution.Symbol(symbols.Symbol(action)))
+	}
+	edges := graph.NewEdgesMap[symbols.Symbol, IOEdge]()
+	template.automaton.Edges(func(edge IOEdge) bool {
+		edges.Connect(NewIOEdge(
+			edge.source, action(edge.action),
+			NewGuard(substitution.Substitute(edge.guard.condition)),
+			NewUpdate(substitution.Substitute(edge.update.expression)),
+			edge.destination,
+		))
+		return true
+	})
+
+	inputs := make([]Action, len(template.automaton.inputs))
+	for idx := range inputs {
+		inputs[idx] = action(template.automaton.inputs[idx])
+	}
+	outputs := make([]Action, len(template.automaton.outputs))
+	for idx := range outputs {
+		outputs[idx] = action(template.automaton.outputs[idx])
+	}
+
+	dg := graph.NewLabeledDirected[symbols.Symbol, IOEdge, Location](locations, edges)
+	return *NewIOAutomaton(NewAutomaton(dg, template.automaton.initial), inputs, outputs)
+}
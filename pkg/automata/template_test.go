@@ -0,0 +1,79 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Instantiate(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	id, count := symbolsMap.Insert("id"), symbolsMap.Insert("count")
+	signal := symbolsMap.Insert("signal")
+	leave := Action(symbolsMap.Insert("leave"))
+
+	clocks := language.NewClocksMap(reference)
+	clocks.Declare(x)
+	locals := language.NewVariablesMap()
+	locals.Declare(id, language.IntegerSort)
+	locals.Declare(count, language.IntegerSort)
+
+	builder := NewIOAutomatonBuilder()
+	builder.AddInputs(Action(signal))
+	builder.AddOutputs(leave)
+	far := builder.AddInitial("far")
+	near := builder.AddLocation("near", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(5, zones.Weak))),
+	))
+	builder.AddEdge(far, Action(signal), near,
+		WithGuard(NewGuard(
+			language.NewBinary(language.NewVariable(count), language.LessThan, language.NewVariable(id)),
+		)),
+		WithUpdate(NewUpdate(language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)))),
+	)
+	builder.AddEdge(near, leave, far)
+	template := NewTemplate("Train", NewTIOAutomaton(builder.Build(), clocks), locals,
+		NewBoundedParameter(id, 0, 5), NewParameter(signal, ChannelParameter),
+	)
+	approach := Action(symbolsMap.Insert("approach"))
+	variables := language.NewVariablesMap()
+
+	// Act
+	first := template.Instantiate(symbolsMap, variables, language.NewInteger(1), language.NewVariable(symbols.Symbol(approach)))
+	second := template.Instantiate(symbolsMap, variables, language.NewInteger(2), language.NewVariable(symbols.Symbol(approach)))
+
+	// Assert
+	firstX, _ := symbolsMap.Lookup("Train(1, approach).x")
+	secondX, _ := symbolsMap.Lookup("Train(2, approach).x")
+	firstCount, _ := symbolsMap.Lookup("Train(1, approach).count")
+	assert.NotEqual(t, firstX, secondX)
+	_, exists := first.Clocks().Lookup(firstX)
+	assert.True(t, exists)
+	_, exists = second.Clocks().Lookup(firstX)
+	assert.False(t, exists)
+	_, exists = variables.Lookup(firstCount)
+	assert.True(t, exists)
+	_, exists = variables.Lookup(id)
+	assert.False(t, exists)
+
+	assert.Equal(t, []Action{approach}, first.Inputs())
+	assert.Equal(t, []Action{leave}, first.Outputs())
+	edges := first.Outgoing(far, approach)
+	assert.Len(t, edges, 1)
+	assert.Equal(t, language.NewBinary(
+		language.NewVariable(firstCount), language.LessThan, language.NewInteger(1),
+	), edges[0].Guard().Condition())
+	assert.Equal(t, language.NewBlockExpression(
+		language.NewTrue(), language.NewClockReset(firstX, 0),
+	), edges[0].Update().Expression())
+	location, _ := first.Location(near)
+	assert.Equal(t, language.NewClockConstraint(firstX, reference, zones.NewRelation(5, zones.Weak)), location.Invariant().Condition())
+	assert.Panics(t, func() {
+		template.Instantiate(symbolsMap, variables, language.NewInteger(6), language.NewVariable(symbols.Symbol(approach)))
+	})
+}
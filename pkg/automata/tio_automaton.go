@@ -1,18 +1,80 @@
 package automata
 
 import (
+	"github.com/Brandhoej/gobion/pkg/automata/language"
 	"github.com/Brandhoej/gobion/pkg/symbols"
 	"github.com/Brandhoej/gobion/pkg/zones"
 )
 
 type TIOAutomaton struct {
 	IOAutomaton
-	clocks symbols.Store[zones.Clock]
+	clocks language.Clocks
 }
 
-func NewTIOAutomaton(automaton IOAutomaton, clocks symbols.Store[zones.Clock]) *TIOAutomaton {
+func NewTIOAutomaton(automaton IOAutomaton, clocks language.Clocks) *TIOAutomaton {
 	return &TIOAutomaton{
 		IOAutomaton: automaton,
-		clocks: clocks,
+		clocks:      clocks,
 	}
-}
\ No newline at end of file
+}
+
+func (automaton TIOAutomaton) Clocks() language.Clocks {
+	return automaton.clocks
+}
+
+// Returns a transition system which explores the clocks of the automaton by zones.
+func (automaton TIOAutomaton) TransitionSystem(interpreter *Interpreter) *SymbolicTransitionSystem {
+	return NewTimedTransitionSystem(automaton.Symbolic(), automaton.clocks, interpreter)
+}
+
+// Returns the LU-bounds of the clocks in the locations of the automaton.
+func (automaton TIOAutomaton) Bounds() Bounds {
+	return NewBounds(automaton.Symbolic(), automaton.clocks)
+}
+
+// Returns a new mapping of clocks which contains the clocks of both mappings.
+func unionClocks(lhs, rhs language.Clocks) *language.ClocksMap {
+	references := make([]symbols.Symbol, 0, 2)
+	collect := func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock == zones.Reference {
+			references = append(references, symbol)
+		}
+		return true
+	}
+	lhs.All(collect)
+	rhs.All(collect)
+
+	// The clocks are declared in the order of their dimensions such that the union is the same every time.
+	union := language.NewClocksMap(references...)
+	for _, symbol := range append(orderedClocks(lhs), orderedClocks(rhs)...) {
+		union.Declare(symbol)
+	}
+	return union
+}
+
+// Returns the symbols of the clocks except the reference clock ordered by their dimensions.
+func orderedClocks(clocks language.Clocks) []symbols.Symbol {
+	ordered := make([]symbols.Symbol, clocks.Dimensions()-1)
+	clocks.All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock != zones.Reference {
+			ordered[clock-1] = symbol
+		}
+		return true
+	})
+	return ordered
+}
+
+// Returns the symbol of the reference clock.
+func referenceClock(clocks language.Clocks) (reference symbols.Symbol) {
+	found := !clocks.All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock == zones.Reference {
+			reference = symbol
+			return false
+		}
+		return true
+	})
+	if !found {
+		panic("Clocks have no reference clock")
+	}
+	return reference
+}
@@ -1,3 +1,47 @@
 package automata
 
+import "github.com/Brandhoej/gobion/pkg/structures"
+
+// A sequence of states starting from the root of the exploration.
 type Trace []State
+
+// Returns the trace from the root to the state of the node.
+func NewTrace(node structures.LinkedNode[State]) Trace {
+	states := node.Array()
+	trace := make(Trace, len(states))
+	for idx := range states {
+		trace[len(states)-1-idx] = states[idx]
+	}
+	return trace
+}
+
+// Returns the last state of the trace.
+func (trace Trace) Last() State {
+	return trace[len(trace)-1]
+}
+
+// An infinite path where the prefix is followed by the cycle repeated forever.
+// The first state of the cycle is a successor of the last state of the cycle.
+type Lasso struct {
+	prefix, cycle Trace
+}
+
+func NewLasso(prefix, cycle Trace) Lasso {
+	return Lasso{
+		prefix: prefix,
+		cycle:  cycle,
+	}
+}
+
+func (lasso Lasso) Prefix() Trace {
+	return lasso.prefix
+}
+
+func (lasso Lasso) Cycle() Trace {
+	return lasso.cycle
+}
+
+// Returns the prefix followed by a single iteration of the cycle.
+func (lasso Lasso) Trace() Trace {
+	return append(append(Trace{}, lasso.prefix...), lasso.cycle...)
+}
@@ -1,11 +1,55 @@
 package automata
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/internal/z3"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
 
 func Test_Outgoing(t *testing.T) {
 	// Arrange
+	context := z3.NewContext(z3.NewConfig())
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	reference, x := symbolsMap.Insert("0"), symbolsMap.Insert("x")
+	action := Action(symbolsMap.Insert("a"))
+	interpreter := NewInterpreter(context, language.NewVariablesMap())
+
+	clocks := language.NewClocksMap(reference)
+	clock := clocks.Declare(x)
+	builder := NewIOAutomatonBuilder()
+	builder.AddOutputs(action)
+	source := builder.AddInitial("source", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(5, zones.Weak))),
+	))
+	destination := builder.AddLocation("destination", WithInvariant(
+		NewInvariant(language.NewClockConstraint(x, reference, zones.NewRelation(2, zones.Weak))),
+	))
+	// x ≥ 3 and x ≥ 6 where only the first is enabled in the source.
+	builder.AddEdge(source, action, destination,
+		WithGuard(NewGuard(
+			language.NewClockConstraint(reference, x, zones.NewRelation(-3, zones.Weak)),
+		)),
+		WithUpdate(NewUpdate(
+			language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)),
+		)),
+	)
+	builder.AddEdge(source, action, destination, WithGuard(NewGuard(
+		language.NewClockConstraint(reference, x, zones.NewRelation(-6, zones.Weak)),
+	)))
+	system := NewTIOAutomaton(builder.Build(), clocks).TransitionSystem(interpreter)
 
 	// Act
+	initial := system.Initial(language.NewValuationsMap())
+	successors := system.Outgoing(initial)
 
 	// Assert
+	assert.Equal(t, zones.NewRelation(5, zones.Weak), initial.Zone().Upper(clock))
+	assert.Len(t, successors, 1)
+	assert.Equal(t, destination, successors[0].Location())
+	assert.Equal(t, zones.NewRelation(2, zones.Weak), successors[0].Zone().Upper(clock))
+	assert.Equal(t, zones.Zero, successors[0].Zone().Lower(clock))
 }
@@ -0,0 +1,88 @@
+package automata
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// A type error of the invariant of a location or the guard or update of an edge.
+type TypeError struct {
+	location symbols.Symbol
+	edge     *Edge
+	label    string
+	err      language.TypeError
+}
+
+// Returns the location of the invariant or the source of the edge.
+func (err TypeError) Location() symbols.Symbol {
+	return err.location
+}
+
+// Returns the edge of the guard or update if the error is not of an invariant.
+func (err TypeError) Edge() (Edge, bool) {
+	if err.edge == nil {
+		return Edge{}, false
+	}
+	return *err.edge, true
+}
+
+func (err TypeError) Unwrap() error {
+	return err.err
+}
+
+func (err TypeError) Error() string {
+	return fmt.Sprintf("%s: %s", err.label, err.err)
+}
+
+// Returns the type errors of all invariants, guards and updates of the automaton such that they can be reported
+// before it is explored. The invariants and guards must be booleans and the updates are checked as statements.
+func (automaton SymbolicAutomaton) TypeErrors(
+	store symbols.Store[any], variables language.Variables, clocks language.Clocks,
+) (errors []TypeError) {
+	checker := language.NewTypeChecker(store, variables, clocks)
+	collect := func(location symbols.Symbol, edge *Edge, label string) {
+		for _, err := range checker.Errors() {
+			errors = append(errors, TypeError{
+				location: location,
+				edge:     edge,
+				label:    label,
+				err:      err,
+			})
+		}
+	}
+
+	var keys []symbols.Symbol
+	automaton.Locations(func(key symbols.Symbol, _ Location) bool {
+		keys = append(keys, key)
+		return true
+	})
+	slices.Sort(keys)
+
+	name := func(key symbols.Symbol) string {
+		location, _ := automaton.Location(key)
+		return location.name
+	}
+	for _, key := range keys {
+		location, _ := automaton.Location(key)
+		checker.Condition(location.invariant.condition)
+		collect(key, nil, fmt.Sprintf("the invariant of \"%s\"", location.name))
+
+		for _, edge := range automaton.Outgoing(key) {
+			edge := edge
+			from := fmt.Sprintf("the edge from \"%s\" to \"%s\"", name(edge.source), name(edge.destination))
+			checker.Condition(edge.guard.condition)
+			collect(key, &edge, fmt.Sprintf("the guard of %s", from))
+			checker.Expression(edge.update.expression)
+			collect(key, &edge, fmt.Sprintf("the update of %s", from))
+		}
+	}
+	return errors
+}
+
+// Returns the type errors of the automaton where its clocks are those of the automaton.
+func (automaton TIOAutomaton) TypeErrors(store symbols.Store[any], variables language.Variables) []TypeError {
+	return automaton.Symbolic().TypeErrors(store, variables, automaton.clocks)
+}
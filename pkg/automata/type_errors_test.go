@@ -0,0 +1,57 @@
+package automata
+
+import (
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TypeErrors(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	i, b, x := symbolsMap.Insert("i"), symbolsMap.Insert("b"), symbolsMap.Insert("x")
+	variables := language.NewVariablesMap()
+	variables.Declare(i, language.IntegerSort)
+	variables.Declare(b, language.BooleanSort)
+	clocks := language.NewClocksMap(symbolsMap.Insert("0"))
+	clocks.Declare(x)
+
+	builder := NewAutomatonBuilder()
+	idle := builder.AddInitial("idle", WithInvariant(NewInvariant(language.NewVariable(i))))
+	busy := builder.AddLocation("busy")
+	builder.AddEdge(idle, busy,
+		WithGuard(NewGuard(language.NewBinary(language.NewVariable(b), language.LessThan, language.NewInteger(3)))),
+		WithUpdate(NewUpdate(language.NewBlockExpression(
+			language.NewTrue(),
+			language.NewAssignment(language.NewVariable(i), language.NewVariable(b)),
+			language.NewClockReset(x, 0),
+		))),
+	)
+	builder.AddEdge(busy, idle,
+		WithGuard(NewGuard(language.NewBinary(language.NewVariable(x), language.LessThan, language.NewInteger(3)))),
+	)
+	automaton := builder.Build()
+
+	// Act
+	errors := automaton.TypeErrors(symbolsMap, variables, clocks)
+
+	// Assert
+	messages := make([]string, len(errors))
+	for idx := range errors {
+		messages[idx] = errors[idx].Error()
+	}
+	assert.Equal(t, []string{
+		"the invariant of \"idle\": the condition \"i\" must be of the sort bool",
+		"the guard of the edge from \"idle\" to \"busy\": the operands of \"b < 3\" must be of the sort int",
+		"the update of the edge from \"idle\" to \"busy\": \"i\" of the sort int cannot be assigned \"b\" of the sort bool",
+		"the guard of the edge from \"busy\" to \"idle\": the clock \"x\" can only be used in clock constraints",
+	}, messages)
+	assert.Equal(t, idle, errors[0].Location())
+	_, isEdge := errors[0].Edge()
+	assert.False(t, isEdge)
+	edge, isEdge := errors[3].Edge()
+	assert.True(t, isEdge)
+	assert.Equal(t, busy, edge.Source())
+}
@@ -17,12 +17,26 @@ func NewUpdate(expression language.Expression) Update {
 	}
 }
 
+func (update Update) Expression() language.Expression {
+	return update.expression
+}
+
 func NewEmptyUpdate() Update {
 	return Update{
 		expression: language.NewTrue(),
 	}
 }
 
+// Returns the update which applies all the updates from left to right.
+func (update Update) Conjunction(updates ...Update) Update {
+	expressions := make([]language.Expression, len(updates))
+	for idx := range updates {
+		expressions[idx] = updates[idx].expression
+	}
+	conjunction := language.Conjunction(update.expression, expressions...)
+	return NewUpdate(conjunction)
+}
+
 func (update Update) Apply(expression language.Expression) language.Expression {
 	return language.Conjunction(update.expression, expression)
 }
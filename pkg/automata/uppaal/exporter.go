@@ -0,0 +1,234 @@
+package uppaal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+)
+
+const doctype = "<!DOCTYPE nta PUBLIC '-//Uppaal Team//DTD Flat System 1.1//EN' " +
+	"'http://www.it.uu.se/research/group/darts/uppaal/flat-1_2.dtd'>\n"
+
+// Constructs a model without processes which can be added before it is exported.
+func NewModel(store symbols.Store[any], variables *language.VariablesMap, valuations *language.ValuationsMap) *Model {
+	origin := store.Insert("0")
+	return &Model{
+		symbols:    store,
+		variables:  variables,
+		valuations: valuations,
+		functions:  language.NewFunctionsMap(),
+		constants:  language.NewValuationsMap(),
+		clocks:     language.NewClocksMap(origin),
+		reference:  origin,
+		silent:     map[automata.Action]bool{},
+	}
+}
+
+// Adds the function to the global declarations of the model such that it can be called by the processes.
+func (model *Model) AddFunction(function language.Function) {
+	model.functions.Declare(function)
+}
+
+// Adds the automaton as a process of the model. All actions of the automaton are channels.
+func (model *Model) AddProcess(name string, automaton *automata.TIOAutomaton) {
+	automaton.Clocks().All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock != zones.Reference {
+			model.clocks.Declare(symbol)
+		}
+		return true
+	})
+	for _, action := range automaton.Actions() {
+		if !model.silent[action] && !slices.Contains(model.channels, action) {
+			model.channels = append(model.channels, action)
+		}
+	}
+	model.processes = append(model.processes, Process{
+		name:      name,
+		automaton: automaton,
+	})
+}
+
+// Writes the model in the UPPAAL XML format. All channels, clocks, variables and functions are declared globally
+// and the processes are written as templates without parameters. Names are written as UPPAAL identifiers.
+func (model *Model) Export(writer io.Writer) error {
+	document := nta{
+		Declaration: model.declaration(),
+	}
+
+	names := make([]string, len(model.processes))
+	for idx, process := range model.processes {
+		document.Templates = append(document.Templates, model.template(process))
+		names[idx] = language.Identifier(process.name)
+	}
+	document.System = fmt.Sprintf("system %s;", strings.Join(names, ", "))
+
+	if _, err := io.WriteString(writer, xml.Header+doctype); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "\t")
+	return encoder.Encode(document)
+}
+
+func (model *Model) identifier(symbol symbols.Symbol) string {
+	name, _ := model.symbols.Item(symbol)
+	return language.Identifier(fmt.Sprint(name))
+}
+
+func (model *Model) declaration() string {
+	var buffer bytes.Buffer
+
+	channels := make([]string, 0, len(model.channels))
+	for _, channel := range model.channels {
+		channels = append(channels, model.identifier(symbols.Symbol(channel)))
+	}
+	if len(channels) > 0 {
+		fmt.Fprintf(&buffer, "chan %s;\n", strings.Join(channels, ", "))
+	}
+
+	clocks := make([]string, model.clocks.Dimensions()-1)
+	model.clocks.All(func(symbol symbols.Symbol, clock zones.Clock) bool {
+		if clock != zones.Reference {
+			clocks[clock-1] = model.identifier(symbol)
+		}
+		return true
+	})
+	if len(clocks) > 0 {
+		fmt.Fprintf(&buffer, "clock %s;\n", strings.Join(clocks, ", "))
+	}
+
+	// The variables are declared in the order of their symbols such that the export is deterministic.
+	variables := make([]symbols.Symbol, 0)
+	model.variables.All(func(symbol symbols.Symbol, sort language.Sort) bool {
+		variables = append(variables, symbol)
+		return true
+	})
+	slices.Sort(variables)
+	for _, symbol := range variables {
+		sort, _ := model.variables.Lookup(symbol)
+		buffer.WriteString(sort.Declaration(model.identifier(symbol)))
+		if value, exists := model.valuations.Value(symbol); exists {
+			fmt.Fprintf(&buffer, " = %s", model.print(value, nil))
+		}
+		buffer.WriteString(";\n")
+	}
+
+	// The functions are declared after the variables they can read and in the order of their symbols
+	// such that functions are declared before the functions calling them.
+	functions := make([]language.Function, 0)
+	model.functions.All(func(function language.Function) bool {
+		functions = append(functions, function)
+		return true
+	})
+	slices.SortFunc(functions, func(lhs, rhs language.Function) int {
+		return int(lhs.Symbol()) - int(rhs.Symbol())
+	})
+	for _, function := range functions {
+		language.NewUPPAALPrinter(&buffer, model.symbols, nil).Function(function)
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
+
+// Writes the expression as an UPPAAL label.
+func (model *Model) print(expression language.Expression, clocks language.Clocks) string {
+	var buffer bytes.Buffer
+	expression.Accept(language.NewUPPAALPrinter(&buffer, model.symbols, clocks))
+	return buffer.String()
+}
+
+func (model *Model) update(expression language.Expression, clocks language.Clocks) string {
+	var buffer bytes.Buffer
+	language.NewUPPAALPrinter(&buffer, model.symbols, clocks).Update(expression)
+	return buffer.String()
+}
+
+func isTrue(expression language.Expression) bool {
+	boolean, ok := expression.(language.Boolean)
+	return ok && boolean.Value()
+}
+
+func (model *Model) template(process Process) template {
+	automaton := process.automaton
+	clocks := automaton.Clocks()
+	exported := template{
+		Name: language.Identifier(process.name),
+	}
+
+	// The locations are written in the order of their keys such that the export is deterministic.
+	keys := make([]symbols.Symbol, 0)
+	automaton.Locations(func(key symbols.Symbol, _ automata.Location) bool {
+		keys = append(keys, key)
+		return true
+	})
+	slices.Sort(keys)
+
+	ids, names := map[symbols.Symbol]string{}, map[string]bool{}
+	for idx, key := range keys {
+		location, _ := automaton.Location(key)
+		ids[key] = fmt.Sprintf("id%v", idx)
+
+		// Location names must be unique identifiers in the template.
+		name := language.Identifier(location.Name())
+		for suffix := 1; names[name]; suffix++ {
+			name = fmt.Sprintf("%s_%v", language.Identifier(location.Name()), suffix)
+		}
+		names[name] = true
+
+		exported.Locations = append(exported.Locations, model.location(process, key, ids[key], name, location, clocks))
+	}
+	exported.Init = reference{Ref: ids[automaton.Initial()]}
+
+	automaton.Edges(func(edge automata.IOEdge) bool {
+		transition := transition{
+			Source: reference{Ref: ids[edge.Source()]},
+			Target: reference{Ref: ids[edge.Destination()]},
+		}
+		if guard := edge.Guard().Condition(); !isTrue(guard) {
+			transition.Labels = append(transition.Labels, label{"guard", model.print(guard, clocks)})
+		}
+		if !model.silent[edge.Action()] {
+			direction := "!"
+			if automaton.IsInput(edge.Action()) {
+				direction = "?"
+			}
+			sync := model.identifier(symbols.Symbol(edge.Action())) + direction
+			transition.Labels = append(transition.Labels, label{"synchronisation", sync})
+		}
+		if update := model.update(edge.Update().Expression(), clocks); update != "" {
+			transition.Labels = append(transition.Labels, label{"assignment", update})
+		}
+		exported.Transitions = append(exported.Transitions, transition)
+		return true
+	})
+
+	return exported
+}
+
+func (model *Model) location(
+	process Process, key symbols.Symbol, id, name string, state automata.Location, clocks language.Clocks,
+) location {
+	exported := location{
+		ID:   id,
+		Name: name,
+	}
+	if invariant := state.Invariant().Condition(); !isTrue(invariant) {
+		exported.Labels = append(exported.Labels, label{"invariant", model.print(invariant, clocks)})
+	}
+	if slices.Contains(process.urgent, key) {
+		exported.Urgent = &struct{}{}
+	}
+	if slices.Contains(process.committed, key) {
+		exported.Committed = &struct{}{}
+	}
+	return exported
+}
@@ -0,0 +1,86 @@
+package uppaal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportRoundTrip(t *testing.T) {
+	// Arrange
+	imported, err := Import(strings.NewReader(lamp), symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory()))
+	assert.NoError(t, err)
+	var buffer bytes.Buffer
+
+	// Act
+	err = imported.Export(&buffer)
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	model, reimportErr := Import(&buffer, symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, reimportErr)
+	assert.Len(t, model.Channels(), 1)
+	assert.Len(t, model.Processes(), 2)
+
+	lamp := model.Processes()[0]
+	assert.Equal(t, "lamp", lamp.Name())
+	assert.Len(t, lamp.Committed(), 1)
+	automaton := lamp.Automaton()
+	assert.Len(t, automaton.Inputs(), 1)
+	assert.Len(t, automaton.Outputs(), 1)
+
+	edges := automaton.Outgoing(automaton.Initial(), automaton.Inputs()[0])
+	assert.Len(t, edges, 1)
+	assert.Equal(t, "lamp_y := 0; lamp_on' := true", edges[0].Update().String(symbolsMap))
+
+	presses, _ := symbolsMap.Lookup("presses")
+	value, _ := model.Valuations().Value(presses)
+	assert.Equal(t, language.NewInteger(0), value)
+}
+
+func Test_Export(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+	x := symbolsMap.Insert("x")
+	coin := automata.Action(symbolsMap.Insert("coin"))
+	tea := automata.Action(symbolsMap.Insert("tea"))
+	model := NewModel(symbolsMap, language.NewVariablesMap(), language.NewValuationsMap())
+	clocks := language.NewClocksMap(model.reference)
+	clocks.Declare(x)
+
+	builder := automata.NewIOAutomatonBuilder()
+	idle := builder.AddInitial("idle")
+	busy := builder.AddLocation("busy", automata.WithInvariant(automata.NewInvariant(
+		language.NewClockConstraint(x, model.reference, zones.NewRelation(2, zones.Weak)),
+	)))
+	builder.AddInputs(coin)
+	builder.AddOutputs(tea)
+	builder.AddEdge(idle, coin, busy, automata.WithUpdate(automata.NewUpdate(
+		language.NewBlockExpression(language.NewTrue(), language.NewClockReset(x, 0)),
+	)))
+	builder.AddEdge(busy, tea, idle, automata.WithGuard(automata.NewGuard(
+		language.NewClockConstraint(model.reference, x, zones.NewRelation(-1, zones.Weak)),
+	)))
+	model.AddProcess("Machine", automata.NewTIOAutomaton(builder.Build(), clocks))
+	var buffer bytes.Buffer
+
+	// Act
+	err := model.Export(&buffer)
+
+	// Assert
+	assert.NoError(t, err)
+	exported := buffer.String()
+	assert.Contains(t, exported, "chan coin, tea;&#xA;clock x;&#xA;")
+	assert.Contains(t, exported, `<label kind="invariant">x &lt;= 2</label>`)
+	assert.Contains(t, exported, `<label kind="guard">x &gt;= 1</label>`)
+	assert.Contains(t, exported, `<label kind="synchronisation">coin?</label>`)
+	assert.Contains(t, exported, `<label kind="assignment">x = 0</label>`)
+	assert.Contains(t, exported, "<system>system Machine;</system>")
+}
@@ -0,0 +1,351 @@
+package uppaal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+// The XML format of UPPAAL models (flat-1.x).
+type nta struct {
+	XMLName     xml.Name   `xml:"nta"`
+	Declaration string     `xml:"declaration"`
+	Templates   []template `xml:"template"`
+	System      string     `xml:"system"`
+}
+
+type template struct {
+	Name        string       `xml:"name"`
+	Parameter   string       `xml:"parameter,omitempty"`
+	Declaration string       `xml:"declaration,omitempty"`
+	Locations   []location   `xml:"location"`
+	Init        reference    `xml:"init"`
+	Transitions []transition `xml:"transition"`
+}
+
+type location struct {
+	ID        string    `xml:"id,attr"`
+	Name      string    `xml:"name,omitempty"`
+	Labels    []label   `xml:"label"`
+	Urgent    *struct{} `xml:"urgent"`
+	Committed *struct{} `xml:"committed"`
+}
+
+type reference struct {
+	Ref string `xml:"ref,attr"`
+}
+
+type transition struct {
+	Source reference `xml:"source"`
+	Target reference `xml:"target"`
+	Labels []label   `xml:"label"`
+}
+
+type label struct {
+	Kind string `xml:"kind,attr"`
+	Text string `xml:",chardata"`
+}
+
+// A process of the system which is an instance of a template.
+type process struct {
+	name, template string
+	position       language.Position
+}
+
+// A process of the imported network.
+type Process struct {
+	name      string
+	automaton *automata.TIOAutomaton
+	urgent    []symbols.Symbol
+	committed []symbols.Symbol
+}
+
+func (process Process) Name() string {
+	return process.name
+}
+
+func (process Process) Automaton() *automata.TIOAutomaton {
+	return process.automaton
+}
+
+// Returns the locations of the process where time cannot pass.
+func (process Process) Urgent() []symbols.Symbol {
+	return process.urgent
+}
+
+// Returns the locations of the process where time cannot pass and
+// which must be left before any process in a non-committed location moves.
+func (process Process) Committed() []symbols.Symbol {
+	return process.committed
+}
+
+// A network of timed automata imported from an UPPAAL model. The variables and clocks declared
+// in a template are local to each process and are registered as symbols qualified by the process "P.x".
+type Model struct {
+	symbols    symbols.Store[any]
+	variables  *language.VariablesMap
+	valuations *language.ValuationsMap
+	functions  *language.FunctionsMap
+	// The values of the constants which replace them in expressions.
+	constants *language.ValuationsMap
+	clocks    *language.ClocksMap
+	reference symbols.Symbol
+	channels  []automata.Action
+	silent    map[automata.Action]bool
+	processes []Process
+}
+
+func (model *Model) Symbols() symbols.Store[any] {
+	return model.symbols
+}
+
+// Returns the sorts of all variables of the model.
+func (model *Model) Variables() *language.VariablesMap {
+	return model.variables
+}
+
+// Returns the initial values of all variables of the model.
+func (model *Model) Valuations() *language.ValuationsMap {
+	return model.valuations
+}
+
+// Returns the functions of the global declarations and the declarations of the processes.
+func (model *Model) Functions() *language.FunctionsMap {
+	return model.functions
+}
+
+// Returns all clocks of the model including the reference clock.
+func (model *Model) Clocks() *language.ClocksMap {
+	return model.clocks
+}
+
+// Returns the actions of the declared channels.
+func (model *Model) Channels() []automata.Action {
+	return model.channels
+}
+
+func (model *Model) Processes() []Process {
+	return model.processes
+}
+
+// Reads an UPPAAL XML model and imports the processes of its system declaration as timed I/O automata.
+// A synchronisation "c!" is an output and "c?" is an input of the channel. Transitions without a
+// synchronisation are outputs of a silent action "P.τ" of the process.
+func Import(reader io.Reader, store symbols.Store[any]) (*Model, error) {
+	var document nta
+	if err := xml.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	model := &Model{
+		symbols:    store,
+		variables:  language.NewVariablesMap(),
+		valuations: language.NewValuationsMap(),
+		functions:  language.NewFunctionsMap(),
+		constants:  language.NewValuationsMap(),
+		reference:  store.Insert("0"),
+		silent:     map[automata.Action]bool{},
+	}
+	model.clocks = language.NewClocksMap(model.reference)
+
+	global := newScope(nil, "")
+	if err := model.parse(global, document.Declaration, (*parser).declarations); err != nil {
+		return nil, fmt.Errorf("global declaration: %w", err)
+	}
+
+	var processes []process
+	if err := model.parse(global, document.System, func(parser *parser) {
+		processes = parser.system()
+	}); err != nil {
+		return nil, fmt.Errorf("system declaration: %w", err)
+	}
+
+	templates := map[string]template{}
+	for _, template := range document.Templates {
+		templates[template.Name] = template
+	}
+	for _, process := range processes {
+		template, exists := templates[process.template]
+		if !exists {
+			return nil, fmt.Errorf("system declaration: %s: unknown template \"%s\"", process.position, process.template)
+		}
+		imported, err := model.process(global, process.name, template)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", template.Name, err)
+		}
+		model.processes = append(model.processes, imported)
+	}
+
+	return model, nil
+}
+
+// Registers the symbol of the declaration and its sort, clock or initial value in the model.
+func (model *Model) declare(
+	scope *scope, name string, kind declarationKind, sort language.Sort, initial language.Expression,
+) {
+	qualified := scope.qualify(name)
+
+	declaration := declaration{
+		kind: kind,
+		sort: sort,
+	}
+	switch kind {
+	case constantDeclaration:
+		declaration.symbol = model.symbols.Insert(qualified)
+		model.constants.Assign(declaration.symbol, initial)
+	case clockDeclaration:
+		declaration.symbol = model.symbols.Insert(qualified)
+		model.clocks.Declare(declaration.symbol)
+	case channelDeclaration:
+		declaration.symbol = model.symbols.Insert(qualified)
+		model.channels = append(model.channels, automata.Action(declaration.symbol))
+	case localDeclaration:
+		declaration.symbol = model.symbols.Insert(qualified)
+	case variableDeclaration:
+		declaration.symbol = model.symbols.Insert(qualified)
+		model.variables.Declare(declaration.symbol, sort)
+		if initial == nil {
+			initial = sort.Zero()
+		}
+		model.valuations.Assign(declaration.symbol, initial)
+	}
+	scope.declarations[name] = declaration
+	scope.names = append(scope.names, name)
+}
+
+func (model *Model) process(global *scope, name string, template template) (Process, error) {
+	if template.Parameter != "" {
+		return Process{}, fmt.Errorf("parameterised templates are not supported")
+	}
+
+	local := newScope(global, name)
+	if err := model.parse(local, template.Declaration, (*parser).declarations); err != nil {
+		return Process{}, fmt.Errorf("declaration: %w", err)
+	}
+
+	// The clocks of the process are the global clocks and its own.
+	clocks := language.NewClocksMap(model.reference)
+	for _, current := range []*scope{global, local} {
+		for _, name := range current.names {
+			if declaration := current.declarations[name]; declaration.kind == clockDeclaration {
+				clocks.Declare(declaration.symbol)
+			}
+		}
+	}
+
+	imported := Process{name: name}
+	builder := automata.NewIOAutomatonBuilder()
+	keys := map[string]symbols.Symbol{}
+	for _, location := range template.Locations {
+		invariant := language.Expression(language.NewTrue())
+		for _, label := range location.Labels {
+			if label.Kind != "invariant" {
+				continue
+			}
+			var err error
+			if invariant, err = model.expression(local, label.Text); err != nil {
+				return Process{}, fmt.Errorf("invariant of %s: %w", location.ID, err)
+			}
+		}
+
+		locationName := location.Name
+		if locationName == "" {
+			locationName = location.ID
+		}
+		key := builder.AddLocation(locationName, automata.WithInvariant(automata.NewInvariant(invariant)))
+		keys[location.ID] = key
+		if location.Urgent != nil {
+			imported.urgent = append(imported.urgent, key)
+		}
+		if location.Committed != nil {
+			imported.committed = append(imported.committed, key)
+		}
+	}
+
+	initial, exists := keys[template.Init.Ref]
+	if !exists {
+		return Process{}, fmt.Errorf("unknown initial location \"%s\"", template.Init.Ref)
+	}
+	builder.SetInitial(initial)
+
+	silent := automata.Action(model.symbols.Insert(fmt.Sprintf("%s.τ", name)))
+	model.silent[silent] = true
+	inputs, outputs := map[automata.Action]bool{}, map[automata.Action]bool{}
+	for _, transition := range template.Transitions {
+		source, sourceExists := keys[transition.Source.Ref]
+		destination, destinationExists := keys[transition.Target.Ref]
+		if !sourceExists || !destinationExists {
+			return Process{}, fmt.Errorf(
+				"transition from \"%s\" to \"%s\" has an unknown location", transition.Source.Ref, transition.Target.Ref,
+			)
+		}
+
+		action, input := silent, false
+		guard, update := language.Expression(language.NewTrue()), language.Expression(language.NewTrue())
+		for _, label := range transition.Labels {
+			var err error
+			switch label.Kind {
+			case "guard":
+				guard, err = model.expression(local, label.Text)
+			case "synchronisation":
+				err = model.parse(local, label.Text, func(parser *parser) {
+					var channel symbols.Symbol
+					channel, input = parser.synchronisation()
+					action = automata.Action(channel)
+				})
+			case "assignment":
+				err = model.parse(local, label.Text, func(parser *parser) {
+					update = parser.updates()
+				})
+			}
+			if err != nil {
+				return Process{}, fmt.Errorf("%s of transition from %s: %w", label.Kind, transition.Source.Ref, err)
+			}
+		}
+
+		if input {
+			inputs[action] = true
+		} else {
+			outputs[action] = true
+		}
+		if inputs[action] && outputs[action] {
+			name, _ := model.symbols.Item(symbols.Symbol(action))
+			return Process{}, fmt.Errorf("the channel \"%v\" is both an input and an output", name)
+		}
+
+		builder.AddEdge(
+			source, action, destination,
+			automata.WithGuard(automata.NewGuard(guard)),
+			automata.WithUpdate(automata.NewUpdate(update)),
+		)
+	}
+
+	// The actions are added in the order of the declared channels such that the import is deterministic.
+	actions := make([]automata.Action, 0, len(model.channels)+1)
+	actions = append(actions, model.channels...)
+	for _, action := range append(actions, silent) {
+		if inputs[action] {
+			builder.AddInputs(action)
+		} else if outputs[action] {
+			builder.AddOutputs(action)
+		}
+	}
+
+	imported.automaton = automata.NewTIOAutomaton(builder.Build(), clocks)
+	return imported, nil
+}
+
+// Parses the label as a guard or invariant in the scope whose clock constraints must be conjunctive.
+func (model *Model) expression(scope *scope, text string) (expression language.Expression, err error) {
+	err = model.parse(scope, text, func(parser *parser) {
+		expression = parser.Expression()
+	})
+	if err == nil && !language.IsConvex(expression) {
+		err = fmt.Errorf("disjunctions of clock constraints are not supported")
+	}
+	return expression, err
+}
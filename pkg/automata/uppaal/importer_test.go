@@ -0,0 +1,367 @@
+package uppaal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Brandhoej/gobion/pkg/automata"
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+	"github.com/Brandhoej/gobion/pkg/zones"
+	"github.com/stretchr/testify/assert"
+)
+
+const lamp = `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE nta PUBLIC '-//Uppaal Team//DTD Flat System 1.1//EN' 'http://www.it.uu.se/research/group/darts/uppaal/flat-1_2.dtd'>
+<nta>
+	<declaration>// Global declarations.
+chan press;
+const int BRIGHT = 5;
+int presses = 0;</declaration>
+	<template>
+		<name>Lamp</name>
+		<declaration>clock y; bool on;</declaration>
+		<location id="id0"><name>off</name></location>
+		<location id="id1"><name>low</name><label kind="invariant">y &lt;= BRIGHT</label></location>
+		<location id="id2"><name>bright</name><committed/></location>
+		<init ref="id0"/>
+		<transition>
+			<source ref="id0"/><target ref="id1"/>
+			<label kind="synchronisation">press?</label>
+			<label kind="assignment">y = 0, on = true</label>
+		</transition>
+		<transition>
+			<source ref="id1"/><target ref="id2"/>
+			<label kind="guard">y &lt; BRIGHT</label>
+			<label kind="synchronisation">press?</label>
+		</transition>
+		<transition>
+			<source ref="id2"/><target ref="id0"/>
+		</transition>
+	</template>
+	<template>
+		<name>User</name>
+		<location id="id3"><name>idle</name></location>
+		<init ref="id3"/>
+		<transition>
+			<source ref="id3"/><target ref="id3"/>
+			<label kind="guard">presses &lt; 10</label>
+			<label kind="synchronisation">press!</label>
+			<label kind="assignment">presses++</label>
+		</transition>
+	</template>
+	<system>lamp = Lamp();
+system lamp, User;</system>
+</nta>`
+
+func Test_Import(t *testing.T) {
+	// Arrange
+	symbolsMap := symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory())
+
+	// Act
+	model, err := Import(strings.NewReader(lamp), symbolsMap)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, model.Processes(), 2)
+
+	press, _ := symbolsMap.Lookup("press")
+	presses, _ := symbolsMap.Lookup("presses")
+	y, _ := symbolsMap.Lookup("lamp.y")
+	on, _ := symbolsMap.Lookup("lamp.on")
+	sort, _ := model.Variables().Lookup(on)
+	assert.Equal(t, language.BooleanSort, sort)
+	value, _ := model.Valuations().Value(presses)
+	assert.Equal(t, language.NewInteger(0), value)
+
+	lamp := model.Processes()[0]
+	assert.Equal(t, "lamp", lamp.Name())
+	assert.Len(t, lamp.Committed(), 1)
+	automaton := lamp.Automaton()
+	assert.Equal(t, zones.Clock(2), automaton.Clocks().Dimensions())
+	clock, _ := automaton.Clocks().Lookup(y)
+	assert.Equal(t, zones.Clock(1), clock)
+	assert.Len(t, automaton.Inputs(), 1)
+	assert.Equal(t, automata.Action(press), automaton.Inputs()[0])
+	assert.Len(t, automaton.Outputs(), 1)
+	assert.Equal(t, "lamp.τ", func() any {
+		name, _ := symbolsMap.Item(symbols.Symbol(automaton.Outputs()[0]))
+		return name
+	}())
+
+	edges := automaton.Outgoing(automaton.Initial(), automaton.Inputs()[0])
+	assert.Len(t, edges, 1)
+	assert.Equal(t, "lamp.y := 0; lamp.on' := true", edges[0].Update().String(symbolsMap))
+
+	user := model.Processes()[1].Automaton()
+	assert.Equal(t, automata.Action(press), user.Outputs()[0])
+}
+
+func Test_ImportLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		label    string
+		expected language.Expression
+	}{
+		{
+			name:     "Upper bound",
+			label:    "x <= 3",
+			expected: language.NewClockConstraint(1, 0, zones.NewRelation(3, zones.Weak)),
+		},
+		{
+			name:     "Lower bound",
+			label:    "x > 3",
+			expected: language.NewClockConstraint(0, 1, zones.NewRelation(-3, zones.Strict)),
+		},
+		{
+			name:     "Reversed lower bound",
+			label:    "N <= x",
+			expected: language.NewClockConstraint(0, 1, zones.NewRelation(-5, zones.Weak)),
+		},
+		{
+			name:     "Difference constraint",
+			label:    "x - z < N - 1",
+			expected: language.NewClockConstraint(1, 2, zones.NewRelation(4, zones.Strict)),
+		},
+		{
+			name:  "Data and clocks",
+			label: "i != 2 && !(x >= 1)",
+			expected: language.NewBinary(
+				language.NewBinary(language.NewVariable(3), language.NotEqual, language.NewInteger(2)),
+				language.LogicalAnd,
+				language.LogicalNegate(language.NewClockConstraint(0, 1, zones.NewRelation(-1, zones.Weak))),
+			),
+		},
+		{
+			name:  "Arithmetic",
+			label: "i * 2 % N <? -i >> 1 == (i & ~1)",
+			expected: language.NewBinary(
+				language.NewBinary(
+					language.NewBinary(
+						language.NewBinary(language.NewVariable(3), language.Multiplication, language.NewInteger(2)),
+						language.Modulus,
+						language.NewInteger(5),
+					),
+					language.Minimum,
+					language.NewBinary(language.Negate(language.NewVariable(3)), language.RightShift, language.NewInteger(1)),
+				),
+				language.Equal,
+				language.NewBinary(
+					language.NewVariable(3),
+					language.BitwiseAnd,
+					language.NewUnary(language.BitwiseNegation, language.NewInteger(1)),
+				),
+			),
+		},
+		{
+			name:     "Constant arithmetic",
+			label:    "x <= N * 2 / 3",
+			expected: language.NewClockConstraint(1, 0, zones.NewRelation(3, zones.Weak)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			model := &Model{
+				symbols:    symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory()),
+				variables:  language.NewVariablesMap(),
+				valuations: language.NewValuationsMap(),
+				constants:  language.NewValuationsMap(),
+			}
+			model.reference = model.symbols.Insert("0")
+			model.clocks = language.NewClocksMap(model.reference)
+			scope := newScope(nil, "")
+			assert.NoError(t, model.parse(scope, "clock x, z; int i; const int N = 5;", (*parser).declarations))
+
+			// Act
+			expression, err := model.expression(scope, tt.label)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expression)
+		})
+	}
+}
+
+func Test_ImportErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		label    string
+		expected string
+	}{
+		{
+			name:     "Undeclared identifier",
+			label:    "x <= 3 && k",
+			expected: "1:11: undeclared identifier \"k\"",
+		},
+		{
+			name:     "Clock multiplied",
+			label:    "x + x <= 3",
+			expected: "1:1: clock constraints must be of the form \"x - y ~ n\"",
+		},
+		{
+			name:     "Missing parenthesis",
+			label:    "(x <= 3",
+			expected: "1:8: expected \")\" but found end of input",
+		},
+		{
+			name:     "Clock argument",
+			label:    "f(x) > 0",
+			expected: "1:3: clocks cannot be passed to functions",
+		},
+		{
+			name:     "Missing argument",
+			label:    "f() > 0",
+			expected: "1:1: \"f\" takes 1 arguments but is given 0",
+		},
+		{
+			name:     "Disjunctive clock constraints",
+			label:    "x < 1 || x > 2",
+			expected: "disjunctions of clock constraints are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			model := &Model{
+				symbols:    symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory()),
+				variables:  language.NewVariablesMap(),
+				valuations: language.NewValuationsMap(),
+				constants:  language.NewValuationsMap(),
+				functions:  language.NewFunctionsMap(),
+			}
+			model.reference = model.symbols.Insert("0")
+			model.clocks = language.NewClocksMap(model.reference)
+			scope := newScope(nil, "")
+			assert.NoError(t, model.parse(scope, "clock x; int f(int n) { return n; }", (*parser).declarations))
+
+			// Act
+			_, err := model.expression(scope, tt.label)
+
+			// Assert
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func Test_ImportAggregates(t *testing.T) {
+	// Arrange
+	model := &Model{
+		symbols:    symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory()),
+		variables:  language.NewVariablesMap(),
+		valuations: language.NewValuationsMap(),
+		constants:  language.NewValuationsMap(),
+	}
+	model.reference = model.symbols.Insert("0")
+	model.clocks = language.NewClocksMap(model.reference)
+	scope := newScope(nil, "")
+	declarations := "const int N = 2; int[0,3] a[N] = {1, 2}; struct { int x; bool b; } r = {1, true}, s;"
+
+	// Act
+	err := model.parse(scope, declarations, (*parser).declarations)
+	var update language.Statement
+	updateErr := model.parse(scope, "a[r.x] += 1", func(parser *parser) {
+		update = parser.update()
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, updateErr)
+	a, _ := model.symbols.Lookup("a")
+	r, _ := model.symbols.Lookup("r")
+	s, _ := model.symbols.Lookup("s")
+	record, _ := model.variables.Lookup(r)
+
+	value, _ := model.valuations.Value(a)
+	assert.Equal(t, language.NewArray(language.NewInteger(1), language.NewInteger(2)), value)
+	value, _ = model.valuations.Value(r)
+	assert.Equal(t, language.NewRecord(record, language.NewInteger(1), language.NewTrue()), value)
+	value, _ = model.valuations.Value(s)
+	assert.Equal(t, language.NewRecord(record, language.NewInteger(0), language.NewFalse()), value)
+
+	element := language.NewIndex(language.NewVariable(a), language.NewMember(language.NewVariable(r), "x"))
+	assert.Equal(t, language.NewAssignment(
+		element, language.NewBinary(element, language.Addition, language.NewInteger(1)),
+	), update)
+}
+
+func Test_ImportFunctions(t *testing.T) {
+	// Arrange
+	model := &Model{
+		symbols:    symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory()),
+		variables:  language.NewVariablesMap(),
+		valuations: language.NewValuationsMap(),
+		constants:  language.NewValuationsMap(),
+		functions:  language.NewFunctionsMap(),
+	}
+	model.reference = model.symbols.Insert("0")
+	model.clocks = language.NewClocksMap(model.reference)
+	scope := newScope(nil, "")
+	declarations := `int y = 2;
+int f(int n) {
+	int s = 0;
+	while (n > 0) { s += y; n--; }
+	if (s > 5) return 5; else { return s; }
+}
+bool g() { return f(1) == y; }`
+
+	// Act
+	err := model.parse(scope, declarations, (*parser).declarations)
+	guard, guardErr := model.expression(scope, "g() && f(y) < 5")
+	_, localErr := model.expression(scope, "n > 0")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, guardErr)
+	assert.EqualError(t, localErr, "1:1: undeclared identifier \"n\"")
+	concrete := language.NewConcreteInterpreter(model.variables, model.valuations)
+	assert.Equal(t, language.NewTrue(), concrete.Interpret(guard))
+
+	f, _ := model.symbols.Lookup("f")
+	function, exists := model.functions.Lookup(f)
+	assert.True(t, exists)
+	assert.Equal(t, language.NewInteger(5), concrete.Interpret(language.NewCall(function, language.NewInteger(3))))
+	assert.Equal(t,
+		"int y = 2;\n"+
+			"int f(int f_n) { int f_s = 0; while (f_n > 0) { f_s = f_s + y; f_n = f_n - 1; } "+
+			"if (f_s > 5) { return 5; } else { return f_s; } }\n"+
+			"bool g() { return f(1) == y; }\n",
+		model.declaration(),
+	)
+}
+
+func Test_ImportLoops(t *testing.T) {
+	// Arrange
+	model := &Model{
+		symbols:    symbols.NewSymbolsMap[any](symbols.NewSymbolsFactory()),
+		variables:  language.NewVariablesMap(),
+		valuations: language.NewValuationsMap(),
+		constants:  language.NewValuationsMap(),
+		functions:  language.NewFunctionsMap(),
+	}
+	model.reference = model.symbols.Insert("0")
+	model.clocks = language.NewClocksMap(model.reference)
+	scope := newScope(nil, "")
+	declarations := `int a[4] = {1, 2, 3, 4};
+int ranged() { int s = 0; for (i : int[0,3]) s += a[i]; return s; }
+int counted() { int s = 0, i; for (i = 0; i < 4; i++) { s += a[i]; } return s; }
+int repeated(int n) { int s = 0; do { s++; n--; } while (n > 0); return s; }`
+
+	// Act
+	err := model.parse(scope, declarations, (*parser).declarations)
+	calls := []language.Expression{}
+	for _, call := range []string{"ranged()", "counted()", "repeated(0)", "repeated(3)"} {
+		expression, callErr := model.expression(scope, call)
+		err = errors.Join(err, callErr)
+		calls = append(calls, expression)
+	}
+
+	// Assert
+	assert.NoError(t, err)
+	concrete := language.NewConcreteInterpreter(model.variables, model.valuations)
+	assert.Equal(t, language.NewInteger(10), concrete.Interpret(calls[0]))
+	assert.Equal(t, language.NewInteger(10), concrete.Interpret(calls[1]))
+	assert.Equal(t, language.NewInteger(1), concrete.Interpret(calls[2]))
+	assert.Equal(t, language.NewInteger(3), concrete.Interpret(calls[3]))
+}
@@ -0,0 +1,588 @@
+package uppaal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Brandhoej/gobion/pkg/automata/language"
+	"github.com/Brandhoej/gobion/pkg/symbols"
+)
+
+type declarationKind uint16
+
+const (
+	variableDeclaration = declarationKind(iota)
+	clockDeclaration
+	constantDeclaration
+	channelDeclaration
+	functionDeclaration
+	// The parameters and local variables of functions.
+	localDeclaration
+)
+
+type declaration struct {
+	kind   declarationKind
+	symbol symbols.Symbol
+	sort   language.Sort
+	// The function of function declarations.
+	function language.Function
+}
+
+// The declarations of either the global declarations or the declarations of a process.
+// The names of the declarations are qualified by the prefix when they are registered as symbols.
+type scope struct {
+	parent       *scope
+	prefix       string
+	declarations map[string]declaration
+	// The names of the declarations in the order they are declared.
+	names []string
+}
+
+func newScope(parent *scope, prefix string) *scope {
+	return &scope{
+		parent:       parent,
+		prefix:       prefix,
+		declarations: map[string]declaration{},
+	}
+}
+
+// Returns the name qualified by the prefix of the scope.
+func (scope *scope) qualify(name string) string {
+	if scope.prefix == "" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", scope.prefix, name)
+}
+
+func (scope *scope) lookup(name string) (declaration, bool) {
+	for current := scope; current != nil; current = current.parent {
+		if declaration, exists := current.declarations[name]; exists {
+			return declaration, true
+		}
+	}
+	return declaration{}, false
+}
+
+// A parser of the UPPAAL declarations and labels where the expressions and assignments are parsed by the
+// language parser. Identifiers are resolved in the scope and constants are replaced by their values.
+type parser struct {
+	*language.Parser
+	model *Model
+	scope *scope
+	// The sorts of the parameters and local variables of the functions being parsed.
+	sorts *language.VariablesMap
+}
+
+// Parses the text by the rule and returns the ParseError if any.
+func (model *Model) parse(scope *scope, text string, rule func(parser *parser)) error {
+	base, err := language.NewParser(text, model.symbols, model.clocks)
+	if err != nil {
+		return err
+	}
+	parser := &parser{
+		Parser: base,
+		model:  model,
+		scope:  scope,
+		sorts:  language.NewVariablesMap(),
+	}
+	base.SetResolver(parser.resolve)
+	base.SetLookup(parser.lookup)
+	base.SetVariables(language.NewScopedVariables(parser.sorts, model.variables))
+	base.SetFunctions(model.functions)
+	base.SetConstants(model.constants)
+	return base.Parse(func(*language.Parser) {
+		rule(parser)
+	})
+}
+
+// Resolves the identifiers of expressions and statements which cannot be channels or functions which are not called.
+func (parser *parser) resolve(token language.Token) symbols.Symbol {
+	declaration, exists := parser.scope.lookup(token.Text)
+	if !exists {
+		parser.Fail(token, "undeclared identifier \"%s\"", token.Text)
+	}
+	switch declaration.kind {
+	case channelDeclaration:
+		parser.Fail(token, "the channel \"%s\" cannot be used in an expression", token.Text)
+	case functionDeclaration:
+		if !parser.Is("(") {
+			parser.Fail(token, "the function \"%s\" must be called", token.Text)
+		}
+	}
+	return declaration.symbol
+}
+
+// Looks up the variable of the name as the record of an identifier "r.f".
+func (parser *parser) lookup(name string) (symbols.Symbol, bool) {
+	declaration, exists := parser.scope.lookup(name)
+	if !exists || (declaration.kind != variableDeclaration && declaration.kind != localDeclaration) {
+		return 0, false
+	}
+	return declaration.symbol, true
+}
+
+// Returns the declaration of the variable of the identifier which may be followed by the fields ".f" of a record.
+func (parser *parser) variable(token language.Token) (declaration, bool) {
+	name, _, _ := strings.Cut(token.Text, ".")
+	return parser.scope.lookup(name)
+}
+
+// Declarations where the sorts of variables are those of the language parser:
+//
+//	declarations := { ["const"] {"urgent" | "broadcast"} type declarator {"," declarator} ";" | type function }
+//	type         := "clock" | "chan" | sort
+//	declarator   := identifier dimensions ["=" initialiser]
+//	initialiser  := expression | "{" initialiser {"," initialiser} "}"
+//	function     := identifier "(" [type identifier dimensions {"," ...}] ")" block
+func (parser *parser) declarations() {
+	for !parser.IsEnd(parser.Peek()) {
+		parser.declaration()
+	}
+}
+
+func (parser *parser) declaration() {
+	_, constant := parser.Accept("const")
+	for {
+		if _, ok := parser.Accept("urgent", "broadcast"); !ok {
+			break
+		}
+	}
+
+	typeToken := parser.Peek()
+	kind, sort := parser.declarationType()
+	if constant {
+		if kind != variableDeclaration || sort.Kind() == language.ArrayKind || sort.Kind() == language.RecordKind {
+			parser.Fail(typeToken, "only integers and booleans can be constants")
+		}
+		kind = constantDeclaration
+	}
+
+	for first := true; ; first = false {
+		name := parser.ExpectIdentifier()
+		if _, exists := parser.scope.declarations[name.Text]; exists {
+			parser.Fail(name, "\"%s\" is already declared", name.Text)
+		}
+		if parser.Is("(") {
+			if !first || constant || kind != variableDeclaration {
+				parser.Fail(name, "functions must return integers, booleans or structs")
+			}
+			parser.function(name, sort)
+			return
+		}
+		sort := sort
+		if parser.Is("[") {
+			if kind != variableDeclaration && kind != constantDeclaration {
+				parser.Fail(name, "only integers, booleans and structs can be arrays")
+			}
+			if kind == constantDeclaration {
+				parser.Fail(name, "constant arrays are not supported")
+			}
+			sort = parser.Dimensions(sort)
+		}
+
+		var initial language.Expression
+		if _, ok := parser.Accept("="); ok {
+			if kind == clockDeclaration || kind == channelDeclaration {
+				parser.Fail(name, "clocks and channels cannot be initialised")
+			}
+			initial = parser.initialiser(sort)
+		} else if kind == constantDeclaration {
+			parser.Fail(name, "the constant \"%s\" must be initialised", name.Text)
+		}
+
+		parser.model.declare(parser.scope, name.Text, kind, sort, initial)
+
+		if _, ok := parser.Accept(","); !ok {
+			break
+		}
+	}
+	parser.Expect(";")
+}
+
+// Returns the kind of the declarations of the type and the sort if they are variables.
+func (parser *parser) declarationType() (declarationKind, language.Sort) {
+	if parser.IsSort() {
+		return variableDeclaration, parser.Sort()
+	}
+	typeToken := parser.ExpectIdentifier()
+	switch typeToken.Text {
+	case "clock":
+		return clockDeclaration, language.IntegerSort
+	case "chan":
+		return channelDeclaration, language.IntegerSort
+	}
+	parser.Fail(typeToken, "unsupported type \"%s\"", typeToken.Text)
+	return variableDeclaration, language.Sort{}
+}
+
+// Declares the function whose parameters and local variables are qualified by the name of the function.
+// The function is declared after its body such that it cannot call itself.
+func (parser *parser) function(name language.Token, sort language.Sort) {
+	outer := parser.scope
+	parser.scope = newScope(outer, outer.qualify(name.Text))
+	defer func() {
+		parser.scope = outer
+	}()
+
+	parser.Expect("(")
+	parameters := []language.Parameter{}
+	if _, ok := parser.Accept(")"); !ok {
+		for {
+			start := parser.Peek()
+			kind, sort := parser.declarationType()
+			if kind != variableDeclaration {
+				parser.Fail(start, "parameters must be integers, booleans or structs")
+			}
+			if reference, ok := parser.Accept("&"); ok {
+				parser.Fail(reference, "reference parameters are not supported")
+			}
+			name := parser.ExpectIdentifier()
+			sort = parser.Dimensions(sort)
+			parameters = append(parameters, language.NewParameter(parser.local(name, sort), sort))
+			if _, ok := parser.Accept(","); !ok {
+				break
+			}
+		}
+		parser.Expect(")")
+	}
+	body := parser.block()
+	accesses := language.NewAccesses()
+	for _, statement := range body {
+		statement.Accept(accesses)
+	}
+	accesses.All(func(symbol symbols.Symbol) bool {
+		if _, exists := parser.model.clocks.Lookup(symbol); exists {
+			parser.Fail(name, "the function \"%s\" cannot use clocks", name.Text)
+		}
+		return true
+	})
+
+	symbol := parser.model.symbols.Insert(outer.qualify(name.Text))
+	function := language.NewFunction(symbol, sort, parameters, body...)
+	outer.declarations[name.Text] = declaration{
+		kind:     functionDeclaration,
+		symbol:   symbol,
+		sort:     sort,
+		function: function,
+	}
+	outer.names = append(outer.names, name.Text)
+	parser.model.functions.Declare(function)
+}
+
+// Declares the parameter or local variable in the scope of the function being parsed.
+func (parser *parser) local(name language.Token, sort language.Sort) symbols.Symbol {
+	if _, exists := parser.scope.declarations[name.Text]; exists {
+		parser.Fail(name, "\"%s\" is already declared", name.Text)
+	}
+	parser.model.declare(parser.scope, name.Text, localDeclaration, sort, nil)
+	symbol := parser.scope.declarations[name.Text].symbol
+	parser.sorts.Declare(symbol, sort)
+	return symbol
+}
+
+// Statements of the bodies of functions where blocks are scopes of their local variables:
+//
+//	block     := "{" {statement} "}"
+//	statement := block | ";" | "return" expression ";"
+//	           | type identifier dimensions ["=" initialiser] {"," ...} ";"
+//	           | "if" "(" expression ")" statement ["else" statement]
+//	           | "while" "(" expression ")" statement
+//	           | "do" statement "while" "(" expression ")" ";"
+//	           | loop
+//	           | update ";"
+func (parser *parser) block() []language.Statement {
+	parser.Expect("{")
+	outer := parser.scope
+	parser.scope = newScope(outer, outer.prefix)
+	defer func() {
+		parser.scope = outer
+	}()
+
+	statements := []language.Statement{}
+	for {
+		if _, ok := parser.Accept("}"); ok {
+			return statements
+		}
+		statements = append(statements, parser.statement()...)
+	}
+}
+
+func (parser *parser) statement() []language.Statement {
+	switch {
+	case parser.Is("{"):
+		return []language.Statement{language.NewSequence(parser.block()...)}
+	case parser.Is(";"):
+		parser.Next()
+		return nil
+	case parser.Is("if"):
+		parser.Next()
+		parser.Expect("(")
+		condition := parser.Expression()
+		parser.Expect(")")
+		consequence := parser.branch()
+		var alternative []language.Statement
+		if _, ok := parser.Accept("else"); ok {
+			alternative = parser.branch()
+		}
+		return []language.Statement{language.NewIf(condition, consequence, alternative)}
+	case parser.Is("while"):
+		parser.Next()
+		parser.Expect("(")
+		condition := parser.Expression()
+		parser.Expect(")")
+		return []language.Statement{language.NewWhile(condition, parser.branch()...)}
+	case parser.Is("do"):
+		// The body is executed once before it is executed as long as the condition is true.
+		parser.Next()
+		body := parser.branch()
+		parser.Expect("while")
+		parser.Expect("(")
+		condition := parser.Expression()
+		parser.Expect(")")
+		parser.Expect(";")
+		return []language.Statement{language.NewSequence(append(body, language.NewWhile(condition, body...))...)}
+	case parser.Is("for"):
+		return []language.Statement{parser.loop()}
+	case parser.Is("return"):
+		parser.Next()
+		value := parser.Expression()
+		parser.Expect(";")
+		return []language.Statement{language.NewReturn(value)}
+	case parser.Is("const", "clock", "chan") || parser.IsSort():
+		return parser.locals()
+	}
+
+	update := parser.assignment()
+	parser.Expect(";")
+	return []language.Statement{update}
+}
+
+// Returns the statements of the body of a control statement where a block is not a nested scope.
+func (parser *parser) branch() []language.Statement {
+	if parser.Is("{") {
+		return parser.block()
+	}
+	return parser.statement()
+}
+
+// Returns the update of a parameter or local variable of a function.
+func (parser *parser) assignment() language.Statement {
+	start := parser.Peek()
+	if declaration, exists := parser.variable(start); exists && declaration.kind != localDeclaration {
+		parser.Fail(start, "functions can only assign their parameters and local variables")
+	}
+	return parser.update()
+}
+
+// Returns the iteration "for (i : int[l,u]) s" over a constant range or the loop "for (init; condition; step) s"
+// as the sequence of the initialisation and a while loop of the body followed by the step.
+//
+//	loop := "for" "(" identifier ":" "int" "[" expression "," expression "]" ")" statement
+//	      | "for" "(" [update {"," update}] ";" [expression] ";" [update {"," update}] ")" statement
+func (parser *parser) loop() language.Statement {
+	parser.Expect("for")
+	parser.Expect("(")
+	outer := parser.scope
+	parser.scope = newScope(outer, outer.prefix)
+	defer func() {
+		parser.scope = outer
+	}()
+
+	if next := parser.Lookahead(1); parser.Peek().Kind == language.IdentifierToken &&
+		next.Kind == language.PunctuationToken && next.Text == ":" {
+		name := parser.ExpectIdentifier()
+		parser.Expect(":")
+		typeToken := parser.Peek()
+		_, sort := parser.declarationType()
+		lower, upper, bounded := sort.Bounds()
+		if typeToken.Text != "int" || !bounded {
+			parser.Fail(typeToken, "the range of \"%s\" must be a range of integers", name.Text)
+		}
+		parser.Expect(")")
+		symbol := parser.local(name, language.IntegerSort)
+		return language.NewFor(symbol, lower, upper, parser.branch()...)
+	}
+
+	updates := func(end string) []language.Statement {
+		statements := []language.Statement{}
+		if parser.Is(end) {
+			return statements
+		}
+		for {
+			statements = append(statements, parser.assignment())
+			if _, ok := parser.Accept(","); !ok {
+				return statements
+			}
+		}
+	}
+	initialisation := updates(";")
+	parser.Expect(";")
+	condition := language.Expression(language.NewTrue())
+	if !parser.Is(";") {
+		condition = parser.Expression()
+	}
+	parser.Expect(";")
+	step := updates(")")
+	parser.Expect(")")
+	body := append(parser.branch(), step...)
+	return language.NewSequence(append(initialisation, language.NewWhile(condition, body...))...)
+}
+
+// Returns the declarations of the local variables of a function.
+func (parser *parser) locals() []language.Statement {
+	start := parser.Peek()
+	kind, sort := parser.declarationType()
+	if kind != variableDeclaration {
+		parser.Fail(start, "local variables must be integers, booleans or structs")
+	}
+	declarations := []language.Statement{}
+	for {
+		name := parser.ExpectIdentifier()
+		sort := parser.Dimensions(sort)
+		var initial language.Expression
+		if _, ok := parser.Accept("="); ok {
+			if sort.Kind() == language.ArrayKind || sort.Kind() == language.RecordKind {
+				initial = parser.initialiser(sort)
+			} else {
+				initial = parser.Expression()
+			}
+		}
+		declarations = append(declarations, language.NewDeclaration(parser.local(name, sort), sort, initial))
+		if _, ok := parser.Accept(","); !ok {
+			break
+		}
+	}
+	parser.Expect(";")
+	return declarations
+}
+
+// Returns the constant initial value of the sort where arrays and structs are initialised by "{...}".
+func (parser *parser) initialiser(sort language.Sort) language.Expression {
+	switch sort.Kind() {
+	case language.ArrayKind:
+		brace := parser.Expect("{")
+		element, length := sort.Element()
+		elements := make([]language.Expression, 0, length)
+		for {
+			elements = append(elements, parser.initialiser(element))
+			if _, ok := parser.Accept(","); !ok {
+				break
+			}
+		}
+		parser.Expect("}")
+		if len(elements) != length {
+			parser.Fail(brace, "expected %d elements but found %d", length, len(elements))
+		}
+		return language.NewArray(elements...)
+	case language.RecordKind:
+		brace := parser.Expect("{")
+		fields := sort.Fields()
+		values := make([]language.Expression, 0, len(fields))
+		for {
+			if len(values) == len(fields) {
+				parser.Fail(brace, "expected %d fields", len(fields))
+			}
+			values = append(values, parser.initialiser(fields[len(values)].Sort()))
+			if _, ok := parser.Accept(","); !ok {
+				break
+			}
+		}
+		parser.Expect("}")
+		if len(values) != len(fields) {
+			parser.Fail(brace, "expected %d fields but found %d", len(fields), len(values))
+		}
+		return language.NewRecord(sort, values...)
+	}
+	return parser.Constant()
+}
+
+// Updates separated by commas where the assignments are those of the language parser:
+//
+//	updates := [update {"," update}]
+//	update  := identifier {selector} (("=" | ":=" | "+=" | "-=") expression | "++" | "--")
+func (parser *parser) updates() language.Expression {
+	statements := make([]language.Statement, 0)
+	for !parser.IsEnd(parser.Peek()) {
+		statements = append(statements, parser.update())
+		if _, ok := parser.Accept(","); !ok {
+			break
+		}
+	}
+	if len(statements) == 0 {
+		return language.NewTrue()
+	}
+	return language.NewBlockExpression(language.NewTrue(), statements...)
+}
+
+func (parser *parser) update() language.Statement {
+	name := parser.Peek()
+	if declaration, exists := parser.variable(name); exists && declaration.kind != variableDeclaration &&
+		declaration.kind != localDeclaration && declaration.kind != clockDeclaration {
+		parser.Fail(name, "cannot assign to \"%s\"", name.Text)
+	}
+	return parser.Statement()
+}
+
+// Synchronisations on channels:
+//
+//	synchronisation := identifier ("!" | "?")
+func (parser *parser) synchronisation() (channel symbols.Symbol, input bool) {
+	name := parser.ExpectIdentifier()
+	declaration, exists := parser.scope.lookup(name.Text)
+	if !exists {
+		parser.Fail(name, "undeclared identifier \"%s\"", name.Text)
+	}
+	if declaration.kind != channelDeclaration {
+		parser.Fail(name, "\"%s\" is not a channel", name.Text)
+	}
+	if _, ok := parser.Accept("?"); ok {
+		return declaration.symbol, true
+	}
+	parser.Expect("!")
+	return declaration.symbol, false
+}
+
+// The system declaration consists of declarations, instantiations of templates and the processes of the system:
+//
+//	system        := {declaration | instantiation} "system" identifier {("," | "<") identifier} ";"
+//	instantiation := identifier ("=" | ":=") identifier "(" ")" ";"
+func (parser *parser) system() (processes []process) {
+	instantiations := map[string]process{}
+	for {
+		if _, ok := parser.Accept("system"); ok {
+			break
+		}
+		if next := parser.Lookahead(1); parser.Peek().Kind == language.IdentifierToken &&
+			next.Kind == language.PunctuationToken && (next.Text == "=" || next.Text == ":=") {
+			name := parser.ExpectIdentifier()
+			parser.Next()
+			template := parser.ExpectIdentifier()
+			parser.Expect("(")
+			if !parser.Is(")") {
+				parser.Fail(parser.Peek(), "parameterised templates are not supported")
+			}
+			parser.Expect(")")
+			parser.Expect(";")
+			instantiations[name.Text] = process{name: name.Text, template: template.Text, position: name.Position}
+			continue
+		}
+		if parser.IsEnd(parser.Peek()) {
+			parser.Fail(parser.Peek(), "expected \"system\" but found %s", parser.Peek())
+		}
+		parser.declaration()
+	}
+
+	for {
+		name := parser.ExpectIdentifier()
+		if instantiation, exists := instantiations[name.Text]; exists {
+			processes = append(processes, instantiation)
+		} else {
+			processes = append(processes, process{name: name.Text, template: name.Text, position: name.Position})
+		}
+		if _, ok := parser.Accept(",", "<"); !ok {
+			break
+		}
+	}
+	parser.Expect(";")
+	return processes
+}
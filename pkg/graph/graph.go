@@ -36,6 +36,10 @@ func (graph *LabeledDirected[K, E, V]) Vertices(yield func(K, V) bool) {
 	graph.vertices.All(yield)
 }
 
+func (graph *LabeledDirected[K, E, V]) Edges(yield func(E) bool) {
+	graph.edges.All(yield)
+}
+
 func (graph *LabeledDirected[K, E, V]) AddVertex(vertex V, key K) K {
 	return graph.vertices.Add(vertex, key)
 }
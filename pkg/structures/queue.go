@@ -11,12 +11,7 @@ func (queue *Queue[T]) Enqueue(elements ...T) {
 }
 
 func (queue *Queue[T]) Dequeue() T {
-	index := 0
-	front := (*queue)[index]
-	if len(*queue) > 1 {
-		*queue = (*queue)[:index+1]
-	} else {
-		*queue = []T{}
-	}
+	front := (*queue)[0]
+	*queue = (*queue)[1:]
 	return front
 }
@@ -53,6 +53,22 @@ func (dbm DBM) Copy() DBM {
 	}
 }
 
+// Returns a copy of the DBM where each clock is renamed to the clock at its index in the permutation.
+func (dbm DBM) Permute(permutation []Clock) DBM {
+	permuted := dbm.Copy()
+	for row := Reference; row < dbm.clocks; row++ {
+		for column := Reference; column < dbm.clocks; column++ {
+			permuted.Constrain(permutation[row], permutation[column], dbm.Constraint(row, column))
+		}
+	}
+	return permuted
+}
+
+// Returns the number of clocks in the DBM including the reference clock.
+func (dbm DBM) Clocks() Clock {
+	return dbm.clocks
+}
+
 // Uses the row-wise indexing and not the layered approach since we have the clock set in the DBM.
 // Eg. 3 clocks (including the reference clock) DBM indexing "(row; column)-index":
 //
@@ -133,6 +149,47 @@ func (lhs DBM) Intersection(rhs DBM, from, to Clock) bool {
 	return true
 }
 
+// Returns the zones of the lhs which are not in the rhs. The zones are disjoint and their
+// union is the difference "lhs \ rhs". Both DBMs are expected to be closed.
+func (lhs DBM) Subtraction(rhs DBM) []DBM {
+	differences := make([]DBM, 0)
+	if !lhs.IsConsistent() {
+		return differences
+	}
+	if !rhs.IsConsistent() {
+		return append(differences, lhs.Copy())
+	}
+
+	remainder := lhs.Copy()
+	for row := Reference; row < lhs.clocks; row++ {
+		for column := Reference; column < lhs.clocks; column++ {
+			if row == column {
+				continue
+			}
+
+			relation := rhs.Constraint(row, column)
+			if relation.IsInfinity() || remainder.Constraint(row, column).LE(relation) {
+				continue
+			}
+
+			// The part of the remainder which violates the constraint is outside the rhs.
+			difference := remainder.Copy()
+			difference.ConstrainAndClose(column, row, relation.Negation())
+			if difference.IsConsistent() {
+				differences = append(differences, difference)
+			}
+
+			// The remaining part satisfies the constraint and is handled by the next constraints.
+			remainder.ConstrainAndClose(row, column, relation)
+			if !remainder.IsConsistent() {
+				return differences
+			}
+		}
+	}
+
+	return differences
+}
+
 // Makes the lhs the convex union of the clocks [from, to] and returns true
 // if there actually is a valid intersection. Otherwise, false.
 func (lhs DBM) ConvexUnion(rhs DBM, from, to Clock) {
@@ -166,52 +223,26 @@ func (dbm DBM) ConstrainAndClose(row, column Clock, element Relation) {
 	}
 }
 
-// Closes the DBM by recomputing all affected shortest paths.
-// This is down by potentially updating the following three paths:
-//   - row -> column -> i => row -> i
-//   - i -> row -> column => i -> column
-//   - i -> column -> j => i -> j
+// Closes the DBM by recomputing all shortest paths affected by the constraint
+// between row and column. This is done by potentially updating the paths:
+//   - i -> row -> column -> j => i -> j
 //
 // Where "i" and "j" are both clocks (Including the reference clock).
 func (dbm DBM) CloseRowColumn(row, column Clock) {
-	if dbm.clocks <= 2 {
-		return
-	}
-
 	pathRC := dbm.Constraint(row, column)
-
-	// Computes the path from "row" to a clock "i" through "column".
-	// row -> column -> i => row -> i
-	for i := Reference; i < dbm.clocks; i++ {
-		pathCI := dbm.Constraint(column, i)
-		if pathCI.IsInfinity() {
-			continue
-		}
-
-		// Check if "row -> c" is shorter through "column".
-		pathRC := pathRC.Add(pathCI)
-		if dbm.Constraint(row, i) > pathRC {
-			dbm.Constrain(row, i, pathRC)
-		}
+	if pathRC.IsInfinity() {
+		return
 	}
 
-	// Computes the path from a clock "i" to "column" through "row".
-	// i -> row -> column => i -> column
-	// i -> column -> j => i -> j
 	for i := Reference; i < dbm.clocks; i++ {
-		// i -> r
+		// i -> row
 		pathIR := dbm.Constraint(i, row)
 		if pathIR.IsInfinity() {
 			continue
 		}
 
-		// i -> row -> column => i -> column
-		pathIC := pathIR.Add(pathRC)
-		if dbm.Constraint(i, column).LE(pathIC) {
-			continue
-		}
-		dbm.Constrain(i, column, pathIC)
-
+		// i -> row -> column
+		pathIRC := pathIR.Add(pathRC)
 		for j := Reference; j < dbm.clocks; j++ {
 			// column -> j
 			pathCJ := dbm.Constraint(column, j)
@@ -219,8 +250,8 @@ func (dbm DBM) CloseRowColumn(row, column Clock) {
 				continue
 			}
 
-			// i -> column -> j => i -> j
-			pathIJ := pathIC.Add(pathCJ)
+			// i -> row -> column -> j => i -> j
+			pathIJ := pathIRC.Add(pathCJ)
 			if dbm.Constraint(i, j).GT(pathIJ) {
 				dbm.Constrain(i, j, pathIJ)
 			}
@@ -252,10 +283,12 @@ func (dbm DBM) Close() {
 				if pathIJ.GT(pathIKJ) {
 					dbm.Constrain(i, j, pathIKJ)
 				}
+			}
 
-				if dbm.Constraint(i, j).LT(Zero) {
-					dbm.Empty()
-				}
+			// A negative cycle means that the clock must be behind itself.
+			if dbm.Diagonal(i).LT(Zero) {
+				dbm.Empty()
+				return
 			}
 		}
 	}
@@ -534,24 +567,76 @@ func (dbm DBM) Shift(clock Clock, limit int) {
 	}
 }
 
-// Removes all upper bounds higher than the maximal constants and lower
-// all lower bounds higher than the maximal constants down to the maximal constants.
+// Extrapolates the DBM by the maximal constants of the clocks (Extra_M). Constraints
+// higher than the maximal constant of the row clock are removed and constraints lower
+// than the negated maximal constant of the column clock are relaxed to it. The maximums
+// are for the clocks excluding the reference clock.
 //
 // If checking safety properties then this can only be used if there are NO difference constraints.
 func (dbm DBM) Norm(maximums ...int) {
-	for i := Clock(1); i < dbm.clocks; i++ {
-		pos := NewRelation(maximums[i-1], Weak)
-		neg := NewRelation(maximums[i-1], Strict)
+	if !dbm.IsConsistent() {
+		return
+	}
 
-		if dbm.Upper(i).GT(pos) {
-			dbm.SetUpper(i, Infinity)
+	maximum := func(clock Clock) int {
+		if clock == Reference {
+			return 0
 		}
+		return maximums[clock-1]
+	}
 
-		if dbm.Lower(i).GT(neg) {
-			dbm.SetLower(i, neg)
+	for i := Reference; i < dbm.clocks; i++ {
+		for j := Reference; j < dbm.clocks; j++ {
+			if i == j {
+				continue
+			}
+
+			constraint := dbm.Constraint(i, j)
+			if constraint.IsInfinity() {
+				continue
+			}
+
+			if constraint.GT(NewRelation(maximum(i), Weak)) {
+				dbm.Constrain(i, j, Infinity)
+			} else if constraint.LT(NewRelation(-maximum(j), Strict)) {
+				dbm.Constrain(i, j, NewRelation(-maximum(j), Strict))
+			}
 		}
+	}
+
+	dbm.Close()
+}
 
-		for j := Clock(1); j < dbm.clocks; j++ {
+// Extrapolates the DBM by the maximal lower and upper bounds of the clocks (Extra+_LU).
+// The lower bounds are the maximal constants the clocks are compared to from below "x > c"
+// and the upper bounds are the maximal constants the clocks are compared to from above "x < c".
+// The bounds are for the clocks excluding the reference clock. The result is coarser
+// than the extrapolation by the maximal constants but preserves reachability.
+//
+// If checking safety properties then this can only be used if there are NO difference constraints.
+func (dbm DBM) ExtraLU(lowers, uppers []int) {
+	if !dbm.IsConsistent() {
+		return
+	}
+
+	bound := func(bounds []int, clock Clock) int {
+		if clock == Reference {
+			return 0
+		}
+		return bounds[clock-1]
+	}
+
+	// The clocks whose lower bound in the zone exceeds their lower or upper bound.
+	// These are determined before the zone is extrapolated.
+	aboveLower, aboveUpper := make([]bool, dbm.clocks), make([]bool, dbm.clocks)
+	for clock := Clock(1); clock < dbm.clocks; clock++ {
+		lower := dbm.Lower(clock)
+		aboveLower[clock] = lower.LT(NewRelation(-bound(lowers, clock), Weak))
+		aboveUpper[clock] = lower.LT(NewRelation(-bound(uppers, clock), Weak))
+	}
+
+	for i := Reference; i < dbm.clocks; i++ {
+		for j := Reference; j < dbm.clocks; j++ {
 			if i == j {
 				continue
 			}
@@ -561,10 +646,21 @@ func (dbm DBM) Norm(maximums ...int) {
 				continue
 			}
 
-			if constraint.GT(pos) {
-				dbm.Constrain(i, j, Infinity)
-			} else if constraint.LT(neg) {
-				dbm.Constrain(i, j, neg)
+			if i != Reference {
+				// The clock is above every lower bound it is compared to or
+				// the constraint is higher than any of its lower bounds.
+				if constraint.GT(NewRelation(bound(lowers, i), Weak)) || aboveLower[i] {
+					dbm.Constrain(i, j, Infinity)
+					continue
+				}
+			}
+
+			if j != Reference && aboveUpper[j] {
+				if i == Reference {
+					dbm.Constrain(i, j, NewRelation(-bound(uppers, j), Strict))
+				} else {
+					dbm.Constrain(i, j, Infinity)
+				}
 			}
 		}
 	}
@@ -163,3 +163,93 @@ func Test_bar(t *testing.T) {
 	fmt.Println(buffer.String())
 	t.Fail()
 }
+
+func Test_CloseLowerBound(t *testing.T) {
+	// Arrange
+	dbm := fig11()
+
+	// Act
+	dbm.Close()
+
+	// Assert
+	assert.True(t, dbm.IsConsistent())
+	assert.Equal(t, NewRelation(-1, Strict), dbm.Lower(Clock(1)))
+}
+
+func Test_Fig11Subtraction(t *testing.T) {
+	// Arrange
+	dbm := fig11()
+	dbm.Close()
+	rhs := NewDBM(Clock(1+2), Infinity)
+	rhs.ConstrainAndClose(Clock(1), Reference, NewRelation(2, Weak))
+
+	// Act
+	differences := dbm.Subtraction(rhs)
+
+	// Assert
+	if assert.Len(t, differences, 1) {
+		assert.Equal(t, NewRelation(-2, Strict), differences[0].Lower(Clock(1)))
+		assert.Equal(t, NewRelation(3, Strict), differences[0].Upper(Clock(1)))
+	}
+	assert.Empty(t, dbm.Subtraction(dbm))
+}
+
+func Test_Norm(t *testing.T) {
+	// Arrange
+	dbm := NewDBM(Clock(1+1), Infinity)
+	dbm.ConstrainAndClose(Reference, Clock(1), NewRelation(-3, Weak))
+	dbm.ConstrainAndClose(Clock(1), Reference, NewRelation(5, Weak))
+
+	// Act
+	dbm.Norm(2)
+
+	// Assert
+	assert.Equal(t, NewRelation(-2, Strict), dbm.Lower(Clock(1)))
+	assert.True(t, dbm.Upper(Clock(1)).IsInfinity())
+}
+
+func Test_ExtraLU(t *testing.T) {
+	tests := []struct {
+		name          string
+		lower, upper  int
+		expectedLower Relation
+		expectedUpper Relation
+	}{
+		{
+			name:          "3 ≤ x ≤ 5 with L=2 and U=10 is x ≥ 3",
+			lower:         2,
+			upper:         10,
+			expectedLower: NewRelation(-3, Weak),
+			expectedUpper: Infinity,
+		},
+		{
+			name:          "3 ≤ x ≤ 5 with L=10 and U=2 is 2 < x ≤ 5",
+			lower:         10,
+			upper:         2,
+			expectedLower: NewRelation(-2, Strict),
+			expectedUpper: NewRelation(5, Weak),
+		},
+		{
+			name:          "3 ≤ x ≤ 5 with L=10 and U=10 is 3 ≤ x ≤ 5",
+			lower:         10,
+			upper:         10,
+			expectedLower: NewRelation(-3, Weak),
+			expectedUpper: NewRelation(5, Weak),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dbm := NewDBM(Clock(1+1), Infinity)
+			dbm.ConstrainAndClose(Reference, Clock(1), NewRelation(-3, Weak))
+			dbm.ConstrainAndClose(Clock(1), Reference, NewRelation(5, Weak))
+
+			// Act
+			dbm.ExtraLU([]int{tt.lower}, []int{tt.upper})
+
+			// Assert
+			assert.Equal(t, tt.expectedLower, dbm.Lower(Clock(1)))
+			assert.Equal(t, tt.expectedUpper, dbm.Upper(Clock(1)))
+		})
+	}
+}
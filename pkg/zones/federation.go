@@ -1,10 +1,161 @@
 package zones
 
+// A finite union of zones over the same clocks. Federations are used to represent
+// the non-convex sets of clock valuations which a single DBM cannot represent.
+// All zones of the federation are expected to be closed and consistent.
 type Federation struct {
 	clocks Clock
 	zones  []DBM
 }
 
-func (federation Federation) Foo() {
+// Constructs a federation of the consistent zones.
+func NewFederation(clocks Clock, zones ...DBM) Federation {
+	federation := Federation{
+		clocks: clocks,
+		zones:  make([]DBM, 0, len(zones)),
+	}
+	for _, zone := range zones {
+		federation.add(zone)
+	}
+	return federation
+}
+
+func (federation *Federation) add(zone DBM) {
+	if zone.clocks != federation.clocks {
+		panic("Zone and federation have different clocks")
+	}
+	if zone.IsConsistent() {
+		federation.zones = append(federation.zones, zone)
+	}
+}
+
+// Returns the number of clocks in the federation including the reference clock.
+func (federation Federation) Clocks() Clock {
+	return federation.clocks
+}
+
+// Returns the zones of the federation.
+func (federation Federation) Zones() []DBM {
+	return federation.zones
+}
+
+// Creates a new copy of the federation where all zones are copied.
+func (federation Federation) Copy() Federation {
+	zones := make([]DBM, len(federation.zones))
+	for idx := range federation.zones {
+		zones[idx] = federation.zones[idx].Copy()
+	}
+	return Federation{
+		clocks: federation.clocks,
+		zones:  zones,
+	}
+}
+
+// Returns true if the federation contains no clock valuations.
+func (federation Federation) IsEmpty() bool {
+	for _, zone := range federation.zones {
+		if zone.IsConsistent() {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the union "lhs ∪ rhs" of the federations.
+func (lhs Federation) Union(rhs Federation) Federation {
+	union := lhs.Copy()
+	for _, zone := range rhs.zones {
+		union.add(zone.Copy())
+	}
+	return union
+}
+
+// Returns the intersection "lhs ∩ rhs" of the federations
+// which consists of the pairwise intersections of their zones.
+func (lhs Federation) Intersection(rhs Federation) Federation {
+	intersection := NewFederation(lhs.clocks)
+	for _, lhsZone := range lhs.zones {
+		for _, rhsZone := range rhs.zones {
+			zone := lhsZone.Copy()
+			if !zone.Intersection(rhsZone, Reference, lhs.clocks) {
+				continue
+			}
+			zone.Close()
+			intersection.add(zone)
+		}
+	}
+	return intersection
+}
+
+// Returns the subtraction "lhs \ rhs" of the federations.
+func (lhs Federation) Subtraction(rhs Federation) Federation {
+	remaining := lhs.Copy()
+	for _, rhsZone := range rhs.zones {
+		differences := NewFederation(lhs.clocks)
+		for _, zone := range remaining.zones {
+			for _, difference := range zone.Subtraction(rhsZone) {
+				differences.add(difference)
+			}
+		}
+		remaining = differences
+	}
+	return remaining
+}
+
+// Computes the relation lhs has to rhs. If subset is true
+// then lhs ⊆ rhs and if superset is true then lhs ⊇ rhs.
+// Implied is that if both are true then lhs = rhs.
+func (lhs Federation) Relation(rhs Federation) (subset bool, superset bool) {
+	return lhs.Subtraction(rhs).IsEmpty(), rhs.Subtraction(lhs).IsEmpty()
+}
+
+// Returns true if all clock valuations of the zone are in the federation.
+func (federation Federation) Includes(zone DBM) bool {
+	subset, _ := NewFederation(federation.clocks, zone).Relation(federation)
+	return subset
+}
+
+// Removes the zones which are included in another zone of the federation.
+// The federation still represents the same clock valuations afterwards.
+func (federation Federation) Reduction() Federation {
+	reduced := NewFederation(federation.clocks)
+	for idx, zone := range federation.zones {
+		included := false
+		for other := range federation.zones {
+			if idx == other {
+				continue
+			}
+			subset, superset := zone.Relation(federation.zones[other], Reference, federation.clocks)
+			// Of two equal zones only the first is kept.
+			if subset && (!superset || other < idx) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			reduced.add(zone)
+		}
+	}
+	return reduced
+}
+
+// Applies the up operation on all zones of the federation.
+func (federation Federation) Up() {
+	for _, zone := range federation.zones {
+		zone.Up()
+	}
+}
+
+// Applies the down operation on all zones of the federation.
+func (federation Federation) Down() {
+	for _, zone := range federation.zones {
+		zone.Down()
+	}
+}
 
+// Resets the clock to the limit in all zones of the federation.
+func (federation Federation) Reset(clock Clock, limit int) {
+	for _, zone := range federation.zones {
+		zone.Reset(clock, limit)
+	}
 }
@@ -0,0 +1,93 @@
+package zones
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the zone "lower ≤ x ≤ upper" over a single clock.
+func interval(lower, upper int) DBM {
+	dbm := NewDBM(Clock(1+1), Infinity)
+	dbm.ConstrainAndClose(Reference, Clock(1), NewRelation(-lower, Weak))
+	dbm.ConstrainAndClose(Clock(1), Reference, NewRelation(upper, Weak))
+	return dbm
+}
+
+func Test_FederationUnion(t *testing.T) {
+	// Arrange
+	lhs := NewFederation(Clock(2), interval(0, 2))
+	rhs := NewFederation(Clock(2), interval(4, 6))
+
+	// Act
+	union := lhs.Union(rhs)
+
+	// Assert
+	assert.Len(t, union.Zones(), 2)
+	assert.True(t, union.Includes(interval(1, 2)))
+	assert.True(t, union.Includes(interval(4, 5)))
+	assert.False(t, union.Includes(interval(2, 4)))
+}
+
+func Test_FederationIntersection(t *testing.T) {
+	// Arrange
+	lhs := NewFederation(Clock(2), interval(0, 2), interval(4, 6))
+	rhs := NewFederation(Clock(2), interval(1, 5))
+
+	// Act
+	intersection := lhs.Intersection(rhs)
+
+	// Assert
+	subset, superset := intersection.Relation(
+		NewFederation(Clock(2), interval(1, 2), interval(4, 5)),
+	)
+	assert.True(t, subset)
+	assert.True(t, superset)
+}
+
+func Test_FederationSubtraction(t *testing.T) {
+	// Arrange
+	lhs := NewFederation(Clock(2), interval(0, 6))
+	rhs := NewFederation(Clock(2), interval(2, 4))
+
+	// Act
+	subtraction := lhs.Subtraction(rhs)
+
+	// Assert
+	assert.False(t, subtraction.IsEmpty())
+	assert.True(t, subtraction.Includes(interval(0, 1)))
+	assert.True(t, subtraction.Includes(interval(5, 6)))
+	assert.True(t, subtraction.Intersection(rhs).IsEmpty())
+	assert.True(t, lhs.Subtraction(lhs).IsEmpty())
+}
+
+func Test_FederationReduction(t *testing.T) {
+	// Arrange
+	federation := NewFederation(Clock(2), interval(0, 6), interval(2, 4), interval(0, 6))
+
+	// Act
+	reduced := federation.Reduction()
+
+	// Assert
+	assert.Len(t, reduced.Zones(), 1)
+	subset, superset := reduced.Relation(federation)
+	assert.True(t, subset)
+	assert.True(t, superset)
+}
+
+func Test_FederationUpDown(t *testing.T) {
+	// Arrange
+	federation := NewFederation(Clock(2), interval(2, 4))
+
+	// Act
+	up := federation.Copy()
+	up.Up()
+	down := federation.Copy()
+	down.Down()
+
+	// Assert
+	assert.True(t, up.Includes(interval(2, 100)))
+	assert.False(t, up.Includes(interval(1, 2)))
+	assert.True(t, down.Includes(interval(0, 4)))
+	assert.False(t, down.Includes(interval(4, 5)))
+}